@@ -0,0 +1,131 @@
+// cmd/terraform-mcp-server/lint.go
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"terraform-mcp-server/pkg/hashicorp"
+	"terraform-mcp-server/pkg/hashicorp/tfdocs"
+)
+
+// Exit codes of the lint command
+const (
+	lintExitOK     = 0
+	lintExitIssues = 1
+	lintExitError  = 2
+)
+
+// runLint validates the Terraform modules found in the given directories and
+// returns the exit code
+func runLint(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: terraform-mcp-server lint [flags] [dir...]\n\n")
+		fmt.Fprintf(stderr, "Validates every Terraform module found in the given directories (default: current directory).\n\n")
+		flags.PrintDefaults()
+	}
+
+	format := flags.String("format", "text", "Output format (text, json, sarif, junit, checkstyle)")
+	threshold := flags.String("severity-threshold", "error", "Exit non-zero if an issue at or above this severity is found (error, warning, info, none)")
+	policyDir := flags.String("policy-dir", "", "Directory of YAML policy rules")
+	regoPolicyDir := flags.String("rego-policy-dir", "", "Directory of Rego policies")
+	schemaDir := flags.String("schema-dir", "", "Directory of provider schemas from terraform providers schema -json")
+	verbose := flags.Bool("verbose", false, "Log progress to stderr")
+	if err := flags.Parse(args); err != nil {
+		return lintExitError
+	}
+
+	outputFormat, err := tfdocs.ParseOutputFormat(*format)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return lintExitError
+	}
+	if *threshold != "none" && !isSeverity(*threshold) {
+		fmt.Fprintf(stderr, "Error: invalid severity threshold: %s\n", *threshold)
+		return lintExitError
+	}
+
+	logOutput := ioutil.Discard
+	if *verbose {
+		logOutput = stderr
+	}
+	logger := &hashicorp.DefaultLogger{
+		Logger: log.New(logOutput, "terraform-mcp: ", log.LstdFlags),
+	}
+
+	engine := tfdocs.NewValidationEngine(nil, logger,
+		tfdocs.WithPolicyPath(*policyDir),
+		tfdocs.WithRegoPolicyPath(*regoPolicyDir),
+		tfdocs.WithSchemaPath(*schemaDir),
+	)
+	if err := engine.Initialize(context.Background()); err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return lintExitError
+	}
+
+	// Discover the modules of every directory
+	dirs := flags.Args()
+	if len(dirs) == 0 {
+		dirs = []string{"."}
+	}
+	var modules []*tfdocs.ModuleDirectory
+	for _, dir := range dirs {
+		found, err := tfdocs.DiscoverModules(dir)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return lintExitError
+		}
+		for _, module := range found {
+			module.Path = path.Join(filepath.ToSlash(filepath.Clean(dir)), module.Path)
+		}
+		modules = append(modules, found...)
+	}
+	logger.Info("Discovered modules", "count", len(modules))
+
+	result, config, err := engine.ValidateModules(modules)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return lintExitError
+	}
+
+	output, err := tfdocs.FormatReport(outputFormat, result, engine.Rules(), config)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return lintExitError
+	}
+	fmt.Fprintln(stdout, strings.TrimRight(output, "\n"))
+
+	if *threshold == "none" {
+		return lintExitOK
+	}
+	for _, issue := range result.Issues {
+		if tfdocs.SeverityAtLeast(issue.Severity, tfdocs.ValidationSeverity(*threshold)) {
+			return lintExitIssues
+		}
+	}
+	return lintExitOK
+}
+
+// isSeverity reports whether a name is a known severity
+func isSeverity(name string) bool {
+	switch tfdocs.ValidationSeverity(name) {
+	case tfdocs.SeverityError, tfdocs.SeverityWarning, tfdocs.SeverityInfo:
+		return true
+	}
+	return false
+}
+
+// lintMain runs the lint command and exits with its exit code
+func lintMain(args []string) {
+	os.Exit(runLint(args, os.Stdout, os.Stderr))
+}
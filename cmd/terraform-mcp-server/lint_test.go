@@ -0,0 +1,142 @@
+// cmd/terraform-mcp-server/lint_test.go
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// lintModule is a module without errors or warnings, only info issues
+var lintModule = map[string]string{
+	"main.tf": `resource "aws_sqs_queue" "jobs" {
+  name = var.name
+}
+`,
+	"variables.tf": `variable "name" {
+  description = "Name of the queue"
+  type        = string
+}
+`,
+	"outputs.tf": `output "queue_url" {
+  description = "URL of the queue"
+  value       = aws_sqs_queue.jobs.url
+}
+`,
+	"versions.tf": `terraform {
+  required_version = ">= 1.5.0, < 2.0.0"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+  }
+}
+`,
+	"README.md": "# Queue\n",
+}
+
+// writeLintModule writes the lint module with extra files to a new directory
+func writeLintModule(t *testing.T, extra map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := make(map[string]string)
+	for name, content := range lintModule {
+		files[name] = content
+	}
+	for name, content := range extra {
+		files[name] = content
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func TestRunLintExitCodes(t *testing.T) {
+	info := writeLintModule(t, nil)
+	warning := writeLintModule(t, map[string]string{
+		"unused.tf": "variable \"unused\" {\n  description = \"Not used\"\n  type        = string\n}\n",
+	})
+	failing := writeLintModule(t, map[string]string{
+		"extra.tf": "resource \"aws_sqs_queue\" \"dead_letter\" {\n  name = var.undeclared\n}\n",
+	})
+
+	tests := []struct {
+		name      string
+		dir       string
+		threshold string
+		want      int
+	}{
+		{"info issues at error", info, "error", lintExitOK},
+		{"info issues at warning", info, "warning", lintExitOK},
+		{"info issues at info", info, "info", lintExitIssues},
+		{"warnings at error", warning, "error", lintExitOK},
+		{"warnings at warning", warning, "warning", lintExitIssues},
+		{"errors at error", failing, "error", lintExitIssues},
+		{"errors at none", failing, "none", lintExitOK},
+		{"invalid threshold", info, "fatal", lintExitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if got := runLint([]string{"-severity-threshold", tt.threshold, tt.dir}, &stdout, &stderr); got != tt.want {
+				t.Errorf("Expected exit code %d, got %d\nstdout:\n%s\nstderr:\n%s", tt.want, got, stdout.String(), stderr.String())
+			}
+		})
+	}
+}
+
+func TestRunLintFormats(t *testing.T) {
+	dir := writeLintModule(t, nil)
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"", "Validation summary:"},
+		{"text", "Validation summary:"},
+		{"json", `"issues": [`},
+		{"sarif", `"version": "2.1.0"`},
+		{"SARIF", `"version": "2.1.0"`},
+		{"junit", "<testsuites "},
+		{"checkstyle", "<checkstyle "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			args := []string{"-severity-threshold", "none", dir}
+			if tt.format != "" {
+				args = append([]string{"-format", tt.format}, args...)
+			}
+			if got := runLint(args, &stdout, &stderr); got != lintExitOK {
+				t.Fatalf("Expected exit code %d, got %d: %s", lintExitOK, got, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.want) {
+				t.Errorf("Expected output to contain %q, got:\n%s", tt.want, stdout.String())
+			}
+			if tt.format == "json" && !json.Valid(stdout.Bytes()) {
+				t.Errorf("Expected valid JSON, got:\n%s", stdout.String())
+			}
+		})
+	}
+
+	var stdout, stderr bytes.Buffer
+	if got := runLint([]string{"-format", "yaml", dir}, &stdout, &stderr); got != lintExitError {
+		t.Errorf("Expected exit code %d for an unknown format, got %d", lintExitError, got)
+	}
+	if !strings.Contains(stderr.String(), "unsupported output format: yaml") {
+		t.Errorf("Expected an unsupported format error, got %q", stderr.String())
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("Expected no output for an unknown format, got:\n%s", stdout.String())
+	}
+}
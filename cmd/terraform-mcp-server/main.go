@@ -22,6 +22,10 @@ type config struct {
 	Addr            string
 	DocSourcePath   string
 	PatternPath     string
+	PolicyPath      string
+	RegoPolicyPath  string
+	SchemaPath      string
+	WorkspaceRoots  string
 	DataDir         string
 	UpdateInterval  time.Duration
 	LogLevel        string
@@ -29,6 +33,11 @@ type config struct {
 }
 
 func main() {
+	// Run the lint command instead of the server if requested
+	if len(os.Args) > 1 && os.Args[1] == "lint" {
+		lintMain(os.Args[2:])
+	}
+
 	// Parse command line arguments
 	cfg := parseFlags()
 	
@@ -56,6 +65,10 @@ func main() {
 	serverConfig := hashicorp.Config{
 		DocSourcePath:    cfg.DocSourcePath,
 		PatternPath:      cfg.PatternPath,
+		PolicyPath:       cfg.PolicyPath,
+		RegoPolicyPath:   cfg.RegoPolicyPath,
+		SchemaPath:       cfg.SchemaPath,
+		WorkspaceRoots:   splitList(cfg.WorkspaceRoots),
 		UpdateInterval:   cfg.UpdateInterval,
 		AuthoritySources: authoritySources,
 	}
@@ -112,6 +125,8 @@ func parseFlags() config {
 	flag.StringVar(&cfg.DataDir, "data-dir", defaultDataDir, "Data directory")
 	flag.StringVar(&cfg.LogLevel, "log-level", "info", "Log level (debug, info, error)")
 	flag.DurationVar(&cfg.UpdateInterval, "update-interval", 24*time.Hour, "Update interval for documentation")
+	flag.StringVar(&cfg.RegoPolicyPath, "rego-policy-dir", "", "Directory of Rego policies to evaluate during validation (disabled if empty)")
+	flag.StringVar(&cfg.WorkspaceRoots, "workspace-roots", "", "Comma-separated list of directories ValidateConfiguration may read through its path argument (disabled if empty)")
 	flag.StringVar(&cfg.AuthoritySources, "authority-sources", "", "Comma-separated list of authority sources for Terraform documentation")
 	
 	// Parse flags
@@ -120,6 +135,19 @@ func parseFlags() config {
 	// Derive paths from data directory
 	cfg.DocSourcePath = filepath.Join(cfg.DataDir, "docs")
 	cfg.PatternPath = filepath.Join(cfg.DataDir, "patterns")
+	cfg.PolicyPath = filepath.Join(cfg.DataDir, "policies")
+	cfg.SchemaPath = filepath.Join(cfg.DataDir, "schemas")
 	
 	return cfg
-}
\ No newline at end of file
+}
+
+// splitList splits a comma-separated list, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -21,6 +21,7 @@ type Server struct {
 	patternRepo      *tfdocs.PatternRepository
 	resourceProvider *tfdocs.ResourceProvider
 	validationEngine *tfdocs.ValidationEngine
+	workspace        *tfdocs.Workspace
 	logger           Logger
 }
 
@@ -35,6 +36,10 @@ type Logger interface {
 type Config struct {
 	DocSourcePath    string
 	PatternPath      string
+	PolicyPath       string
+	RegoPolicyPath   string
+	SchemaPath       string
+	WorkspaceRoots   []string
 	UpdateInterval   time.Duration
 	AuthoritySources []string
 }
@@ -44,6 +49,8 @@ func DefaultConfig() Config {
 	return Config{
 		DocSourcePath:    "data/docs",
 		PatternPath:      "data/patterns",
+		PolicyPath:       "data/policies",
+		SchemaPath:       "data/schemas",
 		UpdateInterval:   24 * time.Hour,
 		AuthoritySources: tfdocs.DefaultAuthoritySources,
 	}
@@ -86,7 +93,19 @@ func NewServer(config Config, logger Logger) (*Server, error) {
 	
 	patternRepo := tfdocs.NewPatternRepository(config.PatternPath, logger)
 	resourceProvider := tfdocs.NewResourceProvider(docIndexer, logger)
-	validationEngine := tfdocs.NewValidationEngine(docIndexer, logger)
+	validationEngine := tfdocs.NewValidationEngine(
+		docIndexer,
+		logger,
+		tfdocs.WithPolicyPath(config.PolicyPath),
+		tfdocs.WithRegoPolicyPath(config.RegoPolicyPath),
+		tfdocs.WithSchemaPath(config.SchemaPath),
+	)
+
+	// Paths on disk can only be validated inside the workspace roots
+	workspace, err := tfdocs.NewWorkspace(config.WorkspaceRoots)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure workspace: %w", err)
+	}
 	
 	// Create MCP server
 	mcpServer := mcp.NewServer(logger)
@@ -97,6 +116,7 @@ func NewServer(config Config, logger Logger) (*Server, error) {
 		patternRepo:      patternRepo,
 		resourceProvider: resourceProvider,
 		validationEngine: validationEngine,
+		workspace:        workspace,
 		logger:           logger,
 	}, nil
 }
@@ -115,6 +135,11 @@ func (s *Server) Initialize(ctx context.Context) error {
 		return fmt.Errorf("failed to initialize pattern repository: %w", err)
 	}
 	
+	// Initialize the validation engine
+	if err := s.validationEngine.Initialize(ctx); err != nil {
+		return fmt.Errorf("failed to initialize validation engine: %w", err)
+	}
+	
 	// Register the tools
 	s.registerTools()
 	
@@ -128,8 +153,18 @@ func (s *Server) registerTools() {
 	s.mcpServer.AddTool(NewGetBestPracticesTool(s.docIndexer, s.resourceProvider, s.logger))
 	s.mcpServer.AddTool(NewGetModuleStructureTool(s.docIndexer, s.resourceProvider, s.logger))
 	s.mcpServer.AddTool(NewGetPatternTemplateTool(s.patternRepo, s.logger))
-	s.mcpServer.AddTool(NewValidateConfigurationTool(s.validationEngine, s.logger))
+	s.mcpServer.AddTool(NewValidateConfigurationTool(s.validationEngine, s.workspace, s.logger))
 	s.mcpServer.AddTool(NewSuggestImprovementsTool(s.validationEngine, s.logger))
+	s.mcpServer.AddTool(NewApplyFixesTool(s.validationEngine, s.logger))
+	s.mcpServer.AddTool(NewFormatConfigurationTool(s.logger))
+	s.mcpServer.AddTool(NewGenerateDocsTool(s.logger))
+	s.mcpServer.AddTool(NewScaffoldModuleTool(s.docIndexer, s.patternRepo, s.validationEngine, s.logger))
+	s.mcpServer.AddTool(NewGenerateTestsTool(s.logger))
+	s.mcpServer.AddTool(NewGenerateExamplesTool(s.logger))
+	s.mcpServer.AddTool(NewGetDependencyGraphTool(s.logger))
+	s.mcpServer.AddTool(NewValidatePlanTool(s.validationEngine, s.workspace, s.logger))
+	s.mcpServer.AddTool(NewSummarizePlanTool(s.workspace, s.logger))
+	s.mcpServer.AddTool(NewAnalyzeStateTool(s.validationEngine, s.workspace, s.logger))
 }
 
 // AddTool registers a tool with the server
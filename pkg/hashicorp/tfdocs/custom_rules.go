@@ -0,0 +1,385 @@
+// pkg/hashicorp/tfdocs/custom_rules.go
+package tfdocs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CustomRuleFile represents a YAML file of declarative policy rules
+type CustomRuleFile struct {
+	Rules []*CustomRule `yaml:"rules"`
+}
+
+// CustomRule is a declarative policy rule. Blocks selected by Match must
+// satisfy every condition in Require, otherwise an issue is reported.
+type CustomRule struct {
+	ID           string             `yaml:"id"`
+	Description  string             `yaml:"description"`
+	Severity     ValidationSeverity `yaml:"severity"`
+	Category     ValidationCategory `yaml:"category"`
+	Message      string             `yaml:"message"`
+	BestPractice string             `yaml:"best_practice"`
+	Suggestion   string             `yaml:"suggestion"`
+	Match        BlockMatcher       `yaml:"match"`
+	Require      RuleRequirement    `yaml:"require"`
+}
+
+// BlockMatcher selects blocks by type, labels and attribute values
+type BlockMatcher struct {
+	// Block is the block type, e.g. resource, data or module
+	Block string `yaml:"block"`
+	// Labels are glob patterns matched positionally against the block labels
+	Labels []string `yaml:"labels"`
+	// Attributes are conditions the block attributes must satisfy to match
+	Attributes []AttributeMatcher `yaml:"attributes"`
+}
+
+// AttributeMatcher is a condition on an attribute. The name may be a dotted
+// path through nested blocks, e.g. versioning.enabled.
+type AttributeMatcher struct {
+	Name    string        `yaml:"name"`
+	Exists  *bool         `yaml:"exists"`
+	Equals  interface{}   `yaml:"equals"`
+	OneOf   []interface{} `yaml:"one_of"`
+	Matches string        `yaml:"matches"`
+
+	pattern *regexp.Regexp
+}
+
+// RuleRequirement lists the conditions a matched block must satisfy
+type RuleRequirement struct {
+	Attributes []AttributeMatcher `yaml:"attributes"`
+	Sibling    *SiblingMatcher    `yaml:"sibling"`
+}
+
+// SiblingMatcher requires another block in the same configuration. When
+// References is set the sibling must also reference the matched block.
+type SiblingMatcher struct {
+	BlockMatcher `yaml:",inline"`
+	References   bool `yaml:"references"`
+}
+
+// LoadCustomRules loads all *.yaml and *.yml rule files from a directory
+func LoadCustomRules(dir string) ([]*CustomRule, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read policy directory: %w", err)
+	}
+
+	var rules []*CustomRule
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rule file %s: %w", entry.Name(), err)
+		}
+
+		fileRules, err := ParseCustomRules(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse rule file %s: %w", entry.Name(), err)
+		}
+		rules = append(rules, fileRules...)
+	}
+
+	return rules, nil
+}
+
+// ParseCustomRules parses and checks the rules of a YAML rule file
+func ParseCustomRules(data []byte) ([]*CustomRule, error) {
+	var file CustomRuleFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	for _, rule := range file.Rules {
+		if err := rule.compile(); err != nil {
+			return nil, err
+		}
+	}
+
+	return file.Rules, nil
+}
+
+// compile checks the rule and fills in defaults
+func (r *CustomRule) compile() error {
+	if r.ID == "" {
+		return fmt.Errorf("rule is missing an id")
+	}
+	if r.Match.Block == "" {
+		return fmt.Errorf("rule %s: match.block is required", r.ID)
+	}
+
+	switch r.Severity {
+	case "":
+		r.Severity = SeverityWarning
+	case SeverityError, SeverityWarning, SeverityInfo:
+	default:
+		return fmt.Errorf("rule %s: unknown severity %q", r.ID, r.Severity)
+	}
+
+	if r.Category == "" {
+		r.Category = CategorySecurity
+	}
+
+	if len(r.Require.Attributes) == 0 && r.Require.Sibling == nil {
+		return fmt.Errorf("rule %s: require must contain attributes or a sibling", r.ID)
+	}
+
+	matchers := [][]AttributeMatcher{r.Match.Attributes, r.Require.Attributes}
+	if r.Require.Sibling != nil {
+		if r.Require.Sibling.Block == "" {
+			return fmt.Errorf("rule %s: require.sibling.block is required", r.ID)
+		}
+		matchers = append(matchers, r.Require.Sibling.Attributes)
+	}
+	for _, list := range matchers {
+		for i := range list {
+			if list[i].Name == "" {
+				return fmt.Errorf("rule %s: attribute condition is missing a name", r.ID)
+			}
+			if list[i].Matches != "" {
+				pattern, err := regexp.Compile(list[i].Matches)
+				if err != nil {
+					return fmt.Errorf("rule %s: invalid pattern for %s: %w", r.ID, list[i].Name, err)
+				}
+				list[i].pattern = pattern
+			}
+		}
+	}
+
+	return nil
+}
+
+// CustomRuleValidator validates a configuration against declarative policy rules
+type CustomRuleValidator struct {
+	rules []*CustomRule
+}
+
+// NewCustomRuleValidator creates a validator for the given rules
+func NewCustomRuleValidator(rules []*CustomRule) *CustomRuleValidator {
+	return &CustomRuleValidator{rules: rules}
+}
+
+// Name returns the name of the validator
+func (v *CustomRuleValidator) Name() string {
+	return "CustomRuleValidator"
+}
+
+// SetRules replaces the rules of the validator
+func (v *CustomRuleValidator) SetRules(rules []*CustomRule) {
+	v.rules = rules
+}
+
+// Rules returns the rules of the validator
+func (v *CustomRuleValidator) Rules() []*CustomRule {
+	return v.rules
+}
+
+// Validate validates a Terraform configuration against the custom rules
+func (v *CustomRuleValidator) Validate(config *TerraformConfiguration) []ValidationIssue {
+	var issues []ValidationIssue
+
+	if len(v.rules) == 0 {
+		return issues
+	}
+
+	blocks := config.Blocks("")
+	for _, rule := range v.rules {
+		for _, block := range blocks {
+			if !rule.Match.matches(block) {
+				continue
+			}
+
+			if failed := rule.check(block, blocks); failed != "" {
+				issues = append(issues, rule.issue(block, failed))
+			}
+		}
+	}
+
+	return issues
+}
+
+// check returns a description of the first unmet requirement, or an empty
+// string if the block satisfies the rule
+func (r *CustomRule) check(block *Block, blocks []*Block) string {
+	for _, cond := range r.Require.Attributes {
+		if !cond.satisfiedBy(block) {
+			return cond.describe()
+		}
+	}
+
+	if sibling := r.Require.Sibling; sibling != nil {
+		found := false
+		for _, candidate := range blocks {
+			if candidate == block || !sibling.matches(candidate) {
+				continue
+			}
+			if sibling.References && !blockReferences(candidate, block.Address()) {
+				continue
+			}
+			found = true
+			break
+		}
+		if !found {
+			return fmt.Sprintf("requires a %s %s", sibling.Block, strings.Join(sibling.Labels, " "))
+		}
+	}
+
+	return ""
+}
+
+// issue builds the validation issue reported for a block that violates the rule
+func (r *CustomRule) issue(block *Block, failed string) ValidationIssue {
+	message := r.Message
+	if message == "" {
+		message = fmt.Sprintf("%s violates rule %s: %s", block.Address(), r.ID, failed)
+	}
+	message = strings.ReplaceAll(message, "{address}", block.Address())
+
+	bestPractice := r.BestPractice
+	if bestPractice == "" {
+		bestPractice = r.Description
+	}
+
+	return ValidationIssue{
+		Message:      message,
+		RuleID:       r.ID,
+		Severity:     r.Severity,
+		Category:     r.Category,
+		File:         block.File,
+		Line:         block.Line,
+		BestPractice: bestPractice,
+		Suggestion:   strings.ReplaceAll(r.Suggestion, "{address}", block.Address()),
+	}
+}
+
+// matches reports whether a block matches the block type, labels and attribute conditions
+func (m *BlockMatcher) matches(block *Block) bool {
+	if block.Type != m.Block {
+		return false
+	}
+
+	if len(m.Labels) > len(block.Labels) {
+		return false
+	}
+	for i, pattern := range m.Labels {
+		if ok, err := path.Match(pattern, block.Labels[i]); err != nil || !ok {
+			return false
+		}
+	}
+
+	for _, cond := range m.Attributes {
+		if !cond.satisfiedBy(block) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// satisfiedBy reports whether a block satisfies the attribute condition.
+// Values that cannot be determined statically satisfy value conditions.
+func (m *AttributeMatcher) satisfiedBy(block *Block) bool {
+	attrs := block.FindAttributes(m.Name)
+
+	if m.Exists != nil && (len(attrs) > 0) != *m.Exists {
+		return false
+	}
+
+	hasValueCondition := m.Equals != nil || len(m.OneOf) > 0 || m.pattern != nil
+	if !hasValueCondition {
+		return true
+	}
+	if len(attrs) == 0 {
+		return false
+	}
+
+	for _, attr := range attrs {
+		value, ok := attr.StringValue()
+		if !ok {
+			continue
+		}
+		if m.Equals != nil && value != fmt.Sprint(m.Equals) {
+			return false
+		}
+		if len(m.OneOf) > 0 && !containsValue(m.OneOf, value) {
+			return false
+		}
+		if m.pattern != nil && !m.pattern.MatchString(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// describe returns a human readable description of the condition
+func (m *AttributeMatcher) describe() string {
+	var parts []string
+	if m.Exists != nil {
+		if *m.Exists {
+			parts = append(parts, "must be set")
+		} else {
+			parts = append(parts, "must not be set")
+		}
+	}
+	if m.Equals != nil {
+		parts = append(parts, fmt.Sprintf("must equal %v", m.Equals))
+	}
+	if len(m.OneOf) > 0 {
+		values := make([]string, 0, len(m.OneOf))
+		for _, value := range m.OneOf {
+			values = append(values, fmt.Sprint(value))
+		}
+		sort.Strings(values)
+		parts = append(parts, fmt.Sprintf("must be one of %s", strings.Join(values, ", ")))
+	}
+	if m.pattern != nil {
+		parts = append(parts, fmt.Sprintf("must match %s", m.Matches))
+	}
+	return fmt.Sprintf("attribute %s %s", m.Name, strings.Join(parts, " and "))
+}
+
+// containsValue reports whether a list of YAML values contains a value
+func containsValue(values []interface{}, value string) bool {
+	for _, v := range values {
+		if fmt.Sprint(v) == value {
+			return true
+		}
+	}
+	return false
+}
+
+// blockReferences reports whether any attribute of a block, including
+// nested blocks, references the given address
+func blockReferences(block *Block, address string) bool {
+	for _, attr := range block.Attributes {
+		for _, ref := range attr.References() {
+			if ref == address || strings.HasPrefix(ref, address+".") {
+				return true
+			}
+		}
+	}
+	for _, nested := range block.Blocks {
+		if blockReferences(nested, address) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,294 @@
+// pkg/hashicorp/tfdocs/dependency.go
+package tfdocs
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GraphFormat is a rendering of a dependency graph
+type GraphFormat string
+
+const (
+	GraphFormatDOT     GraphFormat = "dot"
+	GraphFormatMermaid GraphFormat = "mermaid"
+	GraphFormatJSON    GraphFormat = "json"
+)
+
+// GraphFormats lists the supported dependency graph formats
+var GraphFormats = []GraphFormat{GraphFormatDOT, GraphFormatMermaid, GraphFormatJSON}
+
+// ParseGraphFormat parses a graph format name. An empty name selects JSON.
+func ParseGraphFormat(name string) (GraphFormat, error) {
+	if name == "" {
+		return GraphFormatJSON, nil
+	}
+	for _, format := range GraphFormats {
+		if string(format) == strings.ToLower(name) {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported graph format: %s", name)
+}
+
+// DependencyEdge is a dependency of one symbol on another
+type DependencyEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// DependsOn is set for dependencies declared only through depends_on
+	DependsOn bool `json:"dependsOn,omitempty"`
+}
+
+// DependencyGraph is the dependency graph between the variables, locals,
+// resources, data sources, modules and outputs of a configuration
+type DependencyGraph struct {
+	Nodes []*Symbol        `json:"nodes"`
+	Edges []DependencyEdge `json:"edges"`
+	// Adjacency maps every node to the nodes it depends on
+	Adjacency map[string][]string `json:"adjacency"`
+}
+
+// DependencyGraph builds the dependency graph of the configuration from its
+// implicit references and depends_on arguments. References to undeclared
+// symbols and self references are left out.
+func (c *TerraformConfiguration) DependencyGraph() *DependencyGraph {
+	return c.ReferenceGraph().DependencyGraph()
+}
+
+// DependencyGraph reduces the reference graph to one edge per pair of
+// dependent symbols
+func (g *ReferenceGraph) DependencyGraph() *DependencyGraph {
+	graph := &DependencyGraph{
+		Nodes:     g.SortedSymbols(),
+		Adjacency: make(map[string][]string),
+	}
+	for _, node := range graph.Nodes {
+		graph.Adjacency[node.Address] = []string{}
+	}
+
+	edges := make(map[string]*DependencyEdge)
+	var keys []string
+	for _, ref := range g.References {
+		if _, ok := g.Symbols[ref.To]; !ok || ref.From == ref.To {
+			continue
+		}
+		if _, ok := g.Symbols[ref.From]; !ok {
+			continue
+		}
+
+		key := ref.From + "|" + ref.To
+		if edge, ok := edges[key]; ok {
+			// An implicit reference makes a depends_on entry redundant
+			edge.DependsOn = edge.DependsOn && ref.DependsOn
+			continue
+		}
+		edges[key] = &DependencyEdge{From: ref.From, To: ref.To, DependsOn: ref.DependsOn}
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		edge := edges[key]
+		graph.Edges = append(graph.Edges, *edge)
+		graph.Adjacency[edge.From] = append(graph.Adjacency[edge.From], edge.To)
+	}
+
+	return graph
+}
+
+// Cycles returns the dependency cycles of the graph. Each cycle starts and
+// ends with the same address, e.g. [local.a local.b local.a]. Module calls
+// are left out, since Terraform resolves their inputs and outputs
+// separately and a cycle through a module call is usually not a cycle.
+func (g *DependencyGraph) Cycles() [][]string {
+	// Find the strongly connected components with Tarjan's algorithm
+	index := make(map[string]int)
+	lowlink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var components [][]string
+
+	var connect func(node string)
+	connect = func(node string) {
+		index[node] = len(index)
+		lowlink[node] = index[node]
+		stack = append(stack, node)
+		onStack[node] = true
+
+		for _, next := range g.Adjacency[node] {
+			if isModuleAddress(next) {
+				continue
+			}
+			if _, visited := index[next]; !visited {
+				connect(next)
+				if lowlink[next] < lowlink[node] {
+					lowlink[node] = lowlink[next]
+				}
+			} else if onStack[next] && index[next] < lowlink[node] {
+				lowlink[node] = index[next]
+			}
+		}
+
+		if lowlink[node] != index[node] {
+			return
+		}
+		var component []string
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[last] = false
+			component = append(component, last)
+			if last == node {
+				break
+			}
+		}
+		if len(component) > 1 {
+			components = append(components, component)
+		}
+	}
+
+	for _, node := range g.Nodes {
+		if isModuleAddress(node.Address) {
+			continue
+		}
+		if _, visited := index[node.Address]; !visited {
+			connect(node.Address)
+		}
+	}
+
+	var cycles [][]string
+	for _, component := range components {
+		sort.Strings(component)
+		cycles = append(cycles, g.cyclePath(component))
+	}
+	sort.Slice(cycles, func(i, j int) bool {
+		return cycles[i][0] < cycles[j][0]
+	})
+	return cycles
+}
+
+// isModuleAddress reports whether an address refers to a module call
+func isModuleAddress(address string) bool {
+	return strings.HasPrefix(address, "module.")
+}
+
+// cyclePath returns the shortest cycle through the first node of a strongly
+// connected component
+func (g *DependencyGraph) cyclePath(component []string) []string {
+	members := make(map[string]bool, len(component))
+	for _, node := range component {
+		members[node] = true
+	}
+
+	start := component[0]
+	previous := make(map[string]string)
+	queue := []string{start}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, next := range g.Adjacency[node] {
+			if !members[next] {
+				continue
+			}
+			if next == start {
+				path := []string{start}
+				for n := node; n != start; n = previous[n] {
+					path = append(path, n)
+				}
+				path = append(path, start)
+				// The path was collected backwards
+				for i, j := 1, len(path)-2; i < j; i, j = i+1, j-1 {
+					path[i], path[j] = path[j], path[i]
+				}
+				return path
+			}
+			if _, seen := previous[next]; !seen {
+				previous[next] = node
+				queue = append(queue, next)
+			}
+		}
+	}
+	return append(component, start)
+}
+
+// Render renders the graph in a format. JSON renders the adjacency lists.
+func (g *DependencyGraph) Render(format GraphFormat) (string, error) {
+	switch format {
+	case GraphFormatDOT:
+		return g.DOT(), nil
+	case GraphFormatMermaid:
+		return g.Mermaid(), nil
+	case GraphFormatJSON:
+		data, err := json.MarshalIndent(g.Adjacency, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal graph: %w", err)
+		}
+		return string(data), nil
+	}
+	return "", fmt.Errorf("unsupported graph format: %s", format)
+}
+
+// dotShapes are the Graphviz node shapes of each symbol kind
+var dotShapes = map[SymbolKind]string{
+	SymbolVariable: "ellipse",
+	SymbolLocal:    "hexagon",
+	SymbolResource: "box",
+	SymbolData:     "cylinder",
+	SymbolModule:   "component",
+	SymbolOutput:   "parallelogram",
+}
+
+// DOT renders the graph in the Graphviz DOT language
+func (g *DependencyGraph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph dependencies {\n")
+	b.WriteString("  rankdir=LR;\n")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(&b, "  %q [shape=%s];\n", node.Address, dotShapes[node.Kind])
+	}
+	for _, edge := range g.Edges {
+		if edge.DependsOn {
+			fmt.Fprintf(&b, "  %q -> %q [style=dashed, label=\"depends_on\"];\n", edge.From, edge.To)
+			continue
+		}
+		fmt.Fprintf(&b, "  %q -> %q;\n", edge.From, edge.To)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// mermaidShapes are the opening and closing brackets of the Mermaid node
+// shapes of each symbol kind
+var mermaidShapes = map[SymbolKind][2]string{
+	SymbolVariable: {"([", "])"},
+	SymbolLocal:    {"{{", "}}"},
+	SymbolResource: {"[", "]"},
+	SymbolData:     {"[(", ")]"},
+	SymbolModule:   {"[[", "]]"},
+	SymbolOutput:   {"[/", "/]"},
+}
+
+// Mermaid renders the graph as a Mermaid flowchart that can be pasted into
+// Markdown
+func (g *DependencyGraph) Mermaid() string {
+	ids := make(map[string]string, len(g.Nodes))
+
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for i, node := range g.Nodes {
+		id := fmt.Sprintf("n%d", i)
+		ids[node.Address] = id
+		shape := mermaidShapes[node.Kind]
+		fmt.Fprintf(&b, "  %s%s\"%s\"%s\n", id, shape[0], node.Address, shape[1])
+	}
+	for _, edge := range g.Edges {
+		if edge.DependsOn {
+			fmt.Fprintf(&b, "  %s -. depends_on .-> %s\n", ids[edge.From], ids[edge.To])
+			continue
+		}
+		fmt.Fprintf(&b, "  %s --> %s\n", ids[edge.From], ids[edge.To])
+	}
+	return b.String()
+}
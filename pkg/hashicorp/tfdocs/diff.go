@@ -0,0 +1,237 @@
+// pkg/hashicorp/tfdocs/diff.go
+package tfdocs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// diffContextLines is the number of unchanged lines shown around each change
+const diffContextLines = 3
+
+// diffOp is a single line of a line-based diff
+type diffOp struct {
+	kind byte // ' ' for unchanged, '-' for deleted, '+' for inserted
+	line string
+}
+
+// UnifiedDiff renders the changes between the original and modified content
+// of a file as a git-style unified diff. An empty original with exists set
+// to false is rendered as a new file. Identical content yields an empty string.
+func UnifiedDiff(name string, original string, exists bool, modified string) string {
+	if exists && original == modified {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("diff --git a/%s b/%s\n", name, name))
+	if !exists {
+		sb.WriteString("new file mode 100644\n")
+		if modified == "" {
+			return sb.String()
+		}
+		sb.WriteString("--- /dev/null\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("--- a/%s\n", name))
+	}
+	sb.WriteString(fmt.Sprintf("+++ b/%s\n", name))
+
+	ops := diffLines(splitLines(original), splitLines(modified))
+	for _, hunk := range diffHunks(ops) {
+		writeHunk(&sb, ops, hunk)
+	}
+
+	return sb.String()
+}
+
+// ImprovementDiffs renders each improved file as a unified diff against the
+// original configuration. Files without changes are omitted.
+func ImprovementDiffs(original, improvements map[string]string) map[string]string {
+	diffs := make(map[string]string, len(improvements))
+	for name, content := range improvements {
+		before, exists := original[name]
+		if diff := UnifiedDiff(name, before, exists, content); diff != "" {
+			diffs[name] = diff
+		}
+	}
+	return diffs
+}
+
+// CombinedPatch concatenates the diffs of all improved files, ordered by file
+// name, into a single patch that can be applied with git apply
+func CombinedPatch(original, improvements map[string]string) string {
+	diffs := ImprovementDiffs(original, improvements)
+
+	names := make([]string, 0, len(diffs))
+	for name := range diffs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for _, name := range names {
+		sb.WriteString(diffs[name])
+	}
+	return sb.String()
+}
+
+// splitLines splits content into lines that keep their trailing newline, so
+// a missing newline at the end of the file shows up as a change
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines computes a shortest edit script between two sets of lines using
+// the Myers algorithm
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	max := n + m
+	offset := max + 1
+	v := make([]int, 2*max+3)
+
+	// Walk forward, recording the furthest reaching paths for each edit distance
+	var trace [][]int
+	done := false
+	for d := 0; d <= max && !done; d++ {
+		snapshot := make([]int, len(v))
+		copy(snapshot, v)
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				done = true
+				break
+			}
+		}
+	}
+
+	// Walk back through the trace to recover the edit script
+	var ops []diffOp
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			ops = append(ops, diffOp{kind: ' ', line: a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				ops = append(ops, diffOp{kind: '+', line: b[y-1]})
+			} else {
+				ops = append(ops, diffOp{kind: '-', line: a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+
+	return ops
+}
+
+// hunkRange is a range of diff operations rendered as one hunk
+type hunkRange struct {
+	start, end int
+}
+
+// diffHunks groups changes into hunks with surrounding context, merging
+// hunks whose context would overlap
+func diffHunks(ops []diffOp) []hunkRange {
+	var hunks []hunkRange
+	for i, op := range ops {
+		if op.kind == ' ' {
+			continue
+		}
+
+		start := i - diffContextLines
+		if start < 0 {
+			start = 0
+		}
+		end := i + diffContextLines + 1
+		if end > len(ops) {
+			end = len(ops)
+		}
+
+		if len(hunks) > 0 && start <= hunks[len(hunks)-1].end {
+			hunks[len(hunks)-1].end = end
+			continue
+		}
+		hunks = append(hunks, hunkRange{start: start, end: end})
+	}
+	return hunks
+}
+
+// writeHunk writes a hunk header followed by its lines
+func writeHunk(sb *strings.Builder, ops []diffOp, hunk hunkRange) {
+	// Line numbers of the first line of the hunk in each file
+	oldStart, newStart := 1, 1
+	for _, op := range ops[:hunk.start] {
+		if op.kind != '+' {
+			oldStart++
+		}
+		if op.kind != '-' {
+			newStart++
+		}
+	}
+
+	oldLen, newLen := 0, 0
+	for _, op := range ops[hunk.start:hunk.end] {
+		if op.kind != '+' {
+			oldLen++
+		}
+		if op.kind != '-' {
+			newLen++
+		}
+	}
+
+	// Empty ranges refer to the line before the hunk
+	if oldLen == 0 {
+		oldStart--
+	}
+	if newLen == 0 {
+		newStart--
+	}
+
+	sb.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n", oldStart, oldLen, newStart, newLen))
+	for _, op := range ops[hunk.start:hunk.end] {
+		sb.WriteByte(op.kind)
+		sb.WriteString(op.line)
+		if !strings.HasSuffix(op.line, "\n") {
+			sb.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// pkg/hashicorp/tfdocs/examples.go
+package tfdocs
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// exampleKind is an example caller generated for a module
+type exampleKind struct {
+	Dir         string
+	Title       string
+	Description string
+	// All sets the variables with defaults too
+	All bool
+}
+
+// exampleKinds are the examples generated for a module
+var exampleKinds = []exampleKind{
+	{Dir: "examples/basic", Title: "Basic example", Description: "only its required inputs", All: false},
+	{Dir: "examples/complete", Title: "Complete example", Description: "all of its inputs", All: true},
+}
+
+// GenerateExamples generates callers of the root module of a configuration:
+// examples/basic sets the required variables and examples/complete sets all
+// of them. Values are inferred from the names, types and validations of the
+// variables, or taken from their defaults. Sensitive variables are passed
+// through from variables of the example so no secrets are hardcoded. Each
+// example declares the provider requirements of the module, exposes its
+// outputs and has a README. Variables and outputs of modules in
+// subdirectories are not inputs or outputs of the root module and are ignored.
+func GenerateExamples(config *TerraformConfiguration, name string) map[string]string {
+	files := make(map[string]string)
+	label := strings.ReplaceAll(name, "-", "_")
+	root := config.ModuleTree().Root.Config
+	usages := variableUsages(root)
+
+	// Providers of child modules are configured by the example too
+	var providers strings.Builder
+	for _, provider := range usedProviders(config) {
+		if example := scaffoldProviders[provider].Example; example != "" {
+			providers.WriteString(example + "\n")
+		}
+	}
+
+	var outputs []string
+	for _, block := range root.Blocks("output") {
+		output := block.Label(0)
+		description := fmt.Sprintf("%q", "The "+humanize(output)+" of the module")
+		if attr, ok := block.Attributes["description"]; ok {
+			description = attr.Source
+		}
+		text := fmt.Sprintf("output %q {\n  description = %s\n  value       = module.%s.%s\n", output, description, label, output)
+		if isSensitive(block) {
+			text += "  sensitive   = true\n"
+		}
+		outputs = append(outputs, text+"}\n")
+	}
+
+	for _, example := range exampleKinds {
+		var inputs, secrets, secretNames []string
+		for _, block := range root.Blocks("variable") {
+			variable := block.Label(0)
+			def, hasDefault := block.Attributes["default"]
+			if hasDefault && !example.All {
+				continue
+			}
+			switch {
+			case isSensitive(block) || isSecretName(variable):
+				inputs = append(inputs, fmt.Sprintf("  %s = var.%s\n", variable, variable))
+				secrets = append(secrets, exampleVariable(block))
+				secretNames = append(secretNames, variable)
+			case hasDefault && !isEmptyValue(def):
+				inputs = append(inputs, fmt.Sprintf("  %s = %s\n", variable, def.Source))
+			default:
+				inputs = append(inputs, fmt.Sprintf("  %s = %s\n", variable, sampleValue(block, usages[variable])))
+			}
+		}
+
+		module := fmt.Sprintf("module %q {\n  source = \"../..\"\n", label)
+		if len(inputs) > 0 {
+			module += "\n" + strings.Join(inputs, "")
+		}
+		module += "}\n"
+
+		exampleFiles := map[string]string{
+			"main.tf":     providers.String() + module,
+			"versions.tf": exampleVersions(root),
+		}
+		if len(outputs) > 0 {
+			exampleFiles["outputs.tf"] = strings.Join(outputs, "\n")
+		}
+		if len(secrets) > 0 {
+			exampleFiles["variables.tf"] = strings.Join(secrets, "\n")
+		}
+		for file, content := range exampleFiles {
+			if formatted, err := FormatHCL(file, content); err == nil {
+				exampleFiles[file] = formatted
+			}
+		}
+
+		var readme strings.Builder
+		fmt.Fprintf(&readme, "# %s\n\nCalls the `%s` module with %s.\n\n## Usage\n\n", example.Title, name, example.Description)
+		readme.WriteString("```sh\nterraform init\nterraform plan\n```\n")
+		if len(secretNames) > 0 {
+			fmt.Fprintf(&readme, "\nSet the sensitive inputs with `TF_VAR_` environment variables, e.g. `export TF_VAR_%s=...`.\n", secretNames[0])
+		}
+		exampleConfig := &TerraformConfiguration{Files: exampleFiles}
+		exampleFiles["README.md"] = UpdateReadme(readme.String(), exampleConfig.ModuleDocs())
+
+		for file, content := range exampleFiles {
+			files[path.Join(example.Dir, file)] = content
+		}
+	}
+
+	return files
+}
+
+// exampleVariable returns the declaration of a variable passing a sensitive
+// variable of the module through an example
+func exampleVariable(block *Block) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "variable %q {\n", block.Label(0))
+	if attr, ok := block.Attributes["description"]; ok {
+		fmt.Fprintf(&b, "  description = %s\n", attr.Source)
+	}
+	if attr, ok := block.Attributes["type"]; ok {
+		fmt.Fprintf(&b, "  type        = %s\n", attr.Source)
+	}
+	b.WriteString("  sensitive   = true\n}\n")
+	return b.String()
+}
+
+// exampleVersions returns the terraform block of an example, requiring the
+// Terraform version and providers the module requires
+func exampleVersions(config *TerraformConfiguration) string {
+	requiredVersion := ""
+	for _, block := range config.Blocks("terraform") {
+		if attr, ok := block.Attributes["required_version"]; ok {
+			requiredVersion, _ = attr.StringValue()
+		}
+	}
+	requirements := config.ProviderRequirements()
+	if requiredVersion == "" && len(requirements) == 0 {
+		return scaffoldVersions(scaffoldRequiredVersion, usedProviders(config))
+	}
+	return versionsBlock(requiredVersion, requirements)
+}
+
+// isSensitive reports whether a variable or output is marked sensitive
+func isSensitive(block *Block) bool {
+	attr, ok := block.Attributes["sensitive"]
+	if !ok {
+		return false
+	}
+	value, ok := attr.Value()
+	return ok && value.Type() == cty.Bool && value.True()
+}
+
+// isEmptyValue reports whether a default value is null, an empty string or
+// an empty collection
+func isEmptyValue(attr *Attribute) bool {
+	value, ok := attr.Value()
+	if !ok {
+		return false
+	}
+	switch {
+	case value.IsNull():
+		return true
+	case value.Type() == cty.String:
+		return value.AsString() == ""
+	case value.CanIterateElements():
+		return value.LengthInt() == 0
+	}
+	return false
+}
@@ -0,0 +1,461 @@
+// pkg/hashicorp/tfdocs/fixes.go
+package tfdocs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// Position is a position in a file. Line and column are 1-based, byte is a
+// 0-based offset.
+type Position struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	Byte   int `json:"byte"`
+}
+
+// TextEdit replaces the text between Start and End of a file with NewText.
+// An edit with equal Start and End is an insertion.
+type TextEdit struct {
+	File    string   `json:"file"`
+	Start   Position `json:"start"`
+	End     Position `json:"end"`
+	NewText string   `json:"new_text"`
+}
+
+// Fix is a machine-applicable fix for a validation issue
+type Fix struct {
+	Description string     `json:"description"`
+	Edits       []TextEdit `json:"edits"`
+}
+
+// AppliedFix records a fix that was applied or skipped
+type AppliedFix struct {
+	RuleID      string `json:"rule_id,omitempty"`
+	Description string `json:"description"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// FixResult is the result of applying fixes to a configuration
+type FixResult struct {
+	Files   map[string]string `json:"files"`
+	Changed []string          `json:"changed"`
+	Applied []AppliedFix      `json:"applied"`
+	Skipped []AppliedFix      `json:"skipped"`
+}
+
+// ApplyFixes validates a configuration and applies the fixes of the issues
+// found. If rule IDs are given, only fixes for those rules are applied;
+// otherwise only the fixes of errors and warnings are, like
+// SuggestImprovements does, since info-level fixes such as generated tests
+// add files the module may not want. Fixes whose edits overlap an already
+// applied fix are skipped.
+func (e *ValidationEngine) ApplyFixes(config *TerraformConfiguration, ruleIDs []string) (*FixResult, error) {
+	result, err := e.ValidateConfiguration(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []ValidationIssue
+	for _, issue := range result.Issues {
+		if issue.Fix == nil {
+			continue
+		}
+		if len(ruleIDs) > 0 && !containsString(ruleIDs, issue.RuleID) {
+			continue
+		}
+		if len(ruleIDs) == 0 && issue.Severity != SeverityError && issue.Severity != SeverityWarning {
+			continue
+		}
+		issues = append(issues, issue)
+	}
+
+	return ApplyIssueFixes(config.Files, issues), nil
+}
+
+// ApplyIssueFixes applies the fixes of the given issues to a set of files
+func ApplyIssueFixes(files map[string]string, issues []ValidationIssue) *FixResult {
+	result := &FixResult{
+		Files:   make(map[string]string, len(files)),
+		Changed: []string{},
+		Applied: []AppliedFix{},
+		Skipped: []AppliedFix{},
+	}
+	for name, content := range files {
+		result.Files[name] = content
+	}
+
+	// Accept fixes in order, skipping duplicates and fixes that overlap
+	var accepted []TextEdit
+	for _, issue := range issues {
+		if issue.Fix == nil {
+			continue
+		}
+		record := AppliedFix{RuleID: issue.RuleID, Description: issue.Fix.Description}
+
+		if reason := checkEdits(files, accepted, issue.Fix.Edits); reason != "" {
+			record.Reason = reason
+			result.Skipped = append(result.Skipped, record)
+			continue
+		}
+
+		accepted = append(accepted, issue.Fix.Edits...)
+		result.Applied = append(result.Applied, record)
+	}
+
+	// Apply the accepted edits file by file
+	byFile := make(map[string][]TextEdit)
+	for _, edit := range accepted {
+		byFile[edit.File] = append(byFile[edit.File], edit)
+	}
+	for name, edits := range byFile {
+		result.Files[name] = ApplyEdits(files[name], edits)
+		result.Changed = append(result.Changed, name)
+	}
+	sort.Strings(result.Changed)
+
+	return result
+}
+
+// checkEdits returns the reason a fix cannot be applied, or an empty string
+func checkEdits(files map[string]string, accepted, edits []TextEdit) string {
+	if len(edits) == 0 {
+		return "fix has no edits"
+	}
+
+	for i, edit := range edits {
+		content, ok := files[edit.File]
+		if !ok && !(edit.Start.Byte == 0 && edit.End.Byte == 0) {
+			return fmt.Sprintf("file %s does not exist", edit.File)
+		}
+		if edit.Start.Byte > edit.End.Byte || edit.End.Byte > len(content) {
+			return fmt.Sprintf("edit range is outside of %s", edit.File)
+		}
+		for _, other := range edits[:i] {
+			if editsOverlap(edit, other) {
+				return "fix contains overlapping edits"
+			}
+		}
+		for _, other := range accepted {
+			if edit == other {
+				return "duplicate of an applied fix"
+			}
+			if editsOverlap(edit, other) {
+				return "overlaps an applied fix"
+			}
+		}
+	}
+
+	return ""
+}
+
+// editsOverlap reports whether two edits touch the same text. Insertions at
+// the same position do not overlap and are applied in order, e.g. the
+// attributes several fixes add to a block, but an insertion at the start of
+// a replaced range does, since it could go on either side of the new text.
+func editsOverlap(a, b TextEdit) bool {
+	if a.File != b.File {
+		return false
+	}
+	if a.Start.Byte == b.Start.Byte {
+		return a.Start.Byte != a.End.Byte || b.Start.Byte != b.End.Byte
+	}
+	return a.Start.Byte < b.End.Byte && b.Start.Byte < a.End.Byte
+}
+
+// ApplyEdits applies non-overlapping edits to the content of a file.
+// Insertions at the same position end up in the order of the edits.
+func ApplyEdits(content string, edits []TextEdit) string {
+	// Edits are applied from the end of the file, so that earlier offsets
+	// stay valid, and later insertions at a position before earlier ones
+	sorted := make([]TextEdit, len(edits))
+	for i, edit := range edits {
+		sorted[len(edits)-1-i] = edit
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Start.Byte > sorted[j].Start.Byte
+	})
+
+	for _, edit := range sorted {
+		content = content[:edit.Start.Byte] + edit.NewText + content[edit.End.Byte:]
+	}
+
+	return content
+}
+
+// replaceRange returns an edit replacing the text of an HCL range
+func replaceRange(rng hcl.Range, newText string) TextEdit {
+	return TextEdit{
+		File:    rng.Filename,
+		Start:   positionFromHCL(rng.Start),
+		End:     positionFromHCL(rng.End),
+		NewText: newText,
+	}
+}
+
+// insertAt returns an edit inserting text at an HCL position
+func insertAt(file string, pos hcl.Pos, text string) TextEdit {
+	return TextEdit{
+		File:    file,
+		Start:   positionFromHCL(pos),
+		End:     positionFromHCL(pos),
+		NewText: text,
+	}
+}
+
+// positionFromHCL converts an HCL position
+func positionFromHCL(pos hcl.Pos) Position {
+	return Position{Line: pos.Line, Column: pos.Column, Byte: pos.Byte}
+}
+
+// addAttributeFix returns a fix that adds an attribute as the first line
+// of a block body
+func addAttributeFix(block *Block, name, value, description string) *Fix {
+	indent := strings.Repeat(" ", block.Range.Start.Column-1)
+	line := fmt.Sprintf("%s  %s = %s", indent, name, value)
+
+	// Expand blocks written on a single line, e.g. variable "x" {}
+	if block.OpenBraceRange.End.Line == block.CloseBraceRange.Start.Line {
+		return &Fix{
+			Description: description,
+			Edits: []TextEdit{{
+				File:    block.File,
+				Start:   positionFromHCL(block.OpenBraceRange.End),
+				End:     positionFromHCL(block.CloseBraceRange.Start),
+				NewText: "\n" + line + "\n" + indent,
+			}},
+		}
+	}
+
+	return &Fix{
+		Description: description,
+		Edits:       []TextEdit{insertAt(block.File, block.OpenBraceRange.End, "\n"+line)},
+	}
+}
+
+// renameVariableFix returns a fix that renames a variable and updates all
+// var.<name> references in the configuration, as well as the assignments in
+// .tfvars files and the variables blocks of test files. Callers of the
+// module have to rename their argument, so the description says so.
+func renameVariableFix(config *TerraformConfiguration, block *Block, newName string) *Fix {
+	oldName := block.Label(0)
+	if len(block.LabelRanges) == 0 {
+		return nil
+	}
+
+	fix := &Fix{
+		Description: fmt.Sprintf("Rename variable '%s' to '%s' and update its references; callers of the module must rename the '%s' argument as well", oldName, newName, oldName),
+		Edits:       []TextEdit{replaceRange(block.LabelRanges[0], fmt.Sprintf("%q", newName))},
+	}
+
+	files := config.ParsedFiles()
+	var names []string
+	for name := range config.Files {
+		if strings.HasSuffix(name, ".tfvars") || isTestFile(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		file := parseFile(name, config.Files[name])
+		files = append(files, file)
+
+		// Rename the assignments of the variable, which are top-level in
+		// .tfvars files and in variables blocks of test files and their runs
+		assignments := []map[string]*Attribute{file.Attributes}
+		if isTestFile(name) {
+			assignments = nil
+			for _, block := range file.Blocks {
+				if block.Type == "variables" {
+					assignments = append(assignments, block.Attributes)
+				}
+				for _, variables := range block.NestedBlocks("variables") {
+					assignments = append(assignments, variables.Attributes)
+				}
+			}
+		}
+		for _, attrs := range assignments {
+			if attr, ok := attrs[oldName]; ok {
+				fix.Edits = append(fix.Edits, renameAttribute(attr, newName))
+			}
+		}
+	}
+
+	for _, file := range files {
+		for _, attr := range file.AllAttributes() {
+			for _, traversal := range attr.Expr.Variables() {
+				if traversal.RootName() != "var" || len(traversal) < 2 {
+					continue
+				}
+				step, ok := traversal[1].(hcl.TraverseAttr)
+				if !ok || step.Name != oldName {
+					continue
+				}
+				fix.Edits = append(fix.Edits, replaceRange(step.SrcRange, "."+newName))
+			}
+		}
+	}
+
+	return fix
+}
+
+// renameAttribute returns an edit renaming an attribute
+func renameAttribute(attr *Attribute, newName string) TextEdit {
+	start := attr.Range.Start
+	end := hcl.Pos{Line: start.Line, Column: start.Column + len(attr.Name), Byte: start.Byte + len(attr.Name)}
+	return replaceRange(hcl.Range{Filename: attr.File, Start: start, End: end}, newName)
+}
+
+// countToForEachFix returns a fix that converts count = length(x) into
+// for_each = toset(x), rewriting x[count.index] to each.value. The fix is
+// only offered when x is a collection of strings, every count.index is
+// used exactly as x[count.index] and no other block refers to the
+// instances, since each.key would differ from count.index and indexed
+// references would break. Existing instances still need moved blocks.
+func countToForEachFix(config *TerraformConfiguration, block *Block, attr *Attribute) *Fix {
+	call, ok := attr.Expr.(*hclsyntax.FunctionCallExpr)
+	if !ok || call.Name != "length" || len(call.Args) != 1 {
+		return nil
+	}
+
+	file := config.ParsedFile(block.File)
+	if file == nil {
+		return nil
+	}
+	collection := string(call.Args[0].Range().SliceBytes(file.Source))
+	if !isStringCollection(config, call.Args[0]) {
+		return nil
+	}
+
+	// Every use of count.index must be an element access of the collection
+	element := collection + "[count.index]"
+	for _, other := range block.AllAttributes() {
+		if other == attr {
+			continue
+		}
+		text := string(file.Source[other.Range.Start.Byte:other.Range.End.Byte])
+		if strings.Count(text, "count.index") != strings.Count(text, element) {
+			return nil
+		}
+		for rest := text; strings.Contains(rest, element); {
+			rest = rest[strings.Index(rest, element)+len(element):]
+			if strings.HasPrefix(rest, ".") || strings.HasPrefix(rest, "[") {
+				return nil
+			}
+		}
+	}
+
+	// Instances referenced by index or splat would change type
+	for _, ref := range config.ReferenceGraph().References {
+		if ref.To == block.Address() && ref.From != block.Address() && !ref.DependsOn {
+			return nil
+		}
+	}
+
+	fix := &Fix{
+		Description: fmt.Sprintf("Replace 'count = length(%s)' with 'for_each = toset(%s)'; existing instances need moved blocks to their new keys", collection, collection),
+		Edits:       []TextEdit{replaceRange(attr.Range, fmt.Sprintf("for_each = toset(%s)", collection))},
+	}
+
+	for _, other := range block.AllAttributes() {
+		if other == attr {
+			continue
+		}
+		fix.Edits = append(fix.Edits, replaceInRange(file, other.Range, map[string]string{
+			element: "each.value",
+		})...)
+	}
+
+	return fix
+}
+
+// isStringCollection reports whether an expression refers to a variable
+// declared as a list or set of strings
+func isStringCollection(config *TerraformConfiguration, expr hclsyntax.Expression) bool {
+	traversal, ok := expr.(*hclsyntax.ScopeTraversalExpr)
+	if !ok || traversal.Traversal.RootName() != "var" || len(traversal.Traversal) != 2 {
+		return false
+	}
+	step, ok := traversal.Traversal[1].(hcl.TraverseAttr)
+	if !ok {
+		return false
+	}
+	for _, variable := range config.Blocks("variable") {
+		if variable.Label(0) != step.Name {
+			continue
+		}
+		typ, ok := variable.Attributes["type"]
+		if !ok {
+			return false
+		}
+		switch strings.Join(strings.Fields(typ.Source), "") {
+		case "list(string)", "set(string)":
+			return true
+		}
+	}
+	return false
+}
+
+// replaceInRange returns edits replacing occurrences of the given strings
+// within a range of a file. Longer strings take precedence.
+func replaceInRange(file *ParsedFile, rng hcl.Range, replacements map[string]string) []TextEdit {
+	olds := make([]string, 0, len(replacements))
+	for old := range replacements {
+		olds = append(olds, old)
+	}
+	sort.Slice(olds, func(i, j int) bool {
+		return len(olds[i]) > len(olds[j])
+	})
+
+	var edits []TextEdit
+	text := string(file.Source[rng.Start.Byte:rng.End.Byte])
+	for offset := 0; offset < len(text); {
+		matched := false
+		for _, old := range olds {
+			if !strings.HasPrefix(text[offset:], old) {
+				continue
+			}
+			start := rng.Start.Byte + offset
+			edits = append(edits, TextEdit{
+				File:    file.Name,
+				Start:   bytePosition(file.Source, start),
+				End:     bytePosition(file.Source, start+len(old)),
+				NewText: replacements[old],
+			})
+			offset += len(old)
+			matched = true
+			break
+		}
+		if !matched {
+			offset++
+		}
+	}
+
+	return edits
+}
+
+// bytePosition computes the line and column of a byte offset
+func bytePosition(src []byte, offset int) Position {
+	line := 1 + strings.Count(string(src[:offset]), "\n")
+	column := offset - strings.LastIndex(string(src[:offset]), "\n")
+	return Position{Line: line, Column: column, Byte: offset}
+}
+
+// humanize turns an identifier such as vpc_cidr into words
+func humanize(name string) string {
+	return strings.NewReplacer("_", " ", "-", " ").Replace(name)
+}
+
+// containsString reports whether a slice contains a string
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
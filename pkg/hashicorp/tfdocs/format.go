@@ -0,0 +1,155 @@
+// pkg/hashicorp/tfdocs/format.go
+package tfdocs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// FormatResult is the result of formatting a configuration
+type FormatResult struct {
+	Files   map[string]string `json:"files"`
+	Changed []string          `json:"changed"`
+	Diffs   map[string]string `json:"diffs"`
+	Patch   string            `json:"patch"`
+	// Errors holds the syntax errors of files that could not be formatted
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// isFormattedFile reports whether a file is in native HCL syntax and
+// formatted by terraform fmt
+func isFormattedFile(name string) bool {
+	return strings.HasSuffix(name, ".tf") || strings.HasSuffix(name, ".tfvars") || strings.HasSuffix(name, ".tftest.hcl")
+}
+
+// FormatHCL returns the canonical formatting of a file in native HCL syntax,
+// as produced by terraform fmt. Files with syntax errors are not formatted.
+func FormatHCL(name, content string) (string, error) {
+	_, diags := hclwrite.ParseConfig([]byte(content), name, hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return "", fmt.Errorf("failed to parse %s: %s", name, diags.Error())
+	}
+	return string(hclwrite.Format([]byte(content))), nil
+}
+
+// FormatConfiguration formats the Terraform, variable and test files of a
+// configuration. Other files are returned unchanged.
+func FormatConfiguration(config *TerraformConfiguration) *FormatResult {
+	result := &FormatResult{
+		Files:   make(map[string]string, len(config.Files)),
+		Changed: []string{},
+		Errors:  make(map[string]string),
+	}
+
+	formatted := make(map[string]string)
+	for name, content := range config.Files {
+		result.Files[name] = content
+		if !isFormattedFile(name) {
+			continue
+		}
+		canonical, err := FormatHCL(name, content)
+		if err != nil {
+			result.Errors[name] = err.Error()
+			continue
+		}
+		if canonical != content {
+			result.Files[name] = canonical
+			formatted[name] = canonical
+			result.Changed = append(result.Changed, name)
+		}
+	}
+	sort.Strings(result.Changed)
+
+	result.Diffs = ImprovementDiffs(config.Files, formatted)
+	result.Patch = CombinedPatch(config.Files, formatted)
+	return result
+}
+
+// formatEdits returns the edits turning the original content into the
+// formatted content, one per changed run of lines
+func formatEdits(name, original, formatted string) []TextEdit {
+	src := []byte(original)
+	ops := diffLines(splitLines(original), splitLines(formatted))
+
+	var edits []TextEdit
+	offset := 0
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			offset += len(ops[i].line)
+			i++
+			continue
+		}
+
+		start := offset
+		var text strings.Builder
+		for ; i < len(ops) && ops[i].kind != ' '; i++ {
+			if ops[i].kind == '-' {
+				offset += len(ops[i].line)
+				continue
+			}
+			text.WriteString(ops[i].line)
+		}
+		edits = append(edits, TextEdit{
+			File:    name,
+			Start:   bytePosition(src, start),
+			End:     bytePosition(src, offset),
+			NewText: text.String(),
+		})
+	}
+	return edits
+}
+
+// FormatValidator reports files that are not formatted canonically
+type FormatValidator struct{}
+
+// Name returns the name of the validator
+func (v *FormatValidator) Name() string {
+	return "FormatValidator"
+}
+
+// Validate reports each file whose formatting differs from terraform fmt,
+// with the diff and a fix. Files with syntax errors are skipped.
+func (v *FormatValidator) Validate(config *TerraformConfiguration) []ValidationIssue {
+	names := make([]string, 0, len(config.Files))
+	for name := range config.Files {
+		if isFormattedFile(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var issues []ValidationIssue
+	for _, name := range names {
+		content := config.Files[name]
+		formatted, err := FormatHCL(name, content)
+		if err != nil || formatted == content {
+			continue
+		}
+
+		edits := formatEdits(name, content, formatted)
+		issues = append(issues, ValidationIssue{
+			Message:      fmt.Sprintf("File %s is not formatted canonically", name),
+			RuleID:       "formatting",
+			Severity:     SeverityWarning,
+			Category:     CategoryMaintenance,
+			File:         name,
+			Line:         edits[0].Start.Line,
+			BestPractice: "Format all files with terraform fmt",
+			Suggestion:   "Run terraform fmt or apply the fix",
+			Fix:          &Fix{Description: fmt.Sprintf("Format %s", name), Edits: edits},
+			Diff:         UnifiedDiff(name, content, true, formatted),
+		})
+	}
+	return issues
+}
+
+// DescribeRules returns the rules checked by the validator
+func (v *FormatValidator) DescribeRules() []RuleMetadata {
+	return []RuleMetadata{
+		{ID: "formatting", Name: "Formatting", Description: "Format all files with terraform fmt", Severity: SeverityWarning, Category: CategoryMaintenance, Tags: []string{"style"}},
+	}
+}
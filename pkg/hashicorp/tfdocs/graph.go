@@ -0,0 +1,370 @@
+// pkg/hashicorp/tfdocs/graph.go
+package tfdocs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// SymbolKind is the kind of a symbol declared in a configuration
+type SymbolKind string
+
+const (
+	SymbolVariable SymbolKind = "variable"
+	SymbolLocal    SymbolKind = "local"
+	SymbolResource SymbolKind = "resource"
+	SymbolData     SymbolKind = "data"
+	SymbolModule   SymbolKind = "module"
+	SymbolOutput   SymbolKind = "output"
+)
+
+// Symbol is a declared variable, local value, resource, data source, module
+// call or output
+type Symbol struct {
+	Kind    SymbolKind `json:"kind"`
+	Address string     `json:"address"`
+	Name    string     `json:"name"`
+	File    string     `json:"file"`
+	Line    int        `json:"line"`
+}
+
+// Reference is a reference from one symbol to another, e.g. from
+// output.vpc_id to aws_vpc.main
+type Reference struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// DependsOn is set for references listed in a depends_on argument
+	DependsOn bool      `json:"dependsOn,omitempty"`
+	File      string    `json:"file"`
+	Line      int       `json:"line"`
+	Range     hcl.Range `json:"-"`
+}
+
+// ReferenceGraph holds the symbols of a configuration and the references
+// between them
+type ReferenceGraph struct {
+	Symbols    map[string]*Symbol
+	References []Reference
+}
+
+// referenceRoots are root names of references that do not refer to symbols
+var referenceRoots = map[string]bool{
+	"path":      true,
+	"terraform": true,
+	"count":     true,
+	"each":      true,
+	"self":      true,
+}
+
+// ReferenceGraph builds the symbol and reference graph of the configuration
+func (c *TerraformConfiguration) ReferenceGraph() *ReferenceGraph {
+	graph := &ReferenceGraph{Symbols: make(map[string]*Symbol)}
+
+	// Collect the declared symbols
+	for _, file := range c.ParsedFiles() {
+		for _, block := range file.Blocks {
+			switch block.Type {
+			case "variable":
+				graph.add(SymbolVariable, block.Address(), block.Label(0), block.File, block.Line)
+			case "resource":
+				graph.add(SymbolResource, block.Address(), block.Label(1), block.File, block.Line)
+			case "data":
+				graph.add(SymbolData, block.Address(), block.Label(1), block.File, block.Line)
+			case "module":
+				graph.add(SymbolModule, block.Address(), block.Label(0), block.File, block.Line)
+			case "output":
+				graph.add(SymbolOutput, block.Address(), block.Label(0), block.File, block.Line)
+			case "check":
+				// Data sources scoped to a check block
+				for _, data := range block.NestedBlocks("data") {
+					graph.add(SymbolData, data.Address(), data.Label(1), data.File, data.Line)
+				}
+			case "locals":
+				for _, attr := range sortedAttributes(block.Attributes) {
+					graph.add(SymbolLocal, "local."+attr.Name, attr.Name, attr.File, attr.Line)
+				}
+			}
+		}
+	}
+
+	// Collect the references between them
+	for _, file := range c.ParsedFiles() {
+		for _, block := range file.Blocks {
+			switch block.Type {
+			case "locals":
+				for _, attr := range sortedAttributes(block.Attributes) {
+					graph.addReferences("local."+attr.Name, attr, nil)
+				}
+			case "variable", "resource", "data", "module", "output":
+				graph.addBlockReferences(block.Address(), block, nil)
+			case "provider", "check", "import", "moved", "removed", "terraform":
+				// These blocks refer to symbols without being symbols
+				// themselves, e.g. provider.aws or check.health
+				graph.addBlockReferences(block.Address(), block, nil)
+			}
+		}
+	}
+
+	return graph
+}
+
+// add declares a symbol
+func (g *ReferenceGraph) add(kind SymbolKind, address, name, file string, line int) {
+	if _, ok := g.Symbols[address]; ok {
+		return
+	}
+	g.Symbols[address] = &Symbol{Kind: kind, Address: address, Name: name, File: file, Line: line}
+}
+
+// addBlockReferences adds the references of a block and its nested blocks.
+// Iterator names of dynamic blocks are local to their content.
+func (g *ReferenceGraph) addBlockReferences(from string, block *Block, iterators map[string]bool) {
+	for _, attr := range sortedAttributes(block.Attributes) {
+		if skipReferenceAttribute(block, attr.Name) {
+			continue
+		}
+		g.addReferences(from, attr, iterators)
+	}
+
+	for _, nested := range block.Blocks {
+		scope := iterators
+		if nested.Type == "dynamic" {
+			iterator := nested.Label(0)
+			if attr, ok := nested.Attributes["iterator"]; ok {
+				if refs := attr.References(); len(refs) == 1 {
+					iterator = refs[0]
+				}
+			}
+			scope = make(map[string]bool, len(iterators)+1)
+			for name := range iterators {
+				scope[name] = true
+			}
+			scope[iterator] = true
+		}
+		g.addBlockReferences(from, nested, scope)
+	}
+}
+
+// skipReferenceAttribute reports whether an attribute holds addresses or
+// names that are not references to symbols
+func skipReferenceAttribute(block *Block, name string) bool {
+	switch {
+	case block.Type == "lifecycle" && name == "ignore_changes":
+		return true
+	case block.Type == "dynamic" && name == "iterator":
+		return true
+	case (block.Type == "resource" || block.Type == "data") && name == "provider":
+		return true
+	case block.Type == "module" && name == "providers":
+		return true
+	case (block.Type == "moved" || block.Type == "removed") && (name == "from" || name == "to"):
+		return true
+	case block.Type == "import" && (name == "to" || name == "provider"):
+		return true
+	}
+	return false
+}
+
+// addReferences adds the references of an attribute expression
+func (g *ReferenceGraph) addReferences(from string, attr *Attribute, iterators map[string]bool) {
+	for _, traversal := range attr.Expr.Variables() {
+		if iterators[traversal.RootName()] {
+			continue
+		}
+		to, ok := referenceAddress(traversal)
+		if !ok {
+			continue
+		}
+		rng := traversal.SourceRange()
+		g.References = append(g.References, Reference{
+			From:      from,
+			To:        to,
+			DependsOn: attr.Name == "depends_on",
+			File:      attr.File,
+			Line:      rng.Start.Line,
+			Range:     rng,
+		})
+	}
+}
+
+// referenceAddress returns the address of the symbol a traversal refers to
+func referenceAddress(traversal hcl.Traversal) (string, bool) {
+	root := traversal.RootName()
+	if referenceRoots[root] {
+		return "", false
+	}
+
+	var names []string
+	for _, step := range traversal[1:] {
+		attr, ok := step.(hcl.TraverseAttr)
+		if !ok {
+			break
+		}
+		names = append(names, attr.Name)
+	}
+
+	switch root {
+	case "var", "local", "module":
+		if len(names) < 1 {
+			return "", false
+		}
+		return root + "." + names[0], true
+	case "data":
+		if len(names) < 2 {
+			return "", false
+		}
+		return fmt.Sprintf("data.%s.%s", names[0], names[1]), true
+	}
+
+	// Any other root name is a resource type
+	if len(names) < 1 {
+		return "", false
+	}
+	return root + "." + names[0], true
+}
+
+// ReferencesTo returns the references to a symbol from other symbols
+func (g *ReferenceGraph) ReferencesTo(address string) []Reference {
+	var refs []Reference
+	for _, ref := range g.References {
+		if ref.To == address && ref.From != address {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// SortedSymbols returns the symbols ordered by address
+func (g *ReferenceGraph) SortedSymbols() []*Symbol {
+	symbols := make([]*Symbol, 0, len(g.Symbols))
+	for _, symbol := range g.Symbols {
+		symbols = append(symbols, symbol)
+	}
+	sort.Slice(symbols, func(i, j int) bool {
+		return symbols[i].Address < symbols[j].Address
+	})
+	return symbols
+}
+
+// ReferenceValidator reports undefined references, dependency cycles, unused
+// variables and locals, and outputs pointing at resources that do not exist
+type ReferenceValidator struct{}
+
+// Name returns the name of the validator
+func (v *ReferenceValidator) Name() string {
+	return "ReferenceValidator"
+}
+
+// Validate validates the references of a Terraform configuration
+func (v *ReferenceValidator) Validate(config *TerraformConfiguration) []ValidationIssue {
+	var issues []ValidationIssue
+
+	graph := config.ReferenceGraph()
+
+	// Check for references to undeclared symbols. Undeclared variables share
+	// a fix declaring all of them.
+	declare := undeclaredVariablesFix(config, graph)
+	reported := make(map[string]bool)
+	for _, ref := range graph.References {
+		if _, ok := graph.Symbols[ref.To]; ok {
+			continue
+		}
+		key := fmt.Sprintf("%s|%s|%s:%d", ref.From, ref.To, ref.File, ref.Line)
+		if reported[key] {
+			continue
+		}
+		reported[key] = true
+
+		issue := ValidationIssue{
+			Message:      fmt.Sprintf("Reference to undeclared %s '%s' in %s", referenceKind(ref.To), ref.To, ref.From),
+			RuleID:       "undefined-references",
+			Severity:     SeverityError,
+			Category:     CategoryStructure,
+			File:         ref.File,
+			Line:         ref.Line,
+			BestPractice: "Only reference variables, locals, resources, data sources and modules that are declared",
+			Suggestion:   fmt.Sprintf("Declare '%s' or fix the reference", ref.To),
+		}
+		if strings.HasPrefix(ref.To, "var.") {
+			issue.Fix = declare
+		}
+		if graph.Symbols[ref.From] != nil && graph.Symbols[ref.From].Kind == SymbolOutput {
+			issue.Message = fmt.Sprintf("Output '%s' references undeclared %s '%s'", graph.Symbols[ref.From].Name, referenceKind(ref.To), ref.To)
+			issue.RuleID = "output-undefined-references"
+			issue.BestPractice = "Outputs should expose attributes of resources and modules that exist in the module"
+		}
+		issues = append(issues, issue)
+	}
+
+	// Check for dependency cycles
+	for _, cycle := range graph.DependencyGraph().Cycles() {
+		symbol := graph.Symbols[cycle[0]]
+		issues = append(issues, ValidationIssue{
+			Message:      fmt.Sprintf("Dependency cycle: %s", strings.Join(cycle, " -> ")),
+			RuleID:       "dependency-cycles",
+			Severity:     SeverityError,
+			Category:     CategoryStructure,
+			File:         symbol.File,
+			Line:         symbol.Line,
+			BestPractice: "Resources, modules and locals must not depend on each other in a cycle",
+			Suggestion:   "Break the cycle by removing a reference or depends_on entry",
+		})
+	}
+
+	// Check for variables and locals that are never used
+	for _, symbol := range graph.SortedSymbols() {
+		if symbol.Kind != SymbolVariable && symbol.Kind != SymbolLocal {
+			continue
+		}
+		if len(graph.ReferencesTo(symbol.Address)) > 0 {
+			continue
+		}
+
+		kind := string(symbol.Kind)
+		ruleID := "unused-variables"
+		if symbol.Kind == SymbolLocal {
+			ruleID = "unused-locals"
+		}
+		issues = append(issues, ValidationIssue{
+			Message:      fmt.Sprintf("%s '%s' is declared but never used", strings.ToUpper(kind[:1])+kind[1:], symbol.Name),
+			RuleID:       ruleID,
+			Severity:     SeverityWarning,
+			Category:     CategoryMaintenance,
+			File:         symbol.File,
+			Line:         symbol.Line,
+			BestPractice: "Remove variables and locals that are not used",
+			Suggestion:   fmt.Sprintf("Use or remove %s '%s'", symbol.Kind, symbol.Name),
+		})
+	}
+
+	return issues
+}
+
+// referenceKind describes the kind of symbol an address refers to
+func referenceKind(address string) string {
+	switch {
+	case strings.HasPrefix(address, "var."):
+		return "variable"
+	case strings.HasPrefix(address, "local."):
+		return "local value"
+	case strings.HasPrefix(address, "module."):
+		return "module"
+	case strings.HasPrefix(address, "data."):
+		return "data source"
+	}
+	return "resource"
+}
+
+// DescribeRules returns the rules checked by the validator
+func (v *ReferenceValidator) DescribeRules() []RuleMetadata {
+	return []RuleMetadata{
+		{ID: "undefined-references", Name: "UndefinedReferences", Description: "Only reference variables, locals, resources, data sources and modules that are declared", Severity: SeverityError, Category: CategoryStructure},
+		{ID: "output-undefined-references", Name: "OutputUndefinedReferences", Description: "Outputs should expose attributes of resources and modules that exist in the module", Severity: SeverityError, Category: CategoryStructure},
+		{ID: "dependency-cycles", Name: "DependencyCycles", Description: "Resources, modules and locals must not depend on each other in a cycle", Severity: SeverityError, Category: CategoryStructure},
+		{ID: "unused-variables", Name: "UnusedVariables", Description: "Remove variables that are not used", Severity: SeverityWarning, Category: CategoryMaintenance},
+		{ID: "unused-locals", Name: "UnusedLocals", Description: "Remove locals that are not used", Severity: SeverityWarning, Category: CategoryMaintenance},
+	}
+}
@@ -0,0 +1,146 @@
+// pkg/hashicorp/tfdocs/ignore.go
+package tfdocs
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// defaultIgnorePatterns are always excluded, matching the behavior of
+// Terraform itself
+var defaultIgnorePatterns = []string{
+	".git/",
+	".terraform/",
+}
+
+// ignoreRule is a single pattern of an ignore file
+type ignoreRule struct {
+	pattern *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// IgnoreRules matches paths against gitignore-style patterns such as the
+// ones in .terraformignore. Later patterns take precedence, so a negated
+// pattern can re-include a path excluded by an earlier one.
+type IgnoreRules struct {
+	rules []ignoreRule
+}
+
+// ParseIgnorePatterns compiles gitignore-style patterns. Blank lines and
+// lines starting with # are skipped.
+func ParseIgnorePatterns(patterns []string) *IgnoreRules {
+	ignore := &IgnoreRules{}
+	for _, line := range patterns {
+		ignore.Add(line)
+	}
+	return ignore
+}
+
+// LoadIgnoreFile reads the patterns of an ignore file, following the default
+// patterns. A missing file yields only the default patterns.
+func LoadIgnoreFile(path string) (*IgnoreRules, error) {
+	patterns := append([]string{}, defaultIgnorePatterns...)
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ParseIgnorePatterns(patterns), nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		patterns = append(patterns, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return ParseIgnorePatterns(patterns), nil
+}
+
+// Add compiles a pattern and appends it to the rules
+func (r *IgnoreRules) Add(line string) {
+	pattern := strings.TrimSpace(line)
+	if pattern == "" || strings.HasPrefix(pattern, "#") {
+		return
+	}
+
+	rule := ignoreRule{}
+	if strings.HasPrefix(pattern, "!") {
+		rule.negate = true
+		pattern = pattern[1:]
+	}
+	if strings.HasSuffix(pattern, "/") {
+		rule.dirOnly = true
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+
+	// Patterns containing a slash are relative to the root, others match
+	// at any depth
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	expr := globToRegexp(pattern)
+	if !anchored {
+		expr = "(.*/)?" + expr
+	}
+	rule.pattern = regexp.MustCompile("^" + expr + "$")
+
+	r.rules = append(r.rules, rule)
+}
+
+// Match reports whether a slash-separated path relative to the root is ignored
+func (r *IgnoreRules) Match(path string, isDir bool) bool {
+	_, ignored := r.match(path, isDir)
+	return ignored
+}
+
+// match reports whether any pattern matches a path and whether the last
+// matching pattern ignores it
+func (r *IgnoreRules) match(path string, isDir bool) (matched, ignored bool) {
+	if r == nil {
+		return false, false
+	}
+	path = strings.TrimPrefix(filepath.ToSlash(path), "./")
+
+	for _, rule := range r.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.pattern.MatchString(path) {
+			matched = true
+			ignored = !rule.negate
+		}
+	}
+	return matched, ignored
+}
+
+// globToRegexp converts a glob with *, ? and ** into a regular expression
+func globToRegexp(glob string) string {
+	var sb strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			sb.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return sb.String()
+}
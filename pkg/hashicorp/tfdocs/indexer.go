@@ -310,7 +310,7 @@ func (i *Indexer) addDefaultBestPractices() {
 		Title:       "Document Variables with Description",
 		Category:    "documentation",
 		Description: "Always include a description for all variables",
-		Content:     "All variables in a Terraform module should include a description attribute that explains the purpose of the variable, expected values, and any constraints. This helps users understand how to use the module correctly. Additionally, variables should have an explicit type and, where appropriate, a default value or validation rules.",
+		Content:     "All variables in a Terraform module should include a description attribute that explains the purpose of the variable, expected values, and any constraints. This helps users understand how to use the module correctly. Additionally, variables should have an explicit type and, where appropriate, a default value or validation rules. Prefer specific types over any, such as map(object({...})) instead of map(any), set nullable = false on variables with a non-null default so that callers passing null get the default, and validate values with a known format such as CIDR blocks and ARNs or a fixed set of allowed values.",
 		Tags:        []string{"variables", "documentation"},
 		References:  []string{"https://developer.hashicorp.com/terraform/language/values/variables"},
 	}
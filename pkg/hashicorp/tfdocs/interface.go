@@ -0,0 +1,749 @@
+// pkg/hashicorp/tfdocs/interface.go
+package tfdocs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// variableUsage records how a variable is used in a configuration
+type variableUsage struct {
+	// Types are the types implied by the contexts the variable is used in
+	Types []string
+	// Attributes are the attributes read from the variable, e.g. var.x.name,
+	// with the types implied by their contexts
+	Attributes map[string][]string
+	// Elements are the attributes read from each element of a variable
+	// iterated with for_each, e.g. each.value.name
+	Elements map[string][]string
+	// Enums are the values the variable is compared to with contains()
+	Enums []string
+}
+
+// usageCollector collects variable usages. Each is the variable the
+// for_each argument of the current block iterates, if any.
+type usageCollector struct {
+	usages map[string]*variableUsage
+	each   string
+}
+
+// functionSignature lists the parameter types of a function. Variadic is
+// the type of the parameters after the listed ones.
+type functionSignature struct {
+	Params   []string
+	Variadic string
+}
+
+// functionSignatures are the parameter types of common functions. Empty
+// types accept values of any type.
+var functionSignatures = map[string]functionSignature{
+	"base64encode": {Params: []string{"string"}},
+	"cidrhost":     {Params: []string{"string", "number"}},
+	"cidrsubnet":   {Params: []string{"string", "number", "number"}},
+	"concat":       {Variadic: "list(string)"},
+	"contains":     {Params: []string{"list(string)", ""}},
+	"distinct":     {Params: []string{"list(string)"}},
+	"element":      {Params: []string{"list(string)", "number"}},
+	"endswith":     {Params: []string{"string", "string"}},
+	"file":         {Params: []string{"string"}},
+	"format":       {Params: []string{"string"}},
+	"join":         {Params: []string{"string", "list(string)"}},
+	"keys":         {Params: []string{"map(string)"}},
+	"lookup":       {Params: []string{"map(string)", "string", ""}},
+	"lower":        {Params: []string{"string"}},
+	"max":          {Variadic: "number"},
+	"merge":        {Variadic: "map(string)"},
+	"min":          {Variadic: "number"},
+	"replace":      {Params: []string{"string", "string", "string"}},
+	"sort":         {Params: []string{"list(string)"}},
+	"split":        {Params: []string{"string", "string"}},
+	"startswith":   {Params: []string{"string", "string"}},
+	"substr":       {Params: []string{"string", "number", "number"}},
+	"templatefile": {Params: []string{"string", ""}},
+	"title":        {Params: []string{"string"}},
+	"toset":        {Params: []string{"list(string)"}},
+	"trimspace":    {Params: []string{"string"}},
+	"upper":        {Params: []string{"string"}},
+	"values":       {Params: []string{"map(string)"}},
+}
+
+// param returns the type of the parameter at an index
+func (s functionSignature) param(index int) string {
+	if index < len(s.Params) {
+		return s.Params[index]
+	}
+	return s.Variadic
+}
+
+// numberOperations are the operators taking and returning numbers
+var numberOperations = []*hclsyntax.Operation{
+	hclsyntax.OpAdd, hclsyntax.OpSubtract, hclsyntax.OpMultiply, hclsyntax.OpDivide, hclsyntax.OpModulo,
+	hclsyntax.OpGreaterThan, hclsyntax.OpGreaterThanOrEqual, hclsyntax.OpLessThan, hclsyntax.OpLessThanOrEqual,
+}
+
+// variableUsages collects how each variable is used in the expressions of a
+// configuration. Variable blocks are skipped except for their validations.
+func variableUsages(config *TerraformConfiguration) map[string]*variableUsage {
+	c := &usageCollector{usages: make(map[string]*variableUsage)}
+	for _, block := range config.Blocks("") {
+		switch block.Type {
+		case "variable":
+			for _, validation := range block.NestedBlocks("validation") {
+				for _, attr := range sortedAttributes(validation.Attributes) {
+					c.collect(attr.Expr, "")
+				}
+			}
+		case "output", "locals":
+			for _, attr := range sortedAttributes(block.Attributes) {
+				c.collect(attr.Expr, "")
+			}
+		default:
+			c.each = ""
+			if attr, ok := block.Attributes["for_each"]; ok {
+				if traversal, ok := attr.Expr.(*hclsyntax.ScopeTraversalExpr); ok && traversal.Traversal.RootName() == "var" && len(traversal.Traversal) == 2 {
+					c.each = traversalString(traversal.Traversal)[len("var."):]
+				}
+			}
+			c.collectBlock(block)
+		}
+	}
+	return c.usages
+}
+
+// collectBlock collects the usages in the arguments of a block and its
+// nested blocks, using argument names to infer the expected types
+func (c *usageCollector) collectBlock(block *Block) {
+	for _, attr := range sortedAttributes(block.Attributes) {
+		c.collect(attr.Expr, argumentType(attr.Name))
+	}
+	for _, nested := range block.Blocks {
+		c.collectBlock(nested)
+	}
+}
+
+// collect records the variables used in an expression with the type the
+// context of the expression expects, or an empty type if unknown
+func (c *usageCollector) collect(expr hclsyntax.Expression, want string) {
+	switch e := expr.(type) {
+	case *hclsyntax.ScopeTraversalExpr:
+		c.record(e.Traversal, want)
+	case *hclsyntax.ParenthesesExpr:
+		c.collect(e.Expression, want)
+	case *hclsyntax.TemplateWrapExpr:
+		c.collect(e.Wrapped, want)
+	case *hclsyntax.TemplateExpr:
+		for _, part := range e.Parts {
+			c.collect(part, "string")
+		}
+	case *hclsyntax.ConditionalExpr:
+		c.collect(e.Condition, "bool")
+		c.collect(e.TrueResult, want)
+		c.collect(e.FalseResult, want)
+	case *hclsyntax.UnaryOpExpr:
+		operand := "number"
+		if e.Op == hclsyntax.OpLogicalNot {
+			operand = "bool"
+		}
+		c.collect(e.Val, operand)
+	case *hclsyntax.BinaryOpExpr:
+		operand := ""
+		switch {
+		case e.Op == hclsyntax.OpLogicalAnd || e.Op == hclsyntax.OpLogicalOr:
+			operand = "bool"
+		case containsOperation(numberOperations, e.Op):
+			operand = "number"
+		}
+		c.collect(e.LHS, firstType(operand, literalType(e.RHS)))
+		c.collect(e.RHS, firstType(operand, literalType(e.LHS)))
+	case *hclsyntax.FunctionCallExpr:
+		signature := functionSignatures[e.Name]
+		for i, arg := range e.Args {
+			c.collect(arg, signature.param(i))
+		}
+		if e.Name == "contains" && len(e.Args) == 2 {
+			c.recordEnum(e.Args[0], e.Args[1])
+		}
+	case *hclsyntax.IndexExpr:
+		if traversal, ok := e.Collection.(*hclsyntax.ScopeTraversalExpr); ok {
+			collection := ""
+			switch indexKeyType(e.Key) {
+			case "number":
+				collection = "list(" + firstType(want, "string") + ")"
+			case "string":
+				collection = "map(" + firstType(want, "string") + ")"
+			}
+			c.record(traversal.Traversal, collection)
+		} else {
+			c.collect(e.Collection, "")
+		}
+		c.collect(e.Key, "")
+	case *hclsyntax.TupleConsExpr:
+		for _, item := range e.Exprs {
+			c.collect(item, elementType(want))
+		}
+	case *hclsyntax.ObjectConsExpr:
+		for _, item := range e.Items {
+			c.collect(item.KeyExpr, "")
+			c.collect(item.ValueExpr, elementType(want))
+		}
+	default:
+		for _, traversal := range expr.Variables() {
+			c.record(traversal, "")
+		}
+	}
+}
+
+// record records a traversal of a variable or of the element of a variable
+// iterated with for_each
+func (c *usageCollector) record(traversal hcl.Traversal, want string) {
+	if len(traversal) < 2 {
+		return
+	}
+	step, ok := traversal[1].(hcl.TraverseAttr)
+	if !ok {
+		return
+	}
+
+	switch traversal.RootName() {
+	case "var":
+	case "each":
+		if c.each == "" || step.Name != "value" || len(traversal) < 3 {
+			return
+		}
+		if next, ok := traversal[2].(hcl.TraverseAttr); ok {
+			if len(traversal) > 3 {
+				want = ""
+			}
+			usage := c.usage(c.each)
+			usage.Elements[next.Name] = append(usage.Elements[next.Name], want)
+		}
+		return
+	default:
+		return
+	}
+
+	usage := c.usage(step.Name)
+	rest := traversal[2:]
+	if len(rest) == 0 {
+		usage.Types = append(usage.Types, want)
+		return
+	}
+
+	// Only the type of values read directly from the variable is inferred
+	if len(rest) > 1 {
+		want = ""
+	}
+	switch next := rest[0].(type) {
+	case hcl.TraverseAttr:
+		usage.Attributes[next.Name] = append(usage.Attributes[next.Name], want)
+	case hcl.TraverseIndex:
+		if next.Key.Type() == cty.Number {
+			usage.Types = append(usage.Types, "list("+firstType(want, "string")+")")
+		} else {
+			usage.Types = append(usage.Types, "map("+firstType(want, "string")+")")
+		}
+	}
+}
+
+// usage returns the usage of a variable, creating it if needed
+func (c *usageCollector) usage(name string) *variableUsage {
+	usage := c.usages[name]
+	if usage == nil {
+		usage = &variableUsage{Attributes: make(map[string][]string), Elements: make(map[string][]string)}
+		c.usages[name] = usage
+	}
+	return usage
+}
+
+// recordEnum records the values of contains([...], var.x) as the allowed
+// values of the variable
+func (c *usageCollector) recordEnum(list, value hclsyntax.Expression) {
+	traversal, ok := value.(*hclsyntax.ScopeTraversalExpr)
+	if !ok || traversal.Traversal.RootName() != "var" || len(traversal.Traversal) != 2 {
+		return
+	}
+	tuple, ok := list.(*hclsyntax.TupleConsExpr)
+	if !ok || len(tuple.Exprs) == 0 {
+		return
+	}
+
+	var values []string
+	for _, item := range tuple.Exprs {
+		template, ok := item.(*hclsyntax.TemplateExpr)
+		if !ok || !template.IsStringLiteral() {
+			return
+		}
+		value, _ := template.Value(nil)
+		values = append(values, value.AsString())
+	}
+	usage := c.usage(traversalString(traversal.Traversal)[len("var."):])
+	if len(usage.Enums) == 0 {
+		usage.Enums = values
+	}
+}
+
+// inferredType infers the type of a variable from its usages, such as the
+// arguments it is passed to, falling back to its name only when the usages
+// imply no type and then to string
+func (u *variableUsage) inferredType(name string) string {
+	return firstType(u.usageType(), argumentType(name), "string")
+}
+
+// usageType returns the type implied by the usages of a variable, or an
+// empty string if its usages do not imply one. Variables whose attributes
+// are read are objects, and variables iterated with for_each whose element
+// attributes are read are maps of objects.
+func (u *variableUsage) usageType() string {
+	if u == nil {
+		return ""
+	}
+	merged := mergeTypes(u.Types)
+	if len(u.Elements) > 0 && (merged == "" || merged == "map(any)") {
+		return "map(" + objectType(u.Elements) + ")"
+	}
+	if len(u.Attributes) > 0 && merged == "" {
+		return objectType(u.Attributes)
+	}
+	return merged
+}
+
+// objectType returns an object type with the given attributes
+func objectType(attributes map[string][]string) string {
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		names[i] = fmt.Sprintf("%s = %s", name, firstType(mergeTypes(attributes[name]), "string"))
+	}
+	return fmt.Sprintf("object({ %s })", strings.Join(names, ", "))
+}
+
+// mergeTypes returns the type shared by all known types, "any" if they
+// disagree, or an empty string if none is known
+func mergeTypes(types []string) string {
+	merged := ""
+	for _, t := range types {
+		switch {
+		case t == "":
+		case merged == "":
+			merged = t
+		case merged != t:
+			return "any"
+		}
+	}
+	return merged
+}
+
+// argumentType infers the type of an argument or variable from its name
+func argumentType(name string) string {
+	switch {
+	case name == "count" || name == "port" || name == "size" || name == "timeout":
+		return "number"
+	case name == "for_each":
+		return "map(any)"
+	case name == "tags" || name == "labels" || hasAnySuffix(name, "_tags", "_labels", "_map"):
+		return "map(string)"
+	case name == "enabled" || hasAnyPrefix(name, "enable_", "is_", "has_", "create_", "use_") ||
+		hasAnySuffix(name, "_enabled", "_encrypted", "_protection", "force_destroy", "publicly_accessible", "multi_az", "skip_final_snapshot"):
+		return "bool"
+	case hasAnySuffix(name, "_port", "_size", "_count", "_days", "_seconds", "_minutes", "_timeout", "_capacity", "_storage") ||
+		hasAnyPrefix(name, "min_", "max_", "desired_"):
+		return "number"
+	case name == "subnets" || name == "azs" || hasAnySuffix(name, "_ids", "_arns", "_names", "_blocks", "_cidrs", "_zones", "_subnets", "_groups", "_list"):
+		return "list(string)"
+	case name == "name" || name == "description" || name == "ami" || name == "engine" || name == "region" || name == "bucket" || name == "location" ||
+		hasAnySuffix(name, "_type", "_name", "_class", "_id", "_arn", "_version", "_region", "_cidr", "_family", "_description"):
+		return "string"
+	}
+	return ""
+}
+
+// literalType returns the type of a literal value expression
+func literalType(expr hclsyntax.Expression) string {
+	literal, ok := expr.(*hclsyntax.LiteralValueExpr)
+	if !ok {
+		return ""
+	}
+	switch literal.Val.Type() {
+	case cty.String:
+		return "string"
+	case cty.Number:
+		return "number"
+	case cty.Bool:
+		return "bool"
+	}
+	return ""
+}
+
+// indexKeyType returns the type of an index key: number for literals and
+// count.index, string for literals and each.key
+func indexKeyType(key hclsyntax.Expression) string {
+	if traversal, ok := key.(*hclsyntax.ScopeTraversalExpr); ok {
+		switch traversalString(traversal.Traversal) {
+		case "count.index":
+			return "number"
+		case "each.key":
+			return "string"
+		}
+		return ""
+	}
+	if template, ok := key.(*hclsyntax.TemplateExpr); ok && template.IsStringLiteral() {
+		return "string"
+	}
+	return literalType(key)
+}
+
+// elementType returns the element type of a collection type
+func elementType(collection string) string {
+	for _, prefix := range []string{"list(", "set(", "map("} {
+		if strings.HasPrefix(collection, prefix) && strings.HasSuffix(collection, ")") {
+			return collection[len(prefix) : len(collection)-1]
+		}
+	}
+	return ""
+}
+
+// firstType returns the first non-empty type
+func firstType(types ...string) string {
+	for _, t := range types {
+		if t != "" {
+			return t
+		}
+	}
+	return ""
+}
+
+// containsOperation reports whether an operation is in a list
+func containsOperation(operations []*hclsyntax.Operation, op *hclsyntax.Operation) bool {
+	for _, operation := range operations {
+		if operation == op {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAnyPrefix reports whether a string starts with any of the prefixes
+func hasAnyPrefix(s string, prefixes ...string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAnySuffix reports whether a string ends with any of the suffixes
+func hasAnySuffix(s string, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// undeclaredVariables returns the sorted names of the variables referenced
+// but not declared in a configuration
+func undeclaredVariables(graph *ReferenceGraph) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, ref := range graph.References {
+		if !strings.HasPrefix(ref.To, "var.") || seen[ref.To] {
+			continue
+		}
+		seen[ref.To] = true
+		if _, ok := graph.Symbols[ref.To]; !ok {
+			names = append(names, strings.TrimPrefix(ref.To, "var."))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// variableDeclarations returns variable blocks for the given names, with
+// the types inferred from their usage and placeholder descriptions
+func variableDeclarations(config *TerraformConfiguration, names []string) string {
+	usages := variableUsages(config)
+
+	blocks := make([]string, 0, len(names))
+	for _, name := range names {
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "variable %q {\n", name)
+		fmt.Fprintf(&sb, "  description = %q\n", "The "+humanize(name))
+		fmt.Fprintf(&sb, "  type        = %s\n", usages[name].inferredType(name))
+		if isSecretName(name) {
+			sb.WriteString("  sensitive   = true\n")
+		}
+		sb.WriteString("}\n")
+		blocks = append(blocks, sb.String())
+	}
+	return strings.Join(blocks, "\n")
+}
+
+// undeclaredVariablesFix returns a fix declaring every variable that is
+// referenced but not declared, or nil if there are none
+func undeclaredVariablesFix(config *TerraformConfiguration, graph *ReferenceGraph) *Fix {
+	names := undeclaredVariables(graph)
+	if len(names) == 0 {
+		return nil
+	}
+
+	file := interfaceFile(config, "variables.tf")
+	return &Fix{
+		Description: fmt.Sprintf("Declare %s in %s", pluralNames("variable", names), file),
+		Edits:       []TextEdit{appendBlocks(config, file, generateVariablesTF(config), variableDeclarations(config, names))},
+	}
+}
+
+// interfaceFile returns the file variables or outputs are declared in:
+// the standard file, another file with the same suffix such as
+// network_variables.tf, or the standard file if neither exists
+func interfaceFile(config *TerraformConfiguration, standard string) string {
+	if _, ok := config.Files[standard]; ok {
+		return standard
+	}
+	var names []string
+	for name := range config.Files {
+		if strings.HasSuffix(name, "_"+standard) && !strings.Contains(name, "/") {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return standard
+	}
+	sort.Strings(names)
+	return names[0]
+}
+
+// appendBlocks returns an edit appending blocks to the end of a file, or
+// creating the file with the given content if it does not exist
+func appendBlocks(config *TerraformConfiguration, file, created, blocks string) TextEdit {
+	content, ok := config.Files[file]
+	if !ok {
+		return TextEdit{File: file, NewText: created}
+	}
+
+	text := blocks
+	if content != "" {
+		text = "\n" + text
+		if !strings.HasSuffix(content, "\n") {
+			text = "\n" + text
+		}
+	}
+	end := bytePosition([]byte(content), len(content))
+	return TextEdit{File: file, Start: end, End: end, NewText: text}
+}
+
+// pluralNames describes a list of names, e.g. variables 'a' and 'b'
+func pluralNames(kind string, names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = "'" + name + "'"
+	}
+	if len(quoted) == 1 {
+		return kind + " " + quoted[0]
+	}
+	return fmt.Sprintf("%ss %s and %s", kind, strings.Join(quoted[:len(quoted)-1], ", "), quoted[len(quoted)-1])
+}
+
+// outputAttributes are the attributes of resource types commonly consumed
+// by callers of a module, such as IDs, ARNs and endpoints
+var outputAttributes = map[string][]string{
+	"aws_cloudfront_distribution":       {"id", "domain_name"},
+	"aws_db_instance":                   {"arn", "endpoint"},
+	"aws_dynamodb_table":                {"arn", "name"},
+	"aws_ecr_repository":                {"arn", "repository_url"},
+	"aws_ecs_cluster":                   {"arn"},
+	"aws_eks_cluster":                   {"arn", "endpoint"},
+	"aws_elasticache_replication_group": {"primary_endpoint_address"},
+	"aws_iam_policy":                    {"arn"},
+	"aws_iam_role":                      {"arn", "name"},
+	"aws_instance":                      {"id", "private_ip"},
+	"aws_kms_key":                       {"arn", "key_id"},
+	"aws_lambda_function":               {"arn", "function_name"},
+	"aws_lb":                            {"arn", "dns_name"},
+	"aws_lb_target_group":               {"arn"},
+	"aws_rds_cluster":                   {"arn", "endpoint", "reader_endpoint"},
+	"aws_route53_zone":                  {"zone_id", "name_servers"},
+	"aws_s3_bucket":                     {"id", "arn"},
+	"aws_secretsmanager_secret":         {"arn"},
+	"aws_security_group":                {"id"},
+	"aws_sns_topic":                     {"arn"},
+	"aws_sqs_queue":                     {"arn", "url"},
+	"aws_subnet":                        {"id"},
+	"aws_vpc":                           {"id"},
+	"azurerm_key_vault":                 {"id", "vault_uri"},
+	"azurerm_kubernetes_cluster":        {"id", "name"},
+	"azurerm_linux_virtual_machine":     {"id"},
+	"azurerm_resource_group":            {"id", "name"},
+	"azurerm_storage_account":           {"id", "primary_blob_endpoint"},
+	"azurerm_subnet":                    {"id"},
+	"azurerm_virtual_network":           {"id"},
+	"google_compute_network":            {"id", "self_link"},
+	"google_compute_subnetwork":         {"id", "self_link"},
+	"google_container_cluster":          {"id", "endpoint"},
+	"google_service_account":            {"email"},
+	"google_sql_database_instance":      {"connection_name"},
+	"google_storage_bucket":             {"name", "url"},
+}
+
+// outputAcronyms are attribute words written in upper case in descriptions
+var outputAcronyms = map[string]string{"id": "ID", "arn": "ARN", "url": "URL", "dns": "DNS", "ip": "IP", "uri": "URI"}
+
+// SuggestedOutput is an output proposed for a resource attribute that is
+// commonly consumed by callers of a module
+type SuggestedOutput struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Value       string `json:"value"`
+	Resource    string `json:"resource"`
+	Attribute   string `json:"attribute"`
+	File        string `json:"file"`
+	Line        int    `json:"line"`
+}
+
+// SuggestedOutputs proposes outputs for the key attributes of the managed
+// resources of a configuration that are not exposed by an output yet
+func (c *TerraformConfiguration) SuggestedOutputs() []SuggestedOutput {
+	outputs := c.Blocks("output")
+	declared := make(map[string]bool, len(outputs))
+	for _, block := range outputs {
+		declared[block.Label(0)] = true
+	}
+
+	var suggestions []SuggestedOutput
+	for _, block := range c.Blocks("resource") {
+		for _, attribute := range outputAttributes[block.Label(0)] {
+			if outputExposes(outputs, block.Address(), attribute) {
+				continue
+			}
+			suggestion := suggestOutput(block, attribute)
+			if declared[suggestion.Name] {
+				continue
+			}
+			declared[suggestion.Name] = true
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+	return suggestions
+}
+
+// suggestOutput builds the output for an attribute of a resource. Outputs of
+// resources using count or for_each hold the attribute of every instance.
+func suggestOutput(block *Block, attribute string) SuggestedOutput {
+	resourceType, name := block.Label(0), block.Label(1)
+	kind := resourceType[strings.Index(resourceType, "_")+1:]
+
+	// Drop the type from attributes repeating it, e.g. repository_url
+	words := strings.Split(kind, "_")
+	outputName := strings.TrimPrefix(attribute, words[len(words)-1]+"_")
+	outputName = kind + "_" + outputName
+	if name != "this" && name != "main" && name != "default" && name != kind {
+		outputName = name + "_" + outputName
+	}
+
+	var label []string
+	for _, word := range strings.Split(attribute, "_") {
+		label = append(label, firstType(outputAcronyms[word], word))
+	}
+
+	suggestion := SuggestedOutput{
+		Name:        outputName,
+		Description: fmt.Sprintf("The %s of %s", strings.Join(label, " "), block.Address()),
+		Value:       block.Address() + "." + attribute,
+		Resource:    block.Address(),
+		Attribute:   attribute,
+		File:        block.File,
+		Line:        block.Line,
+	}
+	if _, ok := block.Attributes["count"]; ok {
+		suggestion.Value = fmt.Sprintf("%s[*].%s", block.Address(), attribute)
+	} else if _, ok := block.Attributes["for_each"]; ok {
+		suggestion.Value = fmt.Sprintf("{ for key, value in %s : key => value.%s }", block.Address(), attribute)
+	} else {
+		return suggestion
+	}
+
+	if !strings.HasSuffix(suggestion.Name, "s") {
+		suggestion.Name += "s"
+	}
+	suggestion.Description = fmt.Sprintf("The %s of each instance of %s", strings.Join(label, " "), block.Address())
+	return suggestion
+}
+
+// outputExposes reports whether an output already exposes an attribute of
+// a resource
+func outputExposes(outputs []*Block, address, attribute string) bool {
+	for _, block := range outputs {
+		value, ok := block.Attributes["value"]
+		if !ok || !strings.Contains(value.Source, address) {
+			continue
+		}
+		for rest := value.Source; ; {
+			i := strings.Index(rest, "."+attribute)
+			if i < 0 {
+				break
+			}
+			rest = rest[i+len(attribute)+1:]
+			if rest == "" || !isIdentifierByte(rest[0]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isIdentifierByte reports whether a byte can be part of an identifier
+func isIdentifierByte(b byte) bool {
+	return b == '_' || b == '-' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9'
+}
+
+// outputDeclarations returns output blocks for suggested outputs
+func outputDeclarations(suggestions []SuggestedOutput) string {
+	blocks := make([]string, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		blocks = append(blocks, fmt.Sprintf("output %q {\n  description = %q\n  value       = %s\n}\n",
+			suggestion.Name, suggestion.Description, suggestion.Value))
+	}
+	return strings.Join(blocks, "\n")
+}
+
+// suggestedOutputsIssue reports the key resource attributes a module does
+// not expose, with a fix adding the outputs
+func suggestedOutputsIssue(config *TerraformConfiguration) []ValidationIssue {
+	suggestions := config.SuggestedOutputs()
+	if len(suggestions) == 0 {
+		return nil
+	}
+
+	names := make([]string, len(suggestions))
+	values := make([]string, len(suggestions))
+	for i, suggestion := range suggestions {
+		names[i] = suggestion.Name
+		values[i] = suggestion.Resource + "." + suggestion.Attribute
+	}
+
+	file := interfaceFile(config, "outputs.tf")
+	return []ValidationIssue{{
+		Message:      fmt.Sprintf("Module does not expose %s as outputs", strings.Join(values, ", ")),
+		RuleID:       "suggested-outputs",
+		Severity:     SeverityInfo,
+		Category:     CategoryStructure,
+		File:         suggestions[0].File,
+		Line:         suggestions[0].Line,
+		BestPractice: "Expose the IDs, ARNs and endpoints of key resources as outputs so callers can consume them",
+		Suggestion:   fmt.Sprintf("Add %s to %s", pluralNames("output", names), file),
+		Fix: &Fix{
+			Description: fmt.Sprintf("Add %s to %s", pluralNames("output", names), file),
+			Edits:       []TextEdit{appendBlocks(config, file, generateOutputsTF(config), outputDeclarations(suggestions))},
+		},
+	}}
+}
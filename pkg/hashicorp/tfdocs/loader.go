@@ -0,0 +1,256 @@
+// pkg/hashicorp/tfdocs/loader.go
+package tfdocs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ModuleDirectory is a directory containing Terraform files, loaded as a
+// configuration of its own
+type ModuleDirectory struct {
+	// Path is the slash-separated path of the directory relative to the root
+	Path   string
+	Config *TerraformConfiguration
+}
+
+// isModuleFile reports whether a file is loaded into a configuration
+func isModuleFile(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.HasSuffix(lower, ".tf") ||
+		strings.HasSuffix(lower, ".tfvars") ||
+		strings.HasSuffix(lower, ".tftest.hcl") ||
+		lower == "readme.md"
+}
+
+// discoverOptions configures module discovery
+type discoverOptions struct {
+	recursive bool
+}
+
+// DiscoverOption is a function that configures module discovery
+type DiscoverOption func(*discoverOptions)
+
+// WithRecursion sets whether subdirectories are searched for modules.
+// Recursion is enabled by default.
+func WithRecursion(recursive bool) DiscoverOption {
+	return func(o *discoverOptions) {
+		o.recursive = recursive
+	}
+}
+
+// DiscoverModules walks a directory tree and loads every directory that
+// contains .tf files as a module. Paths matched by the .terraformignore file
+// at the root or by .gitignore files along the way are skipped. Symbolic
+// links are never followed.
+func DiscoverModules(root string, options ...DiscoverOption) ([]*ModuleDirectory, error) {
+	opts := discoverOptions{recursive: true}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", root, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", root)
+	}
+
+	ignore, err := LoadIgnoreFile(filepath.Join(root, ".terraformignore"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read .terraformignore: %w", err)
+	}
+
+	// .gitignore files apply to the directory they are in and below
+	gitignores := make(map[string]*IgnoreRules)
+	ignored := func(rel string, isDir bool) bool {
+		if ignore.Match(rel, isDir) {
+			return true
+		}
+		result := false
+		for dir := path.Dir(rel); ; dir = path.Dir(dir) {
+			if rules, ok := gitignores[dir]; ok {
+				relToDir := rel
+				if dir != "." {
+					relToDir = strings.TrimPrefix(rel, dir+"/")
+				}
+				if matched, skip := rules.match(relToDir, isDir); matched {
+					result = skip
+					break
+				}
+			}
+			if dir == "." {
+				break
+			}
+		}
+		return result
+	}
+	loadGitignore := func(dir, rel string) error {
+		data, err := ioutil.ReadFile(filepath.Join(dir, ".gitignore"))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return fmt.Errorf("failed to read .gitignore: %w", err)
+		}
+		gitignores[rel] = ParseIgnorePatterns(strings.Split(string(data), "\n"))
+		return nil
+	}
+
+	modules := make(map[string]*ModuleDirectory)
+	err = filepath.Walk(root, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, file)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			return loadGitignore(file, rel)
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			return nil
+		}
+		if ignored(rel, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			if !opts.recursive {
+				return filepath.SkipDir
+			}
+			return loadGitignore(file, rel)
+		}
+		if !info.Mode().IsRegular() || !isModuleFile(info.Name()) {
+			return nil
+		}
+
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+
+		dir := path.Dir(rel)
+		module, ok := modules[dir]
+		if !ok {
+			module = &ModuleDirectory{
+				Path:   dir,
+				Config: &TerraformConfiguration{Files: make(map[string]string)},
+			}
+			modules[dir] = module
+		}
+		module.Config.Files[path.Base(rel)] = string(content)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", root, err)
+	}
+
+	// Only directories with Terraform files are modules
+	isModule := func(module *ModuleDirectory) bool {
+		for name := range module.Config.Files {
+			if strings.HasSuffix(name, ".tf") {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Test files in a tests directory belong to the module above it
+	for dir, tests := range modules {
+		parent, ok := modules[path.Dir(dir)]
+		if !ok || path.Base(dir) != "tests" || isModule(tests) {
+			continue
+		}
+		for name, content := range tests.Config.Files {
+			if isTestFile(name) {
+				parent.Config.Files["tests/"+name] = content
+			}
+		}
+	}
+
+	var result []*ModuleDirectory
+	for _, module := range modules {
+		if isModule(module) {
+			result = append(result, module)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Path < result[j].Path
+	})
+
+	return result, nil
+}
+
+// CombineModules combines the discovered modules into one configuration,
+// keyed by their paths relative to the root
+func CombineModules(modules []*ModuleDirectory) *TerraformConfiguration {
+	combined := &TerraformConfiguration{Files: make(map[string]string)}
+	for _, module := range modules {
+		for name, content := range module.Config.Files {
+			combined.Files[path.Join(module.Path, name)] = content
+		}
+	}
+	return combined
+}
+
+// ValidateModules combines the discovered modules into one configuration,
+// keyed by their paths relative to the root, and validates it as a module
+// tree. Issues that are not tied to a file are reported against the root
+// module's main file.
+func (e *ValidationEngine) ValidateModules(modules []*ModuleDirectory) (*ValidationResult, *TerraformConfiguration, error) {
+	combined := CombineModules(modules)
+
+	result, err := e.ValidateConfiguration(combined)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i, issue := range result.Issues {
+		if issue.File == "" {
+			result.Issues[i].File, result.Issues[i].Line = issueLocation(issue, combined)
+		}
+	}
+
+	return result, combined, nil
+}
+
+// relocateFix returns a copy of a fix with its file paths prefixed by a directory
+func relocateFix(fix *Fix, dir string) *Fix {
+	relocated := &Fix{Description: fix.Description}
+	for _, edit := range fix.Edits {
+		edit.File = path.Join(dir, edit.File)
+		relocated.Edits = append(relocated.Edits, edit)
+	}
+	return relocated
+}
+
+// SeverityAtLeast reports whether a severity is at or above a threshold
+func SeverityAtLeast(severity, threshold ValidationSeverity) bool {
+	return severityRank(severity) >= severityRank(threshold)
+}
+
+// severityRank orders severities from info to error
+func severityRank(severity ValidationSeverity) int {
+	switch severity {
+	case SeverityError:
+		return 3
+	case SeverityWarning:
+		return 2
+	case SeverityInfo:
+		return 1
+	}
+	return 0
+}
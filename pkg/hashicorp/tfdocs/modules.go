@@ -0,0 +1,316 @@
+// pkg/hashicorp/tfdocs/modules.go
+package tfdocs
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// ModuleTree is a configuration split into the root module and the local
+// modules in its subdirectories
+type ModuleTree struct {
+	Root *ModuleNode
+	// Modules holds all modules, including the root, ordered by path
+	Modules []*ModuleNode
+}
+
+// ModuleNode is a module of a module tree. Its configuration holds the files
+// of the module directory keyed by their base name.
+type ModuleNode struct {
+	// Path is the slash-separated directory of the module, "." for the root
+	Path    string
+	Config  *TerraformConfiguration
+	Calls   []*ModuleCall
+	Callers []*ModuleCall
+}
+
+// ModuleCall is a module block with a local source
+type ModuleCall struct {
+	Name   string
+	Source string
+	Block  *Block
+	Caller *ModuleNode
+	// Module is the called module, or nil if its files are not part of the
+	// configuration
+	Module *ModuleNode
+}
+
+// isLocalSource reports whether a module source refers to a local directory
+func isLocalSource(source string) bool {
+	return strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../")
+}
+
+// ModuleTree splits the configuration into modules by directory and
+// resolves the module blocks with local sources. Every directory containing
+// .tf files is a module. The root directory is always part of the tree, and
+// test files in a tests directory are part of the module above it.
+func (c *TerraformConfiguration) ModuleTree() *ModuleTree {
+	nodes := make(map[string]*ModuleNode)
+	node := func(dir string) *ModuleNode {
+		if n, ok := nodes[dir]; ok {
+			return n
+		}
+		n := &ModuleNode{Path: dir, Config: &TerraformConfiguration{Files: make(map[string]string)}}
+		nodes[dir] = n
+		return n
+	}
+
+	node(".")
+	for name := range c.Files {
+		if strings.HasSuffix(name, ".tf") {
+			node(path.Dir(name))
+		}
+	}
+	for name, content := range c.Files {
+		dir, base := path.Dir(name), path.Base(name)
+		// Test files in a tests directory belong to the module above it
+		if _, ok := nodes[dir]; !ok && isTestFile(name) && path.Base(dir) == "tests" {
+			dir, base = path.Dir(dir), "tests/"+base
+		}
+		if n, ok := nodes[dir]; ok {
+			n.Config.Files[base] = content
+		}
+	}
+
+	tree := &ModuleTree{Root: nodes["."]}
+	for _, n := range nodes {
+		tree.Modules = append(tree.Modules, n)
+	}
+	sort.Slice(tree.Modules, func(i, j int) bool {
+		return tree.Modules[i].Path < tree.Modules[j].Path
+	})
+
+	// Resolve local module calls
+	for _, n := range tree.Modules {
+		for _, block := range n.Config.Blocks("module") {
+			attr, ok := block.Attributes["source"]
+			if !ok {
+				continue
+			}
+			source, known := attr.StringValue()
+			if !known || !isLocalSource(source) {
+				continue
+			}
+
+			call := &ModuleCall{
+				Name:   block.Label(0),
+				Source: source,
+				Block:  block,
+				Caller: n,
+				Module: nodes[path.Clean(path.Join(n.Path, source))],
+			}
+			n.Calls = append(n.Calls, call)
+			if call.Module != nil {
+				call.Module.Callers = append(call.Module.Callers, call)
+			}
+		}
+	}
+
+	return tree
+}
+
+// IsRoot reports whether the module is the root module
+func (n *ModuleNode) IsRoot() bool {
+	return n.Path == "."
+}
+
+// HasTerraformFiles reports whether the module contains any .tf files
+func (n *ModuleNode) HasTerraformFiles() bool {
+	for name := range n.Config.Files {
+		if strings.HasSuffix(name, ".tf") {
+			return true
+		}
+	}
+	return false
+}
+
+// FilePath returns the path of a module file relative to the configuration
+func (n *ModuleNode) FilePath(name string) string {
+	return path.Join(n.Path, name)
+}
+
+// ModuleTreeValidator reports issues spanning modules: local modules that are
+// never called, module calls missing required inputs, references to outputs
+// a module does not declare, and conflicting version constraints
+type ModuleTreeValidator struct{}
+
+// Name returns the name of the validator
+func (v *ModuleTreeValidator) Name() string {
+	return "ModuleTreeValidator"
+}
+
+// Validate validates the module tree of a Terraform configuration
+func (v *ModuleTreeValidator) Validate(config *TerraformConfiguration) []ValidationIssue {
+	var issues []ValidationIssue
+
+	tree := config.ModuleTree()
+	for _, module := range tree.Modules {
+		// Check for local modules that are never called
+		if isLocalModuleDir(module.Path) && len(module.Callers) == 0 {
+			issues = append(issues, ValidationIssue{
+				Message:      fmt.Sprintf("Local module '%s' is not called by any module", module.Path),
+				RuleID:       "unused-local-modules",
+				Severity:     SeverityInfo,
+				Category:     CategoryMaintenance,
+				File:         module.FilePath(firstTerraformFile(module)),
+				Line:         1,
+				BestPractice: "Remove local modules that are no longer used",
+				Suggestion:   fmt.Sprintf("Call the module in '%s' or remove it", module.Path),
+			})
+		}
+
+		for _, call := range module.Calls {
+			if call.Module == nil {
+				continue
+			}
+			issues = append(issues, missingInputIssues(call)...)
+		}
+
+		issues = append(issues, undeclaredOutputIssues(module)...)
+	}
+
+	issues = append(issues, versionConflictIssues(tree)...)
+
+	return issues
+}
+
+// isLocalModuleDir reports whether a directory is a local module by convention
+func isLocalModuleDir(dir string) bool {
+	return strings.HasPrefix(dir, "modules/") || strings.Contains(dir, "/modules/")
+}
+
+// firstTerraformFile returns main.tf or the first .tf file of a module
+func firstTerraformFile(module *ModuleNode) string {
+	if _, ok := module.Config.Files["main.tf"]; ok {
+		return "main.tf"
+	}
+	var names []string
+	for name := range module.Config.Files {
+		if strings.HasSuffix(name, ".tf") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return ""
+	}
+	return names[0]
+}
+
+// missingInputIssues reports the variables without a default that a module
+// call does not set
+func missingInputIssues(call *ModuleCall) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, variable := range call.Module.Config.Blocks("variable") {
+		name := variable.Label(0)
+		if _, ok := variable.Attributes["default"]; ok {
+			continue
+		}
+		if _, ok := call.Block.Attributes[name]; ok {
+			continue
+		}
+
+		issues = append(issues, ValidationIssue{
+			Message:      fmt.Sprintf("Module '%s' is missing required input '%s' declared in %s", call.Name, name, call.Module.FilePath(variable.File)),
+			RuleID:       "module-required-inputs",
+			Severity:     SeverityError,
+			Category:     CategoryStructure,
+			File:         call.Caller.FilePath(call.Block.File),
+			Line:         call.Block.Line,
+			BestPractice: "Set every required input of a module call",
+			Suggestion:   fmt.Sprintf("Add '%s' to module '%s' or give the variable a default", name, call.Name),
+		})
+	}
+	return issues
+}
+
+// undeclaredOutputIssues reports references to module outputs that the
+// called module does not declare
+func undeclaredOutputIssues(module *ModuleNode) []ValidationIssue {
+	var issues []ValidationIssue
+	if len(module.Calls) == 0 {
+		return issues
+	}
+
+	calls := make(map[string]*ModuleCall)
+	for _, call := range module.Calls {
+		if call.Module != nil {
+			calls[call.Name] = call
+		}
+	}
+
+	for _, file := range module.Config.ParsedFiles() {
+		for _, attr := range file.AllAttributes() {
+			for _, traversal := range attr.Expr.Variables() {
+				name, output, rng, ok := moduleOutputReference(traversal)
+				if !ok {
+					continue
+				}
+				call, ok := calls[name]
+				if !ok || hasOutput(call.Module, output) {
+					continue
+				}
+
+				issues = append(issues, ValidationIssue{
+					Message:      fmt.Sprintf("Module '%s' does not declare output '%s'", name, output),
+					RuleID:       "module-undeclared-outputs",
+					Severity:     SeverityError,
+					Category:     CategoryStructure,
+					File:         module.FilePath(file.Name),
+					Line:         rng.Start.Line,
+					BestPractice: "Only reference outputs that the called module declares",
+					Suggestion:   fmt.Sprintf("Declare output '%s' in %s or reference an existing output", output, call.Module.Path),
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// moduleOutputReference extracts the module name and output of a
+// module.<name>.<output> reference, skipping index steps of modules using
+// count or for_each
+func moduleOutputReference(traversal hcl.Traversal) (string, string, hcl.Range, bool) {
+	if traversal.RootName() != "module" || len(traversal) < 3 {
+		return "", "", hcl.Range{}, false
+	}
+	name, ok := traversal[1].(hcl.TraverseAttr)
+	if !ok {
+		return "", "", hcl.Range{}, false
+	}
+	for _, step := range traversal[2:] {
+		switch s := step.(type) {
+		case hcl.TraverseAttr:
+			return name.Name, s.Name, traversal.SourceRange(), true
+		case hcl.TraverseIndex, hcl.TraverseSplat:
+			continue
+		default:
+			return "", "", hcl.Range{}, false
+		}
+	}
+	return "", "", hcl.Range{}, false
+}
+
+// hasOutput reports whether a module declares an output
+func hasOutput(module *ModuleNode, name string) bool {
+	for _, output := range module.Config.Blocks("output") {
+		if output.Label(0) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// DescribeRules returns the rules checked by the validator
+func (v *ModuleTreeValidator) DescribeRules() []RuleMetadata {
+	return []RuleMetadata{
+		{ID: "unused-local-modules", Name: "UnusedLocalModules", Description: "Remove local modules that are no longer used", Severity: SeverityInfo, Category: CategoryMaintenance},
+		{ID: "module-required-inputs", Name: "ModuleRequiredInputs", Description: "Set every required input of a module call", Severity: SeverityError, Category: CategoryStructure},
+		{ID: "module-undeclared-outputs", Name: "ModuleUndeclaredOutputs", Description: "Only reference outputs that the called module declares", Severity: SeverityError, Category: CategoryStructure},
+		{ID: "version-conflicts", Name: "VersionConflicts", Description: "Version constraints on the same provider must allow at least one common version", Severity: SeverityError, Category: CategoryStructure},
+	}
+}
@@ -0,0 +1,333 @@
+// pkg/hashicorp/tfdocs/parser.go
+package tfdocs
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ParsedFile represents the parsed HCL syntax of a single Terraform file
+type ParsedFile struct {
+	Name        string
+	Source      []byte
+	Blocks      []*Block
+	Attributes  map[string]*Attribute
+	Diagnostics hcl.Diagnostics
+}
+
+// Block represents a block in a Terraform file, such as a resource or variable
+type Block struct {
+	Type            string
+	Labels          []string
+	File            string
+	Line            int
+	Range           hcl.Range
+	LabelRanges     []hcl.Range
+	OpenBraceRange  hcl.Range
+	CloseBraceRange hcl.Range
+	Attributes      map[string]*Attribute
+	Blocks          []*Block
+	Parent          *Block
+}
+
+// Attribute represents an attribute assignment inside a block
+type Attribute struct {
+	Name   string
+	Expr   hclsyntax.Expression
+	Source string
+	File   string
+	Line   int
+	Range  hcl.Range
+}
+
+// parse parses all .tf files of the configuration, caching the result until
+// the file contents change
+func (c *TerraformConfiguration) parse() map[string]*ParsedFile {
+	if c.parsed == nil {
+		c.parsed = make(map[string]*ParsedFile)
+	}
+
+	for name, content := range c.Files {
+		if !strings.HasSuffix(name, ".tf") {
+			continue
+		}
+		if cached, ok := c.parsed[name]; ok && string(cached.Source) == content {
+			continue
+		}
+		c.parsed[name] = parseFile(name, content)
+	}
+
+	for name := range c.parsed {
+		if _, ok := c.Files[name]; !ok {
+			delete(c.parsed, name)
+		}
+	}
+
+	return c.parsed
+}
+
+// ParsedFiles returns the parsed .tf files of the configuration sorted by name
+func (c *TerraformConfiguration) ParsedFiles() []*ParsedFile {
+	parsed := c.parse()
+
+	files := make([]*ParsedFile, 0, len(parsed))
+	for _, file := range parsed {
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name < files[j].Name
+	})
+
+	return files
+}
+
+// Blocks returns all top-level blocks of the given type across the configuration.
+// An empty block type returns every top-level block.
+func (c *TerraformConfiguration) Blocks(blockType string) []*Block {
+	var blocks []*Block
+	for _, file := range c.ParsedFiles() {
+		for _, block := range file.Blocks {
+			if blockType == "" || block.Type == blockType {
+				blocks = append(blocks, block)
+			}
+		}
+	}
+	return blocks
+}
+
+// ParsedFile returns the parsed file with the given name, or nil if it is
+// not a .tf file of the configuration
+func (c *TerraformConfiguration) ParsedFile(name string) *ParsedFile {
+	return c.parse()[name]
+}
+
+// parseFile parses the content of a single Terraform file
+func parseFile(name, content string) *ParsedFile {
+	src := []byte(content)
+	file := &ParsedFile{
+		Name:       name,
+		Source:     src,
+		Attributes: make(map[string]*Attribute),
+	}
+
+	hclFile, diags := hclsyntax.ParseConfig(src, name, hcl.Pos{Line: 1, Column: 1, Byte: 0})
+	file.Diagnostics = diags
+	if hclFile == nil {
+		return file
+	}
+
+	body, ok := hclFile.Body.(*hclsyntax.Body)
+	if !ok {
+		return file
+	}
+
+	file.Attributes = convertAttributes(body, name, src)
+	for _, block := range body.Blocks {
+		file.Blocks = append(file.Blocks, convertBlock(block, nil, name, src))
+	}
+
+	return file
+}
+
+// convertBlock converts an hclsyntax block into a Block
+func convertBlock(block *hclsyntax.Block, parent *Block, name string, src []byte) *Block {
+	b := &Block{
+		Type:            block.Type,
+		Labels:          block.Labels,
+		File:            name,
+		Line:            block.TypeRange.Start.Line,
+		Range:           block.Range(),
+		LabelRanges:     block.LabelRanges,
+		OpenBraceRange:  block.OpenBraceRange,
+		CloseBraceRange: block.CloseBraceRange,
+		Attributes:      convertAttributes(block.Body, name, src),
+		Parent:          parent,
+	}
+
+	for _, nested := range block.Body.Blocks {
+		b.Blocks = append(b.Blocks, convertBlock(nested, b, name, src))
+	}
+
+	return b
+}
+
+// convertAttributes converts the attributes of an hclsyntax body
+func convertAttributes(body *hclsyntax.Body, name string, src []byte) map[string]*Attribute {
+	attributes := make(map[string]*Attribute, len(body.Attributes))
+	for attrName, attr := range body.Attributes {
+		attributes[attrName] = &Attribute{
+			Name:   attrName,
+			Expr:   attr.Expr,
+			Source: string(attr.Expr.Range().SliceBytes(src)),
+			File:   name,
+			Line:   attr.SrcRange.Start.Line,
+			Range:  attr.SrcRange,
+		}
+	}
+	return attributes
+}
+
+// Label returns the label at the given index, or an empty string
+func (b *Block) Label(index int) string {
+	if index < len(b.Labels) {
+		return b.Labels[index]
+	}
+	return ""
+}
+
+// Address returns the Terraform address of a top-level block, e.g. aws_s3_bucket.logs
+func (b *Block) Address() string {
+	switch b.Type {
+	case "resource":
+		return strings.Join(b.Labels, ".")
+	case "data":
+		return "data." + strings.Join(b.Labels, ".")
+	case "module":
+		return "module." + b.Label(0)
+	case "variable":
+		return "var." + b.Label(0)
+	case "output":
+		return "output." + b.Label(0)
+	}
+	return strings.Join(append([]string{b.Type}, b.Labels...), ".")
+}
+
+// NestedBlocks returns the nested blocks of the given type
+func (b *Block) NestedBlocks(blockType string) []*Block {
+	var blocks []*Block
+	for _, nested := range b.Blocks {
+		if nested.Type == blockType {
+			blocks = append(blocks, nested)
+		}
+	}
+	return blocks
+}
+
+// FindAttributes resolves a dotted attribute path, where all but the last
+// element name nested blocks, e.g. "versioning.enabled"
+func (b *Block) FindAttributes(path string) []*Attribute {
+	parts := strings.Split(path, ".")
+	blocks := []*Block{b}
+	for _, part := range parts[:len(parts)-1] {
+		var next []*Block
+		for _, block := range blocks {
+			next = append(next, block.NestedBlocks(part)...)
+		}
+		blocks = next
+	}
+
+	var attributes []*Attribute
+	for _, block := range blocks {
+		if attr, ok := block.Attributes[parts[len(parts)-1]]; ok {
+			attributes = append(attributes, attr)
+		}
+	}
+	return attributes
+}
+
+// AllAttributes returns the attributes of the block and all nested blocks
+func (b *Block) AllAttributes() []*Attribute {
+	attributes := sortedAttributes(b.Attributes)
+	for _, nested := range b.Blocks {
+		attributes = append(attributes, nested.AllAttributes()...)
+	}
+	return attributes
+}
+
+// AllAttributes returns every attribute of the file, including those of nested blocks
+func (f *ParsedFile) AllAttributes() []*Attribute {
+	attributes := sortedAttributes(f.Attributes)
+	for _, block := range f.Blocks {
+		attributes = append(attributes, block.AllAttributes()...)
+	}
+	return attributes
+}
+
+// sortedAttributes returns the attributes of a map in source order
+func sortedAttributes(attrs map[string]*Attribute) []*Attribute {
+	attributes := make([]*Attribute, 0, len(attrs))
+	for _, attr := range attrs {
+		attributes = append(attributes, attr)
+	}
+	sort.Slice(attributes, func(i, j int) bool {
+		return attributes[i].Range.Start.Byte < attributes[j].Range.Start.Byte
+	})
+	return attributes
+}
+
+// Value evaluates the attribute as a constant expression. The second return
+// value is false when the expression depends on variables, functions or
+// other values only known to Terraform at plan time.
+func (a *Attribute) Value() (cty.Value, bool) {
+	if len(a.Expr.Variables()) > 0 {
+		return cty.NilVal, false
+	}
+
+	value, diags := a.Expr.Value(nil)
+	if diags.HasErrors() || !value.IsWhollyKnown() {
+		return cty.NilVal, false
+	}
+
+	return value, true
+}
+
+// StringValue returns the constant value of the attribute as a string, if
+// it is a primitive constant
+func (a *Attribute) StringValue() (string, bool) {
+	value, ok := a.Value()
+	if !ok {
+		return "", false
+	}
+	return ctyPrimitiveString(value)
+}
+
+// References returns the references of the attribute expression as
+// dotted strings, e.g. var.name or aws_s3_bucket.this.id
+func (a *Attribute) References() []string {
+	var refs []string
+	for _, traversal := range a.Expr.Variables() {
+		refs = append(refs, traversalString(traversal))
+	}
+	return refs
+}
+
+// traversalString renders the attribute steps of a traversal as a dotted string
+func traversalString(traversal hcl.Traversal) string {
+	var parts []string
+	for _, step := range traversal {
+		switch s := step.(type) {
+		case hcl.TraverseRoot:
+			parts = append(parts, s.Name)
+		case hcl.TraverseAttr:
+			parts = append(parts, s.Name)
+		default:
+			return strings.Join(parts, ".")
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// ctyPrimitiveString renders a primitive cty value as a string
+func ctyPrimitiveString(value cty.Value) (string, bool) {
+	if value.IsNull() {
+		return "null", true
+	}
+
+	switch value.Type() {
+	case cty.String:
+		return value.AsString(), true
+	case cty.Bool:
+		if value.True() {
+			return "true", true
+		}
+		return "false", true
+	case cty.Number:
+		return value.AsBigFloat().Text('f', -1), true
+	}
+
+	return "", false
+}
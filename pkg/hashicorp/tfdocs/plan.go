@@ -0,0 +1,366 @@
+// pkg/hashicorp/tfdocs/plan.go
+package tfdocs
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Plan is the JSON representation of a plan, as produced by
+// terraform show -json <planfile>
+type Plan struct {
+	FormatVersion    string           `json:"format_version"`
+	TerraformVersion string           `json:"terraform_version,omitempty"`
+	PlannedValues    PlanValues       `json:"planned_values"`
+	ResourceChanges  []ResourceChange `json:"resource_changes"`
+}
+
+// PlanValues holds the values of all resources after the plan is applied
+type PlanValues struct {
+	RootModule PlanModule `json:"root_module"`
+}
+
+// PlanModule holds the resources of a module and its child modules
+type PlanModule struct {
+	Address      string         `json:"address,omitempty"`
+	Resources    []PlanResource `json:"resources,omitempty"`
+	ChildModules []PlanModule   `json:"child_modules,omitempty"`
+}
+
+// PlanResource is a resource instance with its planned values. Values only
+// known after apply are omitted.
+type PlanResource struct {
+	Address      string                 `json:"address"`
+	Mode         string                 `json:"mode"`
+	Type         string                 `json:"type"`
+	Name         string                 `json:"name"`
+	ProviderName string                 `json:"provider_name"`
+	Values       map[string]interface{} `json:"values"`
+}
+
+// ResourceChange is the planned change of a resource instance
+type ResourceChange struct {
+	Address       string     `json:"address"`
+	ModuleAddress string     `json:"module_address,omitempty"`
+	Mode          string     `json:"mode"`
+	Type          string     `json:"type"`
+	Name          string     `json:"name"`
+	ProviderName  string     `json:"provider_name"`
+	Change        PlanChange `json:"change"`
+	ActionReason  string     `json:"action_reason,omitempty"`
+}
+
+// PlanChange holds the actions and the values before and after a change
+type PlanChange struct {
+	Actions      []string               `json:"actions"`
+	Before       map[string]interface{} `json:"before"`
+	After        map[string]interface{} `json:"after"`
+	ReplacePaths [][]interface{}        `json:"replace_paths,omitempty"`
+}
+
+// ChangeAction is the effective action of a resource change
+type ChangeAction string
+
+const (
+	// ActionNoOp leaves the resource unchanged
+	ActionNoOp ChangeAction = "no-op"
+	// ActionCreate creates the resource
+	ActionCreate ChangeAction = "create"
+	// ActionRead reads a data source
+	ActionRead ChangeAction = "read"
+	// ActionUpdate updates the resource in place
+	ActionUpdate ChangeAction = "update"
+	// ActionReplace destroys and recreates the resource
+	ActionReplace ChangeAction = "replace"
+	// ActionDelete destroys the resource
+	ActionDelete ChangeAction = "delete"
+)
+
+// ParsePlan parses the JSON output of terraform show -json for a plan file
+func ParsePlan(data []byte) (*Plan, error) {
+	var plan Plan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse plan: %w", err)
+	}
+	if plan.FormatVersion == "" {
+		return nil, fmt.Errorf("failed to parse plan: missing format_version, expected the output of terraform show -json")
+	}
+	return &plan, nil
+}
+
+// Action returns the effective action of the change. Terraform reports a
+// replacement as a delete and a create in either order.
+func (c ResourceChange) Action() ChangeAction {
+	actions := c.Change.Actions
+	switch {
+	case len(actions) == 2:
+		return ActionReplace
+	case len(actions) == 1:
+		return ChangeAction(actions[0])
+	}
+	return ActionNoOp
+}
+
+// ReplacedBy returns the attribute paths that force a replacement, e.g.
+// engine_version or ingress.0.cidr_blocks
+func (c ResourceChange) ReplacedBy() []string {
+	var paths []string
+	for _, steps := range c.Change.ReplacePaths {
+		parts := make([]string, 0, len(steps))
+		for _, step := range steps {
+			parts = append(parts, fmt.Sprint(step))
+		}
+		paths = append(paths, strings.Join(parts, "."))
+	}
+	return paths
+}
+
+// Resources returns the managed resources of the planned values of all modules
+func (p *Plan) Resources() []PlanResource {
+	var resources []PlanResource
+	var walk func(module PlanModule)
+	walk = func(module PlanModule) {
+		for _, resource := range module.Resources {
+			if resource.Mode == "" || resource.Mode == "managed" {
+				resources = append(resources, resource)
+			}
+		}
+		for _, child := range module.ChildModules {
+			walk(child)
+		}
+	}
+	walk(p.PlannedValues.RootModule)
+	return resources
+}
+
+// PlanValidator is the interface for validators of plan JSON
+type PlanValidator interface {
+	ValidatePlan(plan *Plan) []ValidationIssue
+	Name() string
+}
+
+// ValidatePlan runs the plan-aware rules against the resource changes and
+// planned values of a plan
+func (e *ValidationEngine) ValidatePlan(plan *Plan) (*ValidationResult, error) {
+	e.logger.Info("Validating Terraform plan", "resourceChanges", len(plan.ResourceChanges))
+
+	result := &ValidationResult{
+		Issues: []ValidationIssue{},
+	}
+	for _, validator := range e.planValidators {
+		e.logger.Debug("Running plan validator", "name", validator.Name())
+		result.Issues = append(result.Issues, validator.ValidatePlan(plan)...)
+	}
+
+	for _, issue := range result.Issues {
+		switch issue.Severity {
+		case SeverityError:
+			result.ErrorCount++
+		case SeverityWarning:
+			result.WarnCount++
+		case SeverityInfo:
+			result.InfoCount++
+		}
+	}
+
+	return result, nil
+}
+
+// statefulResourceTypes are resource types that hold data which is lost when
+// the resource is destroyed
+var statefulResourceTypes = map[string]bool{
+	"aws_db_instance":                    true,
+	"aws_rds_cluster":                    true,
+	"aws_dynamodb_table":                 true,
+	"aws_s3_bucket":                      true,
+	"aws_ebs_volume":                     true,
+	"aws_efs_file_system":                true,
+	"aws_elasticache_cluster":            true,
+	"aws_elasticache_replication_group":  true,
+	"aws_elasticsearch_domain":           true,
+	"aws_opensearch_domain":              true,
+	"aws_redshift_cluster":               true,
+	"aws_docdb_cluster":                  true,
+	"aws_neptune_cluster":                true,
+	"aws_kms_key":                        true,
+	"aws_secretsmanager_secret":          true,
+	"aws_route53_zone":                   true,
+	"azurerm_storage_account":            true,
+	"azurerm_managed_disk":               true,
+	"azurerm_mssql_database":             true,
+	"azurerm_mssql_server":               true,
+	"azurerm_postgresql_flexible_server": true,
+	"azurerm_mysql_flexible_server":      true,
+	"azurerm_cosmosdb_account":           true,
+	"azurerm_key_vault":                  true,
+	"google_sql_database_instance":       true,
+	"google_storage_bucket":              true,
+	"google_compute_disk":                true,
+	"google_bigquery_dataset":            true,
+	"google_spanner_instance":            true,
+	"google_kms_crypto_key":              true,
+}
+
+// PlanChangeValidator validates the resource changes of a plan
+type PlanChangeValidator struct{}
+
+// Name returns the name of the validator
+func (v *PlanChangeValidator) Name() string {
+	return "PlanChangeValidator"
+}
+
+// ValidatePlan reports stateful resources the plan destroys or replaces
+func (v *PlanChangeValidator) ValidatePlan(plan *Plan) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, change := range plan.ResourceChanges {
+		if change.Mode == "data" || !statefulResourceTypes[change.Type] {
+			continue
+		}
+
+		var message string
+		switch change.Action() {
+		case ActionDelete:
+			message = fmt.Sprintf("Plan destroys stateful resource '%s'", change.Address)
+		case ActionReplace:
+			message = fmt.Sprintf("Plan replaces stateful resource '%s'", change.Address)
+			if paths := change.ReplacedBy(); len(paths) > 0 {
+				message += fmt.Sprintf(" because %s cannot be updated in place", strings.Join(paths, ", "))
+			}
+		default:
+			continue
+		}
+
+		issues = append(issues, ValidationIssue{
+			Message:      message,
+			RuleID:       "plan-stateful-destroy",
+			Severity:     SeverityError,
+			Category:     CategoryMaintenance,
+			BestPractice: "Do not destroy or replace resources that hold data without a backup",
+			Suggestion:   "Back up the data first, use a moved block for renames, or add lifecycle { prevent_destroy = true }",
+		})
+	}
+	return issues
+}
+
+// DescribeRules returns the rules checked by the validator
+func (v *PlanChangeValidator) DescribeRules() []RuleMetadata {
+	return []RuleMetadata{
+		{ID: "plan-stateful-destroy", Name: "PlanStatefulDestroy", Description: "Do not destroy or replace resources that hold data without a backup", Severity: SeverityError, Category: CategoryMaintenance, Tags: []string{"plan"}},
+	}
+}
+
+// planValueCheck is a check of a single resolved value of a planned resource
+type planValueCheck struct {
+	RuleID    string
+	Types     []string
+	Attribute string
+	// Bad is the value that violates the rule
+	Bad     string
+	Message string
+}
+
+// planValueChecks are the value checks of the security rule pack that can
+// be decided from resolved plan values
+var planValueChecks = []planValueCheck{
+	{RuleID: "ebs-encryption", Types: []string{"aws_ebs_volume"}, Attribute: "encrypted", Bad: "false", Message: "EBS volume '%s' is not encrypted"},
+	{RuleID: "rds-encryption", Types: []string{"aws_db_instance", "aws_rds_cluster"}, Attribute: "storage_encrypted", Bad: "false", Message: "Storage of '%s' is not encrypted"},
+	{RuleID: "database-public-access", Types: []string{"aws_db_instance", "aws_rds_cluster_instance"}, Attribute: "publicly_accessible", Bad: "true", Message: "'%s' is publicly accessible"},
+	{RuleID: "storage-account-public-access", Types: []string{"azurerm_storage_account"}, Attribute: "allow_nested_items_to_be_public", Bad: "true", Message: "Storage account '%s' allows public blob access"},
+	{RuleID: "storage-account-secure-transfer", Types: []string{"azurerm_storage_account"}, Attribute: "https_traffic_only_enabled", Bad: "false", Message: "Storage account '%s' allows unencrypted HTTP traffic"},
+	{RuleID: "cloudtrail-log-validation", Types: []string{"aws_cloudtrail"}, Attribute: "enable_log_file_validation", Bad: "false", Message: "Log file validation is not enabled on '%s'"},
+}
+
+// ValidatePlan checks the resolved values of the planned resources, such as
+// security group rules whose CIDR blocks come from variables
+func (v *SecurityValidator) ValidatePlan(plan *Plan) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, resource := range plan.Resources() {
+		values := mapValues{values: resource.Values}
+
+		for _, check := range planValueChecks {
+			if !containsString(check.Types, resource.Type) {
+				continue
+			}
+			if value, ok := values.String(check.Attribute); ok && value == check.Bad {
+				issues = append(issues, securityRuleByID(check.RuleID).issue(securityFinding{
+					Message: fmt.Sprintf(check.Message, resource.Address),
+				}))
+			}
+		}
+
+		for _, rule := range resourceIngressRules(resource.Type, resource.Address, values) {
+			if names := exposedAdminPorts(rule); len(names) > 0 {
+				issues = append(issues, securityRuleByID("open-ssh-rdp").issue(securityFinding{
+					Message: fmt.Sprintf("'%s' allows %s from %s", rule.Owner, strings.Join(names, " and "), rule.Source),
+				}))
+				continue
+			}
+			issues = append(issues, securityRuleByID("open-ingress").issue(securityFinding{
+				Message: fmt.Sprintf("'%s' allows access from %s (any IP)", rule.Owner, rule.Source),
+			}))
+		}
+	}
+	return issues
+}
+
+// planTagAttributes are the planned attributes holding the final tags of a
+// resource, including provider default tags, by provider
+var planTagAttributes = map[string][]string{
+	"aws":     {"tags_all", "tags"},
+	"azurerm": {"tags"},
+	"google":  {"terraform_labels", "labels"},
+}
+
+// ValidatePlan checks the final tags of the planned resources against the
+// tagging policy. Resources whose tags are only known after apply are skipped.
+func (v *TagValidator) ValidatePlan(plan *Plan) []ValidationIssue {
+	return v.resourceTagIssues(plan.Resources())
+}
+
+// resourceTagIssues checks the resolved tags of resource instances
+func (v *TagValidator) resourceTagIssues(resources []PlanResource) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, resource := range resources {
+		convention := tagConventionFor(resource.Type, v.schemas)
+		if convention == nil {
+			continue
+		}
+
+		tags, ok := plannedTags(resource, convention)
+		if !ok {
+			continue
+		}
+
+		target := tagTarget{Address: resource.Address, Name: resource.Name}
+		if convention.labels {
+			issues = append(issues, labelFormatIssues(target, tags)...)
+		}
+		if v.policy != nil && v.policy.appliesTo(resource.Type) {
+			issues = append(issues, v.policyIssues(target, convention, tags)...)
+		}
+	}
+	return issues
+}
+
+// plannedTags returns the final tags of a planned resource
+func plannedTags(resource PlanResource, convention *tagConvention) (tagSet, bool) {
+	for _, attribute := range planTagAttributes[convention.provider] {
+		raw, ok := resource.Values[attribute]
+		if !ok {
+			continue
+		}
+		tags := tagSet{Tags: make(map[string]tagValue), Complete: true}
+		values, _ := raw.(map[string]interface{})
+		for key, raw := range values {
+			value, known := jsonPrimitiveString(raw)
+			if !known {
+				tags.Tags[key] = tagValue{}
+				continue
+			}
+			tags.Tags[key] = tagValue{Value: &value}
+		}
+		return tags, true
+	}
+	return tagSet{}, false
+}
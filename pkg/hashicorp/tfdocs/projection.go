@@ -0,0 +1,159 @@
+// pkg/hashicorp/tfdocs/projection.go
+package tfdocs
+
+import (
+	"encoding/json"
+	"sort"
+
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// ConfigurationProjection is a JSON representation of a parsed Terraform
+// configuration, used as input for policy evaluation
+type ConfigurationProjection struct {
+	Files     []string               `json:"files"`
+	Terraform []BlockProjection      `json:"terraform"`
+	Providers []BlockProjection      `json:"providers"`
+	Variables []BlockProjection      `json:"variables"`
+	Locals    map[string]interface{} `json:"locals"`
+	Resources []BlockProjection      `json:"resources"`
+	Data      []BlockProjection      `json:"data"`
+	Modules   []BlockProjection      `json:"modules"`
+	Outputs   []BlockProjection      `json:"outputs"`
+}
+
+// BlockProjection is a JSON representation of a block. Attributes hold the
+// constant value where it can be determined statically, otherwise the
+// expression source text.
+type BlockProjection struct {
+	Type       string                       `json:"type,omitempty"`
+	Name       string                       `json:"name,omitempty"`
+	Labels     []string                     `json:"labels,omitempty"`
+	Address    string                       `json:"address,omitempty"`
+	File       string                       `json:"file"`
+	Line       int                          `json:"line"`
+	Attributes map[string]interface{}       `json:"attributes"`
+	References map[string][]string          `json:"references,omitempty"`
+	Blocks     map[string][]BlockProjection `json:"blocks,omitempty"`
+}
+
+// Projection returns the JSON projection of the configuration
+func (c *TerraformConfiguration) Projection() *ConfigurationProjection {
+	projection := &ConfigurationProjection{
+		Files:     []string{},
+		Terraform: []BlockProjection{},
+		Providers: []BlockProjection{},
+		Variables: []BlockProjection{},
+		Locals:    map[string]interface{}{},
+		Resources: []BlockProjection{},
+		Data:      []BlockProjection{},
+		Modules:   []BlockProjection{},
+		Outputs:   []BlockProjection{},
+	}
+
+	for name := range c.Files {
+		projection.Files = append(projection.Files, name)
+	}
+	sort.Strings(projection.Files)
+
+	for _, block := range c.Blocks("") {
+		switch block.Type {
+		case "terraform":
+			projection.Terraform = append(projection.Terraform, projectBlock(block))
+		case "provider":
+			projection.Providers = append(projection.Providers, projectBlock(block))
+		case "variable":
+			projection.Variables = append(projection.Variables, projectBlock(block))
+		case "locals":
+			for name, attr := range block.Attributes {
+				projection.Locals[name] = projectAttribute(attr)
+			}
+		case "resource":
+			projection.Resources = append(projection.Resources, projectBlock(block))
+		case "data":
+			projection.Data = append(projection.Data, projectBlock(block))
+		case "module":
+			projection.Modules = append(projection.Modules, projectBlock(block))
+		case "output":
+			projection.Outputs = append(projection.Outputs, projectBlock(block))
+		}
+	}
+
+	return projection
+}
+
+// ProjectionValue returns the projection as generic JSON values
+func (c *TerraformConfiguration) ProjectionValue() (interface{}, error) {
+	data, err := json.Marshal(c.Projection())
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// projectBlock converts a block into its JSON projection
+func projectBlock(block *Block) BlockProjection {
+	projection := BlockProjection{
+		File:       block.File,
+		Line:       block.Line,
+		Attributes: make(map[string]interface{}, len(block.Attributes)),
+	}
+
+	switch block.Type {
+	case "resource", "data":
+		projection.Type = block.Label(0)
+		projection.Name = block.Label(1)
+		projection.Address = block.Address()
+	case "module", "variable", "output":
+		projection.Name = block.Label(0)
+		projection.Address = block.Address()
+	case "provider":
+		projection.Name = block.Label(0)
+	default:
+		projection.Type = block.Type
+		projection.Labels = block.Labels
+	}
+
+	for name, attr := range block.Attributes {
+		projection.Attributes[name] = projectAttribute(attr)
+		if refs := attr.References(); len(refs) > 0 {
+			if projection.References == nil {
+				projection.References = make(map[string][]string)
+			}
+			projection.References[name] = refs
+		}
+	}
+
+	for _, nested := range block.Blocks {
+		if projection.Blocks == nil {
+			projection.Blocks = make(map[string][]BlockProjection)
+		}
+		projection.Blocks[nested.Type] = append(projection.Blocks[nested.Type], projectBlock(nested))
+	}
+
+	return projection
+}
+
+// projectAttribute returns the constant value of an attribute, or its source text
+func projectAttribute(attr *Attribute) interface{} {
+	value, ok := attr.Value()
+	if !ok {
+		return attr.Source
+	}
+
+	data, err := ctyjson.Marshal(value, value.Type())
+	if err != nil {
+		return attr.Source
+	}
+
+	var result interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return attr.Source
+	}
+	return result
+}
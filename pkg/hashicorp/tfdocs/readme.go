@@ -0,0 +1,403 @@
+// pkg/hashicorp/tfdocs/readme.go
+package tfdocs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Markers delimiting the generated section of a README, compatible with
+// terraform-docs
+const (
+	DocsBeginMarker = "<!-- BEGIN_TF_DOCS -->"
+	DocsEndMarker   = "<!-- END_TF_DOCS -->"
+)
+
+// ModuleDocs describes the interface of a module for its README
+type ModuleDocs struct {
+	Requirements []DocsRequirement `json:"requirements"`
+	Providers    []DocsProvider    `json:"providers"`
+	Modules      []DocsModule      `json:"modules"`
+	Resources    []DocsResource    `json:"resources"`
+	Inputs       []DocsInput       `json:"inputs"`
+	Outputs      []DocsOutput      `json:"outputs"`
+}
+
+// DocsRequirement is a version requirement of Terraform or a provider
+type DocsRequirement struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// DocsProvider is a provider used by the module
+type DocsProvider struct {
+	Name    string `json:"name"`
+	Source  string `json:"source"`
+	Version string `json:"version,omitempty"`
+}
+
+// DocsModule is a child module called by the module
+type DocsModule struct {
+	Name    string `json:"name"`
+	Source  string `json:"source"`
+	Version string `json:"version,omitempty"`
+}
+
+// DocsResource is a resource or data source of the module
+type DocsResource struct {
+	Address string `json:"address"`
+	Mode    string `json:"mode"`
+}
+
+// DocsInput is an input variable of the module
+type DocsInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Type        string `json:"type"`
+	// Default is the source of the default value, empty for required inputs
+	Default     string   `json:"default,omitempty"`
+	Required    bool     `json:"required"`
+	Sensitive   bool     `json:"sensitive,omitempty"`
+	Validations []string `json:"validations,omitempty"`
+}
+
+// DocsOutput is an output value of the module
+type DocsOutput struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Sensitive   bool   `json:"sensitive,omitempty"`
+}
+
+// ModuleDocs collects the documentation of the root module of the
+// configuration. Files in subdirectories belong to other modules and are
+// ignored.
+func (c *TerraformConfiguration) ModuleDocs() *ModuleDocs {
+	root := c.ModuleTree().Root.Config
+	docs := &ModuleDocs{
+		Requirements: []DocsRequirement{},
+		Providers:    []DocsProvider{},
+		Modules:      []DocsModule{},
+		Resources:    []DocsResource{},
+		Inputs:       []DocsInput{},
+		Outputs:      []DocsOutput{},
+	}
+
+	// Requirements and providers
+	for _, block := range root.Blocks("terraform") {
+		if attr, ok := block.Attributes["required_version"]; ok {
+			if version, known := attr.StringValue(); known {
+				docs.Requirements = append(docs.Requirements, DocsRequirement{Name: "terraform", Version: version})
+			}
+		}
+	}
+	versions := make(map[string]ProviderRequirement)
+	for _, requirement := range root.ProviderRequirements() {
+		versions[requirement.Name] = requirement
+		if requirement.Version != "" {
+			docs.Requirements = append(docs.Requirements, DocsRequirement{Name: requirement.Name, Version: requirement.Version})
+		}
+	}
+	providers := usedProviders(root)
+	for name := range versions {
+		if !containsString(providers, name) {
+			providers = append(providers, name)
+		}
+	}
+	sort.Strings(providers)
+	for _, name := range providers {
+		requirement, ok := versions[name]
+		if !ok {
+			requirement = ProviderRequirement{Name: name}
+		}
+		docs.Providers = append(docs.Providers, DocsProvider{Name: name, Source: requirement.Address(), Version: requirement.Version})
+	}
+
+	// Child modules, resources and data sources
+	for _, block := range root.Blocks("module") {
+		module := DocsModule{Name: block.Label(0)}
+		if attr, ok := block.Attributes["source"]; ok {
+			module.Source, _ = attr.StringValue()
+		}
+		if attr, ok := block.Attributes["version"]; ok {
+			module.Version, _ = attr.StringValue()
+		}
+		docs.Modules = append(docs.Modules, module)
+	}
+	for _, block := range root.Blocks("") {
+		if block.Type == "resource" || block.Type == "data" {
+			mode := "resource"
+			if block.Type == "data" {
+				mode = "data source"
+			}
+			docs.Resources = append(docs.Resources, DocsResource{Address: block.Address(), Mode: mode})
+		}
+	}
+
+	// Inputs and outputs
+	for _, block := range root.Blocks("variable") {
+		docs.Inputs = append(docs.Inputs, variableDocs(block))
+	}
+	for _, block := range root.Blocks("output") {
+		output := DocsOutput{Name: block.Label(0), Description: blockString(block, "description")}
+		output.Sensitive = blockString(block, "sensitive") == "true"
+		docs.Outputs = append(docs.Outputs, output)
+	}
+
+	sort.Slice(docs.Modules, func(i, j int) bool { return docs.Modules[i].Name < docs.Modules[j].Name })
+	sort.Slice(docs.Resources, func(i, j int) bool { return docs.Resources[i].Address < docs.Resources[j].Address })
+	sort.Slice(docs.Inputs, func(i, j int) bool { return docs.Inputs[i].Name < docs.Inputs[j].Name })
+	sort.Slice(docs.Outputs, func(i, j int) bool { return docs.Outputs[i].Name < docs.Outputs[j].Name })
+	return docs
+}
+
+// variableDocs documents a variable block
+func variableDocs(block *Block) DocsInput {
+	input := DocsInput{
+		Name:        block.Label(0),
+		Description: blockString(block, "description"),
+		Type:        "any",
+		Required:    true,
+		Sensitive:   blockString(block, "sensitive") == "true",
+	}
+	if attr, ok := block.Attributes["type"]; ok {
+		input.Type = compactSource(attr.Source)
+	}
+	if attr, ok := block.Attributes["default"]; ok {
+		input.Default = compactSource(attr.Source)
+		input.Required = false
+	}
+	for _, validation := range block.NestedBlocks("validation") {
+		if message := blockString(validation, "error_message"); message != "" {
+			input.Validations = append(input.Validations, message)
+		}
+	}
+	return input
+}
+
+// blockString returns the constant value of an attribute of a block, or an
+// empty string
+func blockString(block *Block, name string) string {
+	attr, ok := block.Attributes[name]
+	if !ok {
+		return ""
+	}
+	value, _ := attr.StringValue()
+	return value
+}
+
+// compactSource collapses an expression spanning several lines into one
+func compactSource(source string) string {
+	lines := strings.Split(strings.TrimSpace(source), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	compact := strings.Join(lines, " ")
+	compact = strings.ReplaceAll(compact, "{ }", "{}")
+	compact = strings.ReplaceAll(compact, "[ ]", "[]")
+	return compact
+}
+
+// markdownCell escapes a value for a Markdown table cell
+func markdownCell(value string) string {
+	value = strings.ReplaceAll(value, "|", "\\|")
+	return strings.ReplaceAll(value, "\n", "<br>")
+}
+
+// markdownCode renders a value as inline code in a Markdown table cell
+func markdownCode(value string) string {
+	if value == "" {
+		return "n/a"
+	}
+	return "`" + markdownCell(value) + "`"
+}
+
+// Markdown renders the Requirements, Providers, Modules, Resources, Inputs
+// and Outputs tables. Defaults of sensitive inputs are not shown.
+func (d *ModuleDocs) Markdown() string {
+	var b strings.Builder
+
+	b.WriteString("## Requirements\n\n")
+	if len(d.Requirements) == 0 {
+		b.WriteString("No requirements.\n")
+	} else {
+		b.WriteString("| Name | Version |\n|------|---------|\n")
+		for _, requirement := range d.Requirements {
+			fmt.Fprintf(&b, "| %s | %s |\n", requirement.Name, markdownCode(requirement.Version))
+		}
+	}
+
+	b.WriteString("\n## Providers\n\n")
+	if len(d.Providers) == 0 {
+		b.WriteString("No providers.\n")
+	} else {
+		b.WriteString("| Name | Source | Version |\n|------|--------|---------|\n")
+		for _, provider := range d.Providers {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", provider.Name, provider.Source, markdownCode(provider.Version))
+		}
+	}
+
+	b.WriteString("\n## Modules\n\n")
+	if len(d.Modules) == 0 {
+		b.WriteString("No modules.\n")
+	} else {
+		b.WriteString("| Name | Source | Version |\n|------|--------|---------|\n")
+		for _, module := range d.Modules {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", module.Name, markdownCell(module.Source), markdownCode(module.Version))
+		}
+	}
+
+	b.WriteString("\n## Resources\n\n")
+	if len(d.Resources) == 0 {
+		b.WriteString("No resources.\n")
+	} else {
+		b.WriteString("| Name | Type |\n|------|------|\n")
+		for _, resource := range d.Resources {
+			fmt.Fprintf(&b, "| %s | %s |\n", resource.Address, resource.Mode)
+		}
+	}
+
+	b.WriteString("\n## Inputs\n\n")
+	if len(d.Inputs) == 0 {
+		b.WriteString("No inputs.\n")
+	} else {
+		b.WriteString("| Name | Description | Type | Default | Required |\n|------|-------------|------|---------|:--------:|\n")
+		for _, input := range d.Inputs {
+			description := input.Description
+			for _, validation := range input.Validations {
+				description += "\n_Validation:_ " + validation
+			}
+			defaultValue := markdownCode(input.Default)
+			if input.Sensitive && !input.Required {
+				defaultValue = "sensitive"
+			}
+			required := "no"
+			if input.Required {
+				required = "yes"
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n", input.Name, markdownCell(description), markdownCode(input.Type), defaultValue, required)
+		}
+	}
+
+	b.WriteString("\n## Outputs\n\n")
+	if len(d.Outputs) == 0 {
+		b.WriteString("No outputs.\n")
+	} else {
+		b.WriteString("| Name | Description | Sensitive |\n|------|-------------|:---------:|\n")
+		for _, output := range d.Outputs {
+			sensitive := "no"
+			if output.Sensitive {
+				sensitive = "yes"
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", output.Name, markdownCell(output.Description), sensitive)
+		}
+	}
+
+	return b.String()
+}
+
+// UpdateReadme replaces the generated section of a README, between the
+// BEGIN_TF_DOCS and END_TF_DOCS markers, with the documentation. Content
+// outside the markers is kept. The section is appended to READMEs without
+// markers.
+func UpdateReadme(readme string, docs *ModuleDocs) string {
+	section := DocsBeginMarker + "\n" + docs.Markdown() + DocsEndMarker + "\n"
+
+	begin := strings.Index(readme, DocsBeginMarker)
+	if begin >= 0 {
+		if end := strings.Index(readme[begin:], DocsEndMarker); end >= 0 {
+			end += begin + len(DocsEndMarker)
+			if end < len(readme) && readme[end] == '\n' {
+				end++
+			}
+			return readme[:begin] + section + readme[end:]
+		}
+	}
+
+	if readme == "" {
+		return section
+	}
+	if !strings.HasSuffix(readme, "\n") {
+		readme += "\n"
+	}
+	return readme + "\n" + section
+}
+
+// readmeOptions configures README generation
+type readmeOptions struct {
+	title      string
+	moduleName string
+	source     string
+}
+
+// ReadmeOption is a function that configures README generation
+type ReadmeOption func(*readmeOptions)
+
+// WithReadmeTitle sets the title of a generated README
+func WithReadmeTitle(title string) ReadmeOption {
+	return func(o *readmeOptions) {
+		o.title = title
+	}
+}
+
+// WithModuleSource sets the module name and source used in the usage example
+// of a generated README
+func WithModuleSource(name, source string) ReadmeOption {
+	return func(o *readmeOptions) {
+		o.moduleName = name
+		o.source = source
+	}
+}
+
+// GenerateReadme generates a README for the root module of a configuration
+// with a usage example setting the required inputs, followed by the
+// generated documentation section
+func GenerateReadme(config *TerraformConfiguration, options ...ReadmeOption) string {
+	opts := readmeOptions{title: "Terraform Module", moduleName: "example", source: "./path/to/module"}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	docs := config.ModuleDocs()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n## Usage\n\n", opts.title)
+	b.WriteString("```hcl\n")
+	fmt.Fprintf(&b, "module %q {\n  source = %q\n", opts.moduleName, opts.source)
+	var required []DocsInput
+	for _, input := range docs.Inputs {
+		if input.Required {
+			required = append(required, input)
+		}
+	}
+	if len(required) > 0 {
+		b.WriteString("\n")
+		width := 0
+		for _, input := range required {
+			if len(input.Name) > width {
+				width = len(input.Name)
+			}
+		}
+		for _, input := range required {
+			fmt.Fprintf(&b, "  %-*s = %s\n", width, input.Name, exampleValue(input.Type))
+		}
+	}
+	b.WriteString("}\n```\n")
+
+	return UpdateReadme(b.String(), docs)
+}
+
+// exampleValue returns a placeholder value for a variable type
+func exampleValue(typeExpr string) string {
+	switch {
+	case typeExpr == "string":
+		return `"..."`
+	case typeExpr == "number":
+		return "0"
+	case typeExpr == "bool":
+		return "false"
+	case strings.HasPrefix(typeExpr, "list") || strings.HasPrefix(typeExpr, "set") || strings.HasPrefix(typeExpr, "tuple"):
+		return "[]"
+	}
+	return "{}"
+}
@@ -0,0 +1,254 @@
+// pkg/hashicorp/tfdocs/rego.go
+package tfdocs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/rego"
+)
+
+// regoRuleKinds maps the Rego rule names evaluated in each policy package to
+// the severity of the issues they produce
+var regoRuleKinds = []struct {
+	name     string
+	severity ValidationSeverity
+}{
+	{name: "deny", severity: SeverityError},
+	{name: "warn", severity: SeverityWarning},
+}
+
+// regoQuery is a prepared query for one rule of a policy package
+type regoQuery struct {
+	pkg      string
+	rule     string
+	severity ValidationSeverity
+	query    rego.PreparedEvalQuery
+}
+
+// RegoValidator evaluates Rego policies against the JSON projection of a
+// configuration. Each policy package may define deny and warn rules that
+// produce strings or objects with msg, id, category, file, line and
+// suggestion fields.
+type RegoValidator struct {
+	queries []regoQuery
+}
+
+// Name returns the name of the validator
+func (v *RegoValidator) Name() string {
+	return "RegoValidator"
+}
+
+// LoadRegoPolicies loads all *.rego files from a directory, including
+// subdirectories, and prepares their deny and warn rules for evaluation
+func LoadRegoPolicies(ctx context.Context, dir string) (*RegoValidator, error) {
+	modules := make(map[string]string)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Ext(path) != ".rego" || strings.HasSuffix(path, "_test.rego") {
+			return nil
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read policy %s: %w", path, err)
+		}
+		modules[path] = string(data)
+		return nil
+	})
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &RegoValidator{}, nil
+		}
+		return nil, fmt.Errorf("failed to read policy directory: %w", err)
+	}
+
+	return NewRegoValidator(ctx, modules)
+}
+
+// NewRegoValidator compiles Rego modules, keyed by file name, and prepares
+// their deny and warn rules for evaluation
+func NewRegoValidator(ctx context.Context, modules map[string]string) (*RegoValidator, error) {
+	validator := &RegoValidator{}
+	if len(modules) == 0 {
+		return validator, nil
+	}
+
+	compiler, err := ast.CompileModules(modules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile policies: %w", err)
+	}
+
+	// Collect the packages defining deny or warn rules
+	packages := make(map[string]map[string]bool)
+	for _, module := range compiler.Modules {
+		pkg := module.Package.Path.String()
+		for _, rule := range module.Rules {
+			name := rule.Head.Name.String()
+			if name == "" && len(rule.Head.Reference) > 0 {
+				name = rule.Head.Reference[0].String()
+			}
+			if packages[pkg] == nil {
+				packages[pkg] = make(map[string]bool)
+			}
+			packages[pkg][name] = true
+		}
+	}
+
+	pkgNames := make([]string, 0, len(packages))
+	for pkg := range packages {
+		pkgNames = append(pkgNames, pkg)
+	}
+	sort.Strings(pkgNames)
+
+	for _, pkg := range pkgNames {
+		for _, kind := range regoRuleKinds {
+			if !packages[pkg][kind.name] {
+				continue
+			}
+
+			query, err := rego.New(
+				rego.Query(pkg+"."+kind.name),
+				rego.Compiler(compiler),
+			).PrepareForEval(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to prepare %s.%s: %w", pkg, kind.name, err)
+			}
+
+			validator.queries = append(validator.queries, regoQuery{
+				pkg:      strings.TrimPrefix(pkg, "data."),
+				rule:     kind.name,
+				severity: kind.severity,
+				query:    query,
+			})
+		}
+	}
+
+	return validator, nil
+}
+
+// Validate evaluates the policies against a Terraform configuration
+func (v *RegoValidator) Validate(config *TerraformConfiguration) []ValidationIssue {
+	var issues []ValidationIssue
+
+	if len(v.queries) == 0 {
+		return issues
+	}
+
+	input, err := config.ProjectionValue()
+	if err != nil {
+		return append(issues, regoErrorIssue("", err))
+	}
+
+	ctx := context.Background()
+	for _, q := range v.queries {
+		results, err := q.query.Eval(ctx, rego.EvalInput(input))
+		if err != nil {
+			issues = append(issues, regoErrorIssue(q.pkg+"."+q.rule, err))
+			continue
+		}
+
+		for _, result := range results {
+			for _, expr := range result.Expressions {
+				for _, value := range regoResultValues(expr.Value) {
+					issues = append(issues, q.issue(value))
+				}
+			}
+		}
+	}
+
+	return issues
+}
+
+// issue converts a deny or warn result into a validation issue
+func (q *regoQuery) issue(value interface{}) ValidationIssue {
+	issue := ValidationIssue{
+		RuleID:       q.pkg + "." + q.rule,
+		Severity:     q.severity,
+		Category:     CategorySecurity,
+		BestPractice: fmt.Sprintf("Satisfy the %s policy", q.pkg),
+	}
+
+	switch v := value.(type) {
+	case string:
+		issue.Message = v
+	case map[string]interface{}:
+		issue.Message = regoString(v, "msg", "message")
+		if id := regoString(v, "id", "rule_id"); id != "" {
+			issue.RuleID = id
+		}
+		if category := regoString(v, "category"); category != "" {
+			issue.Category = ValidationCategory(category)
+		}
+		issue.File = regoString(v, "file")
+		issue.Suggestion = regoString(v, "suggestion")
+		if line, ok := v["line"].(json.Number); ok {
+			if n, err := line.Int64(); err == nil {
+				issue.Line = int(n)
+			}
+		}
+	default:
+		data, _ := json.Marshal(v)
+		issue.Message = string(data)
+	}
+
+	if issue.Message == "" {
+		issue.Message = fmt.Sprintf("Policy %s failed", issue.RuleID)
+	}
+
+	return issue
+}
+
+// regoResultValues flattens the value of a set or object rule into its elements
+func regoResultValues(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		return v
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		values := make([]interface{}, 0, len(v))
+		for _, key := range keys {
+			values = append(values, v[key])
+		}
+		return values
+	case nil:
+		return nil
+	}
+	return []interface{}{value}
+}
+
+// regoString returns the first string field found under the given keys
+func regoString(obj map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if s, ok := obj[key].(string); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+// regoErrorIssue reports a policy that could not be evaluated
+func regoErrorIssue(ruleID string, err error) ValidationIssue {
+	return ValidationIssue{
+		Message:      fmt.Sprintf("Failed to evaluate policy: %v", err),
+		RuleID:       ruleID,
+		Severity:     SeverityError,
+		Category:     CategorySecurity,
+		BestPractice: "Keep policies free of runtime errors",
+		Suggestion:   "Fix the policy so that it evaluates against the configuration",
+	}
+}
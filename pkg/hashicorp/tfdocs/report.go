@@ -0,0 +1,589 @@
+// pkg/hashicorp/tfdocs/report.go
+package tfdocs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// OutputFormat is a format validation results can be rendered in
+type OutputFormat string
+
+const (
+	OutputFormatText       OutputFormat = "text"
+	OutputFormatJSON       OutputFormat = "json"
+	OutputFormatSARIF      OutputFormat = "sarif"
+	OutputFormatJUnit      OutputFormat = "junit"
+	OutputFormatCheckstyle OutputFormat = "checkstyle"
+)
+
+// OutputFormats lists the supported output formats
+var OutputFormats = []OutputFormat{
+	OutputFormatText,
+	OutputFormatJSON,
+	OutputFormatSARIF,
+	OutputFormatJUnit,
+	OutputFormatCheckstyle,
+}
+
+const (
+	reportToolName = "terraform-mcp-server"
+	reportToolURI  = "https://github.com/MrFixit96/terraform-best-practices-mcp"
+)
+
+// ParseOutputFormat parses an output format name. An empty name selects text.
+func ParseOutputFormat(name string) (OutputFormat, error) {
+	if name == "" {
+		return OutputFormatText, nil
+	}
+	for _, format := range OutputFormats {
+		if strings.EqualFold(name, string(format)) {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported output format: %s", name)
+}
+
+// FormatReport renders a validation result in the given format. The rules
+// provide metadata for SARIF output and the configuration is used to compute
+// fingerprints that stay stable when lines move.
+func FormatReport(format OutputFormat, result *ValidationResult, rules []RuleMetadata, config *TerraformConfiguration) (string, error) {
+	switch format {
+	case OutputFormatText, "":
+		return FormatValidationResult(result), nil
+	case OutputFormatJSON:
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal result: %w", err)
+		}
+		return string(data), nil
+	case OutputFormatSARIF:
+		return formatSARIF(result, rules, config)
+	case OutputFormatJUnit:
+		return formatJUnit(result, config)
+	case OutputFormatCheckstyle:
+		return formatCheckstyle(result, config)
+	}
+	return "", fmt.Errorf("unsupported output format: %s", format)
+}
+
+// IssueFingerprint returns a fingerprint identifying an issue across runs.
+// It is based on the rule, the file and the path of the symbol the issue is
+// reported on, i.e. the address of its block followed by the nested blocks
+// and the attribute, so it stays stable when lines move or a message
+// mentions changing counts. Issues outside of a symbol use their normalized
+// message instead.
+func IssueFingerprint(issue ValidationIssue, config *TerraformConfiguration) string {
+	return fingerprint(issueKey(issue, config), 0)
+}
+
+// IssueFingerprints returns the fingerprints of issues. Issues of the same
+// rule reported on the same symbol, such as an SSH and an RDP ingress rule
+// of one block, are told apart by their ordinal.
+func IssueFingerprints(issues []ValidationIssue, config *TerraformConfiguration) []string {
+	fingerprints := make([]string, len(issues))
+	seen := make(map[string]int)
+	for i, issue := range issues {
+		key := issueKey(issue, config)
+		fingerprints[i] = fingerprint(key, seen[key])
+		seen[key]++
+	}
+	return fingerprints
+}
+
+// issueKey returns the rule, file and symbol path of an issue, separated by
+// NUL bytes
+func issueKey(issue ValidationIssue, config *TerraformConfiguration) string {
+	symbol := issueSymbol(config, issue.File, issue.Line)
+	if symbol == "" {
+		symbol = normalizeMessage(issue.Message)
+	}
+	return issueRuleID(issue) + "\x00" + issue.File + "\x00" + symbol
+}
+
+// fingerprint hashes an issue key and its ordinal among issues of the same key
+func fingerprint(key string, ordinal int) string {
+	hash := sha256.New()
+	hash.Write([]byte(key))
+	if ordinal > 0 {
+		fmt.Fprintf(hash, "\x00%d", ordinal)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+var digitsPattern = regexp.MustCompile(`\d+`)
+
+// normalizeMessage returns a message with numbers and runs of whitespace
+// replaced, so counts and sizes in it do not change fingerprints
+func normalizeMessage(message string) string {
+	return strings.Join(strings.Fields(digitsPattern.ReplaceAllString(message, "#")), " ")
+}
+
+// issueSymbol returns the path of the symbol at a 1-based line of a file,
+// such as aws_security_group.web.ingress[1].cidr_blocks or local.name, or an
+// empty string
+func issueSymbol(config *TerraformConfiguration, file string, line int) string {
+	if config == nil || line <= 0 {
+		return ""
+	}
+	parsed := config.ParsedFile(file)
+	if parsed == nil {
+		return ""
+	}
+	for _, block := range parsed.Blocks {
+		if !containsLine(block.Range, line) {
+			continue
+		}
+		if block.Type == "locals" {
+			for _, attr := range block.Attributes {
+				if containsLine(attr.Range, line) {
+					return "local." + attr.Name
+				}
+			}
+			return ""
+		}
+		return block.Address() + nestedSymbol(block, line)
+	}
+	return ""
+}
+
+// nestedSymbol returns the path of the nested blocks and the attribute of a
+// block at a line, numbering nested blocks of the same type
+func nestedSymbol(block *Block, line int) string {
+	for _, attr := range block.Attributes {
+		if containsLine(attr.Range, line) {
+			return "." + attr.Name
+		}
+	}
+	counts := make(map[string]int)
+	for _, nested := range block.Blocks {
+		name := strings.Join(append([]string{nested.Type}, nested.Labels...), ".")
+		if containsLine(nested.Range, line) {
+			return fmt.Sprintf(".%s[%d]", name, counts[name]) + nestedSymbol(nested, line)
+		}
+		counts[name]++
+	}
+	return ""
+}
+
+// containsLine reports whether a range contains a 1-based line
+func containsLine(rng hcl.Range, line int) bool {
+	return line >= rng.Start.Line && line <= rng.End.Line
+}
+
+// sourceLine returns a 1-based line of a file, or an empty string
+func sourceLine(config *TerraformConfiguration, file string, line int) string {
+	if config == nil || line <= 0 {
+		return ""
+	}
+	lines := strings.Split(config.Files[file], "\n")
+	if line > len(lines) {
+		return ""
+	}
+	return lines[line-1]
+}
+
+// issueLocation returns the file and line an issue is reported at. Issues
+// that are not tied to a file, such as a missing README.md, are reported
+// against the first line of main.tf or the first file of the configuration.
+func issueLocation(issue ValidationIssue, config *TerraformConfiguration) (string, int) {
+	if issue.File != "" {
+		line := issue.Line
+		if line <= 0 {
+			line = 1
+		}
+		return issue.File, line
+	}
+	if config == nil || len(config.Files) == 0 {
+		return "", 0
+	}
+	if _, ok := config.Files["main.tf"]; ok {
+		return "main.tf", 1
+	}
+	names := make([]string, 0, len(config.Files))
+	for name := range config.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names[0], 1
+}
+
+// SARIF 2.1.0 types, limited to the properties the report uses
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string                 `json:"id"`
+	Name                 string                 `json:"name,omitempty"`
+	ShortDescription     sarifMessage           `json:"shortDescription"`
+	HelpURI              string                 `json:"helpUri,omitempty"`
+	DefaultConfiguration sarifConfiguration     `json:"defaultConfiguration"`
+	Properties           map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations,omitempty"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+	Fixes               []sarifFix        `json:"fixes,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+type sarifFix struct {
+	Description     sarifMessage          `json:"description"`
+	ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Replacements     []sarifReplacement    `json:"replacements"`
+}
+
+type sarifReplacement struct {
+	DeletedRegion   sarifRegion   `json:"deletedRegion"`
+	InsertedContent *sarifMessage `json:"insertedContent,omitempty"`
+}
+
+// sarifLevel maps an issue severity to a SARIF level
+func sarifLevel(severity ValidationSeverity) string {
+	switch severity {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	}
+	return "note"
+}
+
+// formatSARIF renders a validation result as a SARIF 2.1.0 log
+func formatSARIF(result *ValidationResult, rules []RuleMetadata, config *TerraformConfiguration) (string, error) {
+	// Index the known rules and add rules that are only known from issues
+	ruleIndex := make(map[string]int)
+	sarifRules := []sarifRule{}
+	addRule := func(rule RuleMetadata) {
+		if _, ok := ruleIndex[rule.ID]; ok {
+			return
+		}
+		properties := map[string]interface{}{"category": string(rule.Category)}
+		tags := append([]string{}, rule.Tags...)
+		for _, ref := range rule.References {
+			if id := strings.TrimPrefix(ref, "CWE-"); id != ref {
+				tags = append(tags, "external/cwe/cwe-"+id)
+			}
+		}
+		if len(tags) > 0 {
+			properties["tags"] = tags
+		}
+		if len(rule.References) > 0 {
+			properties["references"] = rule.References
+		}
+		ruleIndex[rule.ID] = len(sarifRules)
+		sarifRules = append(sarifRules, sarifRule{
+			ID:                   rule.ID,
+			Name:                 rule.Name,
+			ShortDescription:     sarifMessage{Text: rule.Description},
+			HelpURI:              rule.HelpURI,
+			DefaultConfiguration: sarifConfiguration{Level: sarifLevel(rule.Severity)},
+			Properties:           properties,
+		})
+	}
+	for _, rule := range rules {
+		addRule(rule)
+	}
+	for _, issue := range result.Issues {
+		addRule(ruleFromIssue(issue))
+	}
+
+	results := []sarifResult{}
+	fingerprints := IssueFingerprints(result.Issues, config)
+	for i, issue := range result.Issues {
+		ruleID := issueRuleID(issue)
+		message := issue.Message
+		if issue.Suggestion != "" {
+			message += ". " + issue.Suggestion
+		}
+
+		sarif := sarifResult{
+			RuleID:    ruleID,
+			RuleIndex: ruleIndex[ruleID],
+			Level:     sarifLevel(issue.Severity),
+			Message:   sarifMessage{Text: message},
+			PartialFingerprints: map[string]string{
+				"issueHash/v1": fingerprints[i],
+			},
+		}
+
+		if file, line := issueLocation(issue, config); file != "" {
+			sarif.Locations = []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: file},
+					Region:           &sarifRegion{StartLine: line},
+				},
+			}}
+		}
+
+		if issue.Fix != nil {
+			sarif.Fixes = []sarifFix{sarifFixFromFix(issue.Fix)}
+		}
+
+		results = append(results, sarif)
+	}
+
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           reportToolName,
+				InformationURI: reportToolURI,
+				Rules:          sarifRules,
+			}},
+			Results: results,
+		}},
+	}
+
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal SARIF log: %w", err)
+	}
+	return string(data), nil
+}
+
+// sarifFixFromFix converts a fix into SARIF artifact changes, grouped by file
+func sarifFixFromFix(fix *Fix) sarifFix {
+	result := sarifFix{Description: sarifMessage{Text: fix.Description}}
+	changes := make(map[string]int)
+	for _, edit := range fix.Edits {
+		index, ok := changes[edit.File]
+		if !ok {
+			index = len(result.ArtifactChanges)
+			changes[edit.File] = index
+			result.ArtifactChanges = append(result.ArtifactChanges, sarifArtifactChange{
+				ArtifactLocation: sarifArtifactLocation{URI: edit.File},
+			})
+		}
+
+		replacement := sarifReplacement{
+			DeletedRegion: sarifRegion{
+				StartLine:   maxInt(edit.Start.Line, 1),
+				StartColumn: maxInt(edit.Start.Column, 1),
+				EndLine:     maxInt(edit.End.Line, 1),
+				EndColumn:   maxInt(edit.End.Column, 1),
+			},
+		}
+		if edit.NewText != "" {
+			replacement.InsertedContent = &sarifMessage{Text: edit.NewText}
+		}
+		result.ArtifactChanges[index].Replacements = append(result.ArtifactChanges[index].Replacements, replacement)
+	}
+	return result
+}
+
+// maxInt returns the larger of two integers
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// JUnit XML types
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// formatJUnit renders a validation result as JUnit XML with a test suite per
+// file. Each issue is a failed test case and files without issues get a
+// single passing test case.
+func formatJUnit(result *ValidationResult, config *TerraformConfiguration) (string, error) {
+	byFile := make(map[string][]ValidationIssue)
+	if config != nil {
+		for name := range config.Files {
+			byFile[name] = nil
+		}
+	}
+	for _, issue := range result.Issues {
+		file, _ := issueLocation(issue, config)
+		byFile[file] = append(byFile[file], issue)
+	}
+
+	files := make([]string, 0, len(byFile))
+	for file := range byFile {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	suites := junitTestSuites{Name: reportToolName}
+	for _, file := range files {
+		suite := junitTestSuite{Name: file}
+		if suite.Name == "" {
+			suite.Name = "configuration"
+		}
+
+		for _, issue := range byFile[file] {
+			var details strings.Builder
+			_, line := issueLocation(issue, config)
+			details.WriteString(fmt.Sprintf("%s:%d: %s\n", suite.Name, line, issue.Message))
+			if issue.BestPractice != "" {
+				details.WriteString(fmt.Sprintf("Best Practice: %s\n", issue.BestPractice))
+			}
+			if issue.Suggestion != "" {
+				details.WriteString(fmt.Sprintf("Suggestion: %s\n", issue.Suggestion))
+			}
+
+			suite.Cases = append(suite.Cases, junitTestCase{
+				Name:      issueRuleID(issue),
+				ClassName: suite.Name,
+				Failure: &junitFailure{
+					Message: issue.Message,
+					Type:    string(issue.Severity),
+					Text:    details.String(),
+				},
+			})
+			suite.Failures++
+		}
+		if len(suite.Cases) == 0 {
+			suite.Cases = append(suite.Cases, junitTestCase{Name: "validation", ClassName: suite.Name})
+		}
+
+		suite.Tests = len(suite.Cases)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	data, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JUnit report: %w", err)
+	}
+	return xml.Header + string(data) + "\n", nil
+}
+
+// Checkstyle XML types
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// formatCheckstyle renders a validation result as Checkstyle XML
+func formatCheckstyle(result *ValidationResult, config *TerraformConfiguration) (string, error) {
+	byFile := make(map[string][]checkstyleError)
+	for _, issue := range result.Issues {
+		file, line := issueLocation(issue, config)
+		byFile[file] = append(byFile[file], checkstyleError{
+			Line:     line,
+			Severity: string(issue.Severity),
+			Message:  issue.Message,
+			Source:   reportToolName + "." + issueRuleID(issue),
+		})
+	}
+
+	files := make([]string, 0, len(byFile))
+	for file := range byFile {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	report := checkstyleReport{Version: "4.3"}
+	for _, file := range files {
+		report.Files = append(report.Files, checkstyleFile{Name: file, Errors: byFile[file]})
+	}
+
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal Checkstyle report: %w", err)
+	}
+	return xml.Header + string(data) + "\n", nil
+}
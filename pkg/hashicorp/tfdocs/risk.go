@@ -0,0 +1,231 @@
+// pkg/hashicorp/tfdocs/risk.go
+package tfdocs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RiskLevel is the overall risk of applying a plan
+type RiskLevel string
+
+const (
+	RiskNone     RiskLevel = "none"
+	RiskLow      RiskLevel = "low"
+	RiskMedium   RiskLevel = "medium"
+	RiskHigh     RiskLevel = "high"
+	RiskCritical RiskLevel = "critical"
+)
+
+// RiskyChange is a resource change with its risk score
+type RiskyChange struct {
+	Address  string          `json:"address"`
+	Type     string          `json:"type"`
+	Action   ChangeAction    `json:"action"`
+	Category PatternCategory `json:"category"`
+	Score    int             `json:"score"`
+	// Stateful is true for resources holding data that is lost on destroy
+	Stateful   bool     `json:"stateful,omitempty"`
+	ReplacedBy []string `json:"replaced_by,omitempty"`
+}
+
+// PlanRiskSummary summarizes how risky it is to apply a plan
+type PlanRiskSummary struct {
+	Score      int                     `json:"score"`
+	Level      RiskLevel               `json:"level"`
+	Actions    map[ChangeAction]int    `json:"actions"`
+	Categories map[PatternCategory]int `json:"categories"`
+	TopChanges []RiskyChange           `json:"top_changes"`
+	Markdown   string                  `json:"markdown"`
+}
+
+// actionRiskWeights weight the actions of resource changes. Data source reads
+// and no-ops carry no risk.
+var actionRiskWeights = map[ChangeAction]int{
+	ActionCreate:  1,
+	ActionUpdate:  2,
+	ActionReplace: 6,
+	ActionDelete:  8,
+}
+
+// categoryRiskWeights weight the resource categories by the impact of a
+// failed or unintended change
+var categoryRiskWeights = map[PatternCategory]int{
+	CategoryDatabase:                  5,
+	CategoryStorage:                   4,
+	PatternCategory(CategorySecurity): 3,
+	CategoryNetworking:                3,
+	CategoryCompute:                   2,
+	CategoryApplication:               1,
+	CategoryMonitoring:                1,
+}
+
+// resourceCategoryKeywords map words of resource type names to categories.
+// The first category with a matching word wins, so security groups are
+// classified as security rather than networking.
+var resourceCategoryKeywords = []struct {
+	Category PatternCategory
+	Keywords []string
+}{
+	{PatternCategory(CategorySecurity), []string{"iam", "kms", "secret", "secretsmanager", "key_vault", "security_group", "firewall", "waf", "wafv2", "acm", "certificate", "role", "service_account", "guardduty", "security"}},
+	{CategoryDatabase, []string{"db", "rds", "dynamodb", "sql", "database", "mssql", "mysql", "postgresql", "cosmosdb", "redshift", "docdb", "neptune", "elasticache", "redis", "spanner", "bigtable", "bigquery", "opensearch", "elasticsearch"}},
+	{CategoryStorage, []string{"s3", "bucket", "storage", "ebs", "efs", "disk", "volume", "backup", "glacier", "fsx", "filestore"}},
+	{CategoryNetworking, []string{"vpc", "subnet", "route", "route53", "route_table", "nat", "gateway", "internet_gateway", "lb", "elb", "alb", "dns", "network", "peering", "vpn", "cloudfront", "cdn", "eip", "address", "virtual_network"}},
+	{CategoryMonitoring, []string{"cloudwatch", "log", "logging", "monitor", "monitoring", "alarm", "alert", "dashboard", "insights"}},
+	{CategoryCompute, []string{"instance", "autoscaling", "launch_template", "ecs", "eks", "kubernetes", "container", "lambda", "function", "virtual_machine", "compute", "batch"}},
+}
+
+// ResourceCategory classifies a resource type into a pattern category by the
+// words of its name. Unknown types are classified as application resources.
+func ResourceCategory(resourceType string) PatternCategory {
+	name := "_" + resourceType + "_"
+	for _, entry := range resourceCategoryKeywords {
+		for _, keyword := range entry.Keywords {
+			if strings.Contains(name, "_"+keyword+"_") {
+				return entry.Category
+			}
+		}
+	}
+	return CategoryApplication
+}
+
+// riskVolumeDivisor and riskVolumeCap bound how much the number of changes
+// adds to the score of the riskiest change, so that many low risk changes
+// do not add up to a critical plan
+const (
+	riskVolumeDivisor = 10
+	riskVolumeCap     = 20
+)
+
+// SummarizePlan scores the resource changes of a plan by action and
+// resource category and returns the overall risk with the top risky
+// changes. Destroying or replacing a stateful resource doubles its score.
+// The plan score is the score of the riskiest change plus a capped share
+// of the scores of the other changes.
+func SummarizePlan(plan *Plan, top int) *PlanRiskSummary {
+	summary := &PlanRiskSummary{
+		Actions:    make(map[ChangeAction]int),
+		Categories: make(map[PatternCategory]int),
+	}
+
+	var changes []RiskyChange
+	total, highest := 0, 0
+	for _, change := range plan.ResourceChanges {
+		action := change.Action()
+		if change.Mode == "data" {
+			action = ActionRead
+		}
+		summary.Actions[action]++
+
+		weight := actionRiskWeights[action]
+		if weight == 0 {
+			continue
+		}
+
+		category := ResourceCategory(change.Type)
+		risky := RiskyChange{
+			Address:    change.Address,
+			Type:       change.Type,
+			Action:     action,
+			Category:   category,
+			Score:      weight * categoryRiskWeights[category],
+			ReplacedBy: change.ReplacedBy(),
+		}
+		if statefulResourceTypes[change.Type] && (action == ActionDelete || action == ActionReplace) {
+			risky.Stateful = true
+			risky.Score *= 2
+		}
+
+		total += risky.Score
+		if risky.Score > highest {
+			highest = risky.Score
+		}
+		summary.Categories[category] += risky.Score
+		changes = append(changes, risky)
+	}
+
+	volume := (total - highest) / riskVolumeDivisor
+	if volume > riskVolumeCap {
+		volume = riskVolumeCap
+	}
+	summary.Score = highest + volume
+	if summary.Score > 100 {
+		summary.Score = 100
+	}
+	summary.Level = riskLevel(summary.Score)
+
+	// Keep the plan order for changes with the same score
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Score > changes[j].Score
+	})
+	if top > 0 && len(changes) > top {
+		changes = changes[:top]
+	}
+	summary.TopChanges = changes
+	summary.Markdown = summary.markdown()
+
+	return summary
+}
+
+// riskLevel returns the risk level of a plan score
+func riskLevel(score int) RiskLevel {
+	switch {
+	case score == 0:
+		return RiskNone
+	case score <= 10:
+		return RiskLow
+	case score <= 25:
+		return RiskMedium
+	case score <= 50:
+		return RiskHigh
+	}
+	return RiskCritical
+}
+
+// riskActionLabels are the verbs used for actions in the Markdown summary
+var riskActionLabels = []struct {
+	Action ChangeAction
+	Label  string
+}{
+	{ActionCreate, "to create"},
+	{ActionUpdate, "to update"},
+	{ActionReplace, "to replace"},
+	{ActionDelete, "to destroy"},
+}
+
+// markdown renders the summary for a pull request comment
+func (s *PlanRiskSummary) markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Plan risk: %s (%d/100)\n\n", s.Level, s.Score)
+
+	var counts []string
+	for _, entry := range riskActionLabels {
+		if n := s.Actions[entry.Action]; n > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", n, entry.Label))
+		}
+	}
+	if len(counts) == 0 {
+		b.WriteString("No changes.\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "%s.\n", strings.Join(counts, ", "))
+
+	if len(s.TopChanges) == 0 {
+		return b.String()
+	}
+	b.WriteString("\n### Top risky changes\n\n")
+	b.WriteString("| Score | Action | Resource | Category | Notes |\n")
+	b.WriteString("| ---: | --- | --- | --- | --- |\n")
+	for _, change := range s.TopChanges {
+		var notes []string
+		if change.Stateful {
+			notes = append(notes, "stateful, data may be lost")
+		}
+		if len(change.ReplacedBy) > 0 {
+			notes = append(notes, fmt.Sprintf("forced by `%s`", strings.Join(change.ReplacedBy, "`, `")))
+		}
+		fmt.Fprintf(&b, "| %d | %s | `%s` | %s | %s |\n", change.Score, change.Action, change.Address, change.Category, strings.Join(notes, "; "))
+	}
+	return b.String()
+}
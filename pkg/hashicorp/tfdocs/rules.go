@@ -0,0 +1,193 @@
+// pkg/hashicorp/tfdocs/rules.go
+package tfdocs
+
+import (
+	"sort"
+	"strings"
+)
+
+// RuleMetadata describes a validation rule for reports such as SARIF
+type RuleMetadata struct {
+	ID          string             `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Severity    ValidationSeverity `json:"severity"`
+	Category    ValidationCategory `json:"category"`
+	HelpURI     string             `json:"help_uri,omitempty"`
+	Tags        []string           `json:"tags,omitempty"`
+	References  []string           `json:"references,omitempty"`
+}
+
+// RuleDescriber is implemented by validators that can describe the rules
+// they check
+type RuleDescriber interface {
+	DescribeRules() []RuleMetadata
+}
+
+// Rules returns the metadata of all rules known to the engine, ordered by ID
+func (e *ValidationEngine) Rules() []RuleMetadata {
+	seen := make(map[string]bool)
+	var rules []RuleMetadata
+	var describers []RuleDescriber
+	for _, validator := range append(e.validators, e.moduleTree) {
+		if describer, ok := validator.(RuleDescriber); ok {
+			describers = append(describers, describer)
+		}
+	}
+	for _, validator := range e.planValidators {
+		if describer, ok := validator.(RuleDescriber); ok {
+			describers = append(describers, describer)
+		}
+	}
+	for _, validator := range e.stateValidators {
+		if describer, ok := validator.(RuleDescriber); ok {
+			describers = append(describers, describer)
+		}
+	}
+	for _, describer := range describers {
+		for _, rule := range describer.DescribeRules() {
+			if seen[rule.ID] {
+				continue
+			}
+			seen[rule.ID] = true
+			rules = append(rules, rule)
+		}
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].ID < rules[j].ID
+	})
+	return rules
+}
+
+// issueRuleID returns the rule ID of an issue, falling back to its category
+// for issues reported without one
+func issueRuleID(issue ValidationIssue) string {
+	if issue.RuleID != "" {
+		return issue.RuleID
+	}
+	return string(issue.Category)
+}
+
+// ruleFromIssue builds metadata for a rule that is only known from its issues
+func ruleFromIssue(issue ValidationIssue) RuleMetadata {
+	description := issue.BestPractice
+	if description == "" {
+		description = issue.Message
+	}
+	return RuleMetadata{
+		ID:          issueRuleID(issue),
+		Name:        ruleName(issueRuleID(issue)),
+		Description: description,
+		Severity:    issue.Severity,
+		Category:    issue.Category,
+		References:  issue.References,
+	}
+}
+
+// ruleName turns a rule ID such as variable-description into a name such as
+// VariableDescription
+func ruleName(id string) string {
+	var sb strings.Builder
+	for _, part := range strings.FieldsFunc(id, func(r rune) bool {
+		return r == '-' || r == '_' || r == '.' || r == '/'
+	}) {
+		sb.WriteString(strings.ToUpper(part[:1]))
+		sb.WriteString(part[1:])
+	}
+	return sb.String()
+}
+
+// DescribeRules returns the rules checked by the validator
+func (v *StructureValidator) DescribeRules() []RuleMetadata {
+	return []RuleMetadata{
+		{ID: "main-tf", Name: "MainTF", Description: "Include a main.tf file with core resource definitions", Severity: SeverityError, Category: CategoryStructure},
+		{ID: "variables-tf", Name: "VariablesTF", Description: "Include a variables.tf file for input variable definitions", Severity: SeverityWarning, Category: CategoryStructure},
+		{ID: "outputs-tf", Name: "OutputsTF", Description: "Include an outputs.tf file for output definitions", Severity: SeverityWarning, Category: CategoryStructure},
+		{ID: "file-size", Name: "FileSize", Description: "Keep Terraform files under 500 lines for better maintainability", Severity: SeverityWarning, Category: CategoryMaintenance},
+		{ID: "standard-module-files", Name: "StandardModuleFiles", Description: "Follow standard module structure with main.tf, variables.tf, outputs.tf, and README.md", Severity: SeverityInfo, Category: CategoryStructure},
+		{ID: "module-tests", Name: "ModuleTests", Description: "Test modules with .tftest.hcl files planning the module and checking its outputs and validations", Severity: SeverityInfo, Category: CategoryStructure, HelpURI: "https://developer.hashicorp.com/terraform/language/tests"},
+	}
+}
+
+// DescribeRules returns the rules checked by the validator
+func (v *NamingValidator) DescribeRules() []RuleMetadata {
+	return []RuleMetadata{
+		{ID: "variable-naming", Name: "VariableNaming", Description: "Use lowercase letters and underscores in variable names", Severity: SeverityWarning, Category: CategoryNaming},
+		{ID: "resource-naming", Name: "ResourceNaming", Description: "Use underscores in resource names for readability", Severity: SeverityInfo, Category: CategoryNaming},
+	}
+}
+
+// DescribeRules returns the rules checked by the validator
+func (v *SecurityValidator) DescribeRules() []RuleMetadata {
+	rules := []RuleMetadata{
+		{ID: "hardcoded-secrets", Name: "HardcodedSecrets", Description: "Never hardcode sensitive values in Terraform configuration", Severity: SeverityError, Category: CategorySecurity, HelpURI: cweHelpURI([]string{"CWE-798"}), Tags: []string{"security"}, References: []string{"CWE-798"}},
+		{ID: "sensitive-variables", Name: "SensitiveVariables", Description: "Mark sensitive variables with sensitive = true", Severity: SeverityWarning, Category: CategorySecurity, HelpURI: cweHelpURI([]string{"CWE-532"}), Tags: []string{"security"}, References: []string{"CWE-532"}},
+	}
+	return append(rules, securityPackRules()...)
+}
+
+// DescribeRules returns the rules checked by the validator
+func (v *DocumentationValidator) DescribeRules() []RuleMetadata {
+	return []RuleMetadata{
+		{ID: "readme", Name: "Readme", Description: "Include a README.md file with module documentation", Severity: SeverityWarning, Category: CategoryDocumentation},
+		{ID: "variable-description", Name: "VariableDescription", Description: "Add descriptions to all variables", Severity: SeverityWarning, Category: CategoryDocumentation},
+		{ID: "output-description", Name: "OutputDescription", Description: "Add descriptions to all outputs", Severity: SeverityInfo, Category: CategoryDocumentation},
+		{ID: "variable-type", Name: "VariableType", Description: "Declare an explicit type for all variables", Severity: SeverityWarning, Category: CategoryDocumentation, HelpURI: variablesHelpURI, References: []string{variablesPracticeID}},
+		{ID: "variable-any-type", Name: "VariableAnyType", Description: "Prefer specific types over any so invalid values are rejected early", Severity: SeverityInfo, Category: CategoryDocumentation, HelpURI: variablesHelpURI, References: []string{variablesPracticeID}},
+		{ID: "variable-nullable", Name: "VariableNullable", Description: "Set nullable = false on variables with a non-null default", Severity: SeverityInfo, Category: CategoryDocumentation, HelpURI: variablesHelpURI, References: []string{variablesPracticeID}},
+		{ID: "variable-validation", Name: "VariableValidation", Description: "Validate the values of variables with a known format or set of allowed values", Severity: SeverityInfo, Category: CategoryDocumentation, HelpURI: variablesHelpURI, References: []string{variablesPracticeID}},
+		{ID: "suggested-outputs", Name: "SuggestedOutputs", Description: "Expose the IDs, ARNs and endpoints of key resources as outputs so callers can consume them", Severity: SeverityInfo, Category: CategoryStructure},
+	}
+}
+
+// DescribeRules returns the rules checked by the validator
+func (v *ModuleValidator) DescribeRules() []RuleMetadata {
+	return []RuleMetadata{
+		{ID: "module-version", Name: "ModuleVersion", Description: "Always specify module versions for stability", Severity: SeverityWarning, Category: CategoryMaintenance},
+		{ID: "module-source-unpinned", Name: "ModuleSourceUnpinned", Description: "Pin git sources to a tag or commit and http sources to a versioned archive", Severity: SeverityWarning, Category: CategoryMaintenance},
+	}
+}
+
+// DescribeRules returns the rules checked by the validator
+func (v *ResourceValidator) DescribeRules() []RuleMetadata {
+	return []RuleMetadata{
+		{ID: "prefer-for-each", Name: "PreferForEach", Description: "Use for_each instead of count when iterating over complex values", Severity: SeverityInfo, Category: CategoryMaintenance},
+	}
+}
+
+// DescribeRules returns the custom rules loaded by the validator
+func (v *CustomRuleValidator) DescribeRules() []RuleMetadata {
+	var rules []RuleMetadata
+	for _, rule := range v.Rules() {
+		description := rule.Description
+		if description == "" {
+			description = rule.BestPractice
+		}
+		rules = append(rules, RuleMetadata{
+			ID:          rule.ID,
+			Name:        ruleName(rule.ID),
+			Description: description,
+			Severity:    rule.Severity,
+			Category:    rule.Category,
+			Tags:        []string{"custom"},
+		})
+	}
+	return rules
+}
+
+// DescribeRules returns the deny and warn rules of the loaded policies
+func (v *RegoValidator) DescribeRules() []RuleMetadata {
+	var rules []RuleMetadata
+	for _, q := range v.queries {
+		rules = append(rules, RuleMetadata{
+			ID:          q.pkg + "." + q.rule,
+			Name:        ruleName(q.pkg + "." + q.rule),
+			Description: "Satisfy the " + q.pkg + " policy",
+			Severity:    q.severity,
+			Category:    CategorySecurity,
+			Tags:        []string{"rego"},
+		})
+	}
+	return rules
+}
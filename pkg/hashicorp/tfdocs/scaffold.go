@@ -0,0 +1,497 @@
+// pkg/hashicorp/tfdocs/scaffold.go
+package tfdocs
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ModuleSpec describes a module to scaffold
+type ModuleSpec struct {
+	Name          string   `json:"name"`
+	Provider      string   `json:"provider"`
+	ResourceTypes []string `json:"resource_types"`
+	Examples      bool     `json:"examples,omitempty"`
+	Tests         bool     `json:"tests,omitempty"`
+	CIWorkflow    bool     `json:"ci_workflow,omitempty"`
+	PreCommit     bool     `json:"pre_commit,omitempty"`
+}
+
+// ScaffoldResult is a generated module
+type ScaffoldResult struct {
+	Files map[string]string `json:"files"`
+	// Patterns are the IDs of the patterns resources were taken from
+	Patterns []string `json:"patterns"`
+	// Resources are the addresses of the resources of the module
+	Resources []string `json:"resources"`
+	// Placeholders are the resource types no pattern covers, scaffolded as
+	// resources with their arguments left to fill in
+	Placeholders []string `json:"placeholders,omitempty"`
+}
+
+// scaffoldProvider is the source and version constraint of a provider
+type scaffoldProvider struct {
+	Source  string
+	Version string
+	// Example configures the provider in example callers
+	Example string
+}
+
+// scaffoldProviders are the providers known to the scaffolder by local name
+var scaffoldProviders = map[string]scaffoldProvider{
+	"aws":     {Source: "hashicorp/aws", Version: "~> 5.0", Example: "provider \"aws\" {\n  region = \"us-east-1\"\n}\n"},
+	"azurerm": {Source: "hashicorp/azurerm", Version: "~> 3.0", Example: "provider \"azurerm\" {\n  features {}\n}\n"},
+	"google":  {Source: "hashicorp/google", Version: "~> 5.0", Example: "provider \"google\" {\n  project = \"my-project\"\n  region  = \"us-central1\"\n}\n"},
+	"random":  {Source: "hashicorp/random", Version: "~> 3.0"},
+}
+
+// providerAliases map the cloud provider names of patterns to the local
+// names of their Terraform providers
+var providerAliases = map[string]string{
+	"azure": "azurerm",
+	"gcp":   "google",
+}
+
+const (
+	// scaffoldRequiredVersion is the Terraform version generated modules require
+	scaffoldRequiredVersion = ">= 1.5.0, < 2.0.0"
+	// testsRequiredVersion is required by modules with generated tests, since
+	// override_during of mock providers was added in Terraform 1.11
+	testsRequiredVersion = ">= 1.11.0, < 2.0.0"
+)
+
+// placeholderDefaults are the secure arguments placeholders of a resource type
+// start with, so that scaffolded modules pass the security rule pack
+var placeholderDefaults = map[string][]string{
+	"aws_cloudtrail":                    {"enable_log_file_validation = true"},
+	"aws_db_instance":                   {"storage_encrypted   = true", "publicly_accessible = false"},
+	"aws_ebs_encryption_by_default":     {"enabled = true"},
+	"aws_ebs_volume":                    {"encrypted = true"},
+	"aws_rds_cluster":                   {"storage_encrypted = true"},
+	"aws_rds_cluster_instance":          {"publicly_accessible = false"},
+	"aws_s3_bucket_acl":                 {"acl = \"private\""},
+	"aws_s3_bucket_public_access_block": {"block_public_acls       = true", "block_public_policy     = true", "ignore_public_acls      = true", "restrict_public_buckets = true"},
+	"azurerm_storage_account":           {"https_traffic_only_enabled      = true", "min_tls_version                 = \"TLS1_2\"", "allow_nested_items_to_be_public = false"},
+	"azurerm_storage_container":         {"container_access_type = \"private\""},
+}
+
+// moduleNamePattern matches valid module names
+var moduleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+// defaultModuleLayout is the layout used when no module structure is known
+var defaultModuleLayout = []ModuleStructureFile{
+	{Name: "main.tf", Description: "Contains the main resources of the module", Required: true},
+	{Name: "variables.tf", Description: "Contains the input variables for the module", Required: true},
+	{Name: "outputs.tf", Description: "Contains the outputs from the module", Required: true},
+	{Name: "versions.tf", Description: "Contains provider and terraform version constraints", Required: true},
+	{Name: "README.md", Description: "Contains documentation for the module", Required: true},
+}
+
+// scaffoldSource is a pattern parsed for scaffolding
+type scaffoldSource struct {
+	pattern *Pattern
+	config  *TerraformConfiguration
+	graph   *ReferenceGraph
+}
+
+// ScaffoldModule generates a module with the resources of a spec. The files
+// follow the layout of the module structure, resources are taken from the
+// patterns declaring them together with the resources, data sources and
+// locals they depend on, and the variables and outputs are derived from
+// the resulting configuration. Resource types no pattern declares are
+// scaffolded as resources whose arguments are left to fill in.
+func ScaffoldModule(spec ModuleSpec, structure *ModuleStructureDoc, patterns []*Pattern) (*ScaffoldResult, error) {
+	if !moduleNamePattern.MatchString(spec.Name) {
+		return nil, fmt.Errorf("invalid module name %q: use lowercase letters, digits, dashes and underscores", spec.Name)
+	}
+	if len(spec.ResourceTypes) == 0 {
+		return nil, fmt.Errorf("at least one resource type is required")
+	}
+	provider := spec.Provider
+	if alias, ok := providerAliases[provider]; ok {
+		provider = alias
+	}
+
+	sources := scaffoldSources(patterns, provider)
+	result := &ScaffoldResult{
+		Files:     make(map[string]string),
+		Patterns:  []string{},
+		Resources: []string{},
+	}
+
+	// Collect the blocks of each resource type with their dependencies
+	var blocks, locals []string
+	variables := make(map[string]string)
+	included := make(map[string]bool)
+	for _, resourceType := range spec.ResourceTypes {
+		source := findPatternResource(sources, resourceType)
+		if source == nil {
+			address := resourceType + ".this"
+			if included[address] {
+				continue
+			}
+			included[address] = true
+			blocks = append(blocks, placeholderResource(resourceType))
+			result.Resources = append(result.Resources, address)
+			result.Placeholders = append(result.Placeholders, resourceType)
+			continue
+		}
+
+		if !containsString(result.Patterns, source.pattern.ID) {
+			result.Patterns = append(result.Patterns, source.pattern.ID)
+		}
+		needed := source.dependencies(resourceType)
+		for _, block := range source.config.Blocks("") {
+			if (block.Type != "resource" && block.Type != "data") || !needed[block.Address()] || included[block.Address()] {
+				continue
+			}
+			included[block.Address()] = true
+			blocks = append(blocks, source.text(block))
+			if block.Type == "resource" {
+				result.Resources = append(result.Resources, block.Address())
+			}
+		}
+		for _, block := range source.config.Blocks("locals") {
+			for _, attr := range sortedAttributes(block.Attributes) {
+				address := "local." + attr.Name
+				if needed[address] && !included[address] {
+					included[address] = true
+					locals = append(locals, fmt.Sprintf("  %s = %s\n", attr.Name, attr.Source))
+				}
+			}
+		}
+		for _, block := range source.config.Blocks("variable") {
+			if _, ok := variables[block.Label(0)]; !ok {
+				variables[block.Label(0)] = source.text(block)
+			}
+		}
+	}
+
+	// Keep the outputs of the patterns referring only to included symbols
+	var outputs []string
+	for _, source := range sources {
+		if !containsString(result.Patterns, source.pattern.ID) {
+			continue
+		}
+		for _, block := range source.config.Blocks("output") {
+			if source.outputIncluded(block.Address(), included) && !included[block.Address()] {
+				included[block.Address()] = true
+				outputs = append(outputs, source.text(block))
+			}
+		}
+	}
+
+	main := strings.Join(blocks, "\n")
+	if len(locals) > 0 {
+		main = "locals {\n" + strings.Join(locals, "") + "}\n\n" + main
+	}
+	module := &TerraformConfiguration{Files: map[string]string{
+		"main.tf":    main,
+		"outputs.tf": strings.Join(outputs, "\n"),
+	}}
+
+	// Declare the variables the resources use, preferring the declarations
+	// of the patterns and of the module structure
+	declared := make(map[string]string)
+	if structure != nil {
+		for _, file := range structure.Files {
+			if file.Name != "variables.tf" {
+				continue
+			}
+			layout := &TerraformConfiguration{Files: map[string]string{file.Name: file.Content}}
+			for _, block := range layout.Blocks("variable") {
+				declared[block.Label(0)] = layout.ParsedFile(file.Name).text(block)
+			}
+		}
+	}
+	var names []string
+	for _, name := range undeclaredVariables(module.ReferenceGraph()) {
+		if text, ok := variables[name]; ok {
+			declared[name] = text
+		}
+		names = append(names, name)
+	}
+	var declarations, generated []string
+	for _, name := range names {
+		if text, ok := declared[name]; ok {
+			declarations = append(declarations, text)
+		} else {
+			generated = append(generated, name)
+		}
+	}
+	if len(generated) > 0 {
+		declarations = append(declarations, variableDeclarations(module, generated))
+	}
+	module.Files["variables.tf"] = strings.Join(declarations, "\n")
+	if suggestions := module.SuggestedOutputs(); len(suggestions) > 0 {
+		module.Files["outputs.tf"] = strings.Join(append(outputs, outputDeclarations(suggestions)), "\n")
+	}
+	// The mock providers of generated tests need a newer Terraform version
+	requiredVersion := scaffoldRequiredVersion
+	if spec.Tests {
+		requiredVersion = testsRequiredVersion
+	}
+	module.Files["versions.tf"] = scaffoldVersions(requiredVersion, usedProviders(module))
+
+	// Lay out the files of the module structure
+	layout := defaultModuleLayout
+	if structure != nil && len(structure.Files) > 0 {
+		layout = structure.Files
+	}
+	for _, file := range layout {
+		switch content, ok := module.Files[file.Name]; {
+		case ok:
+			result.Files[file.Name] = fmt.Sprintf("# %s\n# %s\n\n%s", file.Name, file.Description, content)
+		case file.Name == "README.md":
+		default:
+			result.Files[file.Name] = file.Content
+		}
+	}
+	for name, content := range module.Files {
+		if _, ok := result.Files[name]; !ok {
+			result.Files[name] = content
+		}
+	}
+	for _, pattern := range patterns {
+		if content, ok := pattern.Files[".gitignore"]; ok {
+			result.Files[".gitignore"] = content
+			break
+		}
+	}
+
+	root := &TerraformConfiguration{Files: result.Files}
+	label := strings.ReplaceAll(spec.Name, "-", "_")
+	result.Files["README.md"] = GenerateReadme(root, WithReadmeTitle(spec.Name), WithModuleSource(label, "./modules/"+spec.Name))
+
+	// Add the optional files
+	if spec.Examples {
+		for name, content := range GenerateExamples(root, spec.Name) {
+			result.Files[name] = content
+		}
+	}
+	if spec.Tests {
+		for name, content := range GenerateTests(root, label).Files {
+			result.Files[name] = content
+		}
+	}
+	if spec.CIWorkflow {
+		result.Files[".github/workflows/terraform.yml"] = scaffoldWorkflow(spec.Tests)
+	}
+	if spec.PreCommit {
+		result.Files[".pre-commit-config.yaml"] = defaultPreCommitConfig
+	}
+
+	// Format the generated Terraform files
+	for name, content := range result.Files {
+		if !isFormattedFile(name) {
+			continue
+		}
+		formatted, err := FormatHCL(name, content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format generated file: %w", err)
+		}
+		result.Files[name] = formatted
+	}
+
+	return result, nil
+}
+
+// scaffoldSources parses the Terraform files of the patterns, with the
+// patterns of the given provider first
+func scaffoldSources(patterns []*Pattern, provider string) []*scaffoldSource {
+	sorted := make([]*Pattern, len(patterns))
+	copy(sorted, patterns)
+	rank := func(pattern *Pattern) int {
+		if name := string(pattern.Provider); name == provider || providerAliases[name] == provider {
+			return 0
+		}
+		return 1
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if rank(sorted[i]) != rank(sorted[j]) {
+			return rank(sorted[i]) < rank(sorted[j])
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	var sources []*scaffoldSource
+	for _, pattern := range sorted {
+		files := make(map[string]string)
+		for name, content := range pattern.Files {
+			if strings.HasSuffix(name, ".tf") {
+				files[name] = content
+			}
+		}
+		config := &TerraformConfiguration{Files: files}
+		sources = append(sources, &scaffoldSource{pattern: pattern, config: config, graph: config.ReferenceGraph()})
+	}
+	return sources
+}
+
+// findPatternResource returns the first pattern declaring a resource type
+func findPatternResource(sources []*scaffoldSource, resourceType string) *scaffoldSource {
+	for _, source := range sources {
+		for _, block := range source.config.Blocks("resource") {
+			if block.Label(0) == resourceType {
+				return source
+			}
+		}
+	}
+	return nil
+}
+
+// dependencies returns the addresses of the resources of a type in the
+// pattern and of the symbols they depend on, transitively
+func (s *scaffoldSource) dependencies(resourceType string) map[string]bool {
+	needed := make(map[string]bool)
+	var queue []string
+	for _, block := range s.config.Blocks("resource") {
+		if block.Label(0) == resourceType {
+			needed[block.Address()] = true
+			queue = append(queue, block.Address())
+		}
+	}
+
+	for len(queue) > 0 {
+		address := queue[0]
+		queue = queue[1:]
+		for _, ref := range s.graph.References {
+			if ref.From != address || needed[ref.To] {
+				continue
+			}
+			needed[ref.To] = true
+			if symbol, ok := s.graph.Symbols[ref.To]; ok && symbol.Kind != SymbolVariable {
+				queue = append(queue, ref.To)
+			}
+		}
+	}
+	return needed
+}
+
+// outputIncluded reports whether an output of the pattern refers only to
+// variables and included symbols
+func (s *scaffoldSource) outputIncluded(address string, included map[string]bool) bool {
+	refers := false
+	for _, ref := range s.graph.References {
+		if ref.From != address || strings.HasPrefix(ref.To, "var.") {
+			continue
+		}
+		if !included[ref.To] {
+			return false
+		}
+		refers = true
+	}
+	return refers
+}
+
+// text returns the source text of a block of the pattern
+func (s *scaffoldSource) text(block *Block) string {
+	return s.config.ParsedFile(block.File).text(block)
+}
+
+// text returns the source text of a block of the file
+func (f *ParsedFile) text(block *Block) string {
+	return string(f.Source[block.Range.Start.Byte:block.Range.End.Byte]) + "\n"
+}
+
+// placeholderResource returns a resource of a type no pattern declares
+func placeholderResource(resourceType string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "resource %q \"this\" {\n", resourceType)
+	fmt.Fprintf(&b, "  # Set the arguments of %s\n", resourceType)
+	if defaults := placeholderDefaults[resourceType]; len(defaults) > 0 {
+		b.WriteString("\n")
+		for _, attr := range defaults {
+			fmt.Fprintf(&b, "  %s\n", attr)
+		}
+	}
+	if strings.HasPrefix(resourceType, "aws_") || strings.HasPrefix(resourceType, "azurerm_") {
+		b.WriteString("\n  tags = var.tags\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// scaffoldVersions returns the terraform block requiring a Terraform version
+// and the given providers
+func scaffoldVersions(requiredVersion string, providers []string) string {
+	requirements := make([]ProviderRequirement, 0, len(providers))
+	for _, name := range providers {
+		provider, ok := scaffoldProviders[name]
+		if !ok {
+			provider.Source = "hashicorp/" + name
+		}
+		requirements = append(requirements, ProviderRequirement{Name: name, Source: provider.Source, Version: provider.Version})
+	}
+	return versionsBlock(requiredVersion, requirements)
+}
+
+// versionsBlock returns a terraform block with a required Terraform version
+// and provider requirements
+func versionsBlock(requiredVersion string, requirements []ProviderRequirement) string {
+	var b strings.Builder
+	b.WriteString("terraform {\n")
+	if requiredVersion != "" {
+		fmt.Fprintf(&b, "  required_version = %q\n", requiredVersion)
+	}
+	if len(requirements) > 0 {
+		if requiredVersion != "" {
+			b.WriteString("\n")
+		}
+		b.WriteString("  required_providers {\n")
+		for _, requirement := range requirements {
+			fmt.Fprintf(&b, "    %s = {\n      source = %q\n", requirement.Name, requirement.Address())
+			if requirement.Version != "" {
+				fmt.Fprintf(&b, "      version = %q\n", requirement.Version)
+			}
+			b.WriteString("    }\n")
+		}
+		b.WriteString("  }\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// scaffoldWorkflow returns a GitHub Actions workflow checking the module
+func scaffoldWorkflow(tests bool) string {
+	workflow := `name: Terraform
+
+on:
+  push:
+    branches: [main]
+  pull_request:
+
+jobs:
+  validate:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - uses: hashicorp/setup-terraform@v3
+      - name: Check formatting
+        run: terraform fmt -check -recursive
+      - name: Initialize
+        run: terraform init -backend=false
+      - name: Validate
+        run: terraform validate
+`
+	if tests {
+		workflow += `      - name: Test
+        run: terraform test
+`
+	}
+	return workflow
+}
+
+// defaultPreCommitConfig runs the Terraform hooks of pre-commit-terraform
+const defaultPreCommitConfig = `repos:
+  - repo: https://github.com/antonbabenko/pre-commit-terraform
+    rev: v1.96.1
+    hooks:
+      - id: terraform_fmt
+      - id: terraform_validate
+      - id: terraform_docs
+        args: ["--args=--output-mode=inject"]
+      - id: terraform_tflint
+`
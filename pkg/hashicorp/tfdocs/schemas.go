@@ -0,0 +1,431 @@
+// pkg/hashicorp/tfdocs/schemas.go
+package tfdocs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ProviderSchemas is the output of terraform providers schema -json
+type ProviderSchemas struct {
+	FormatVersion   string                     `json:"format_version"`
+	ProviderSchemas map[string]*ProviderSchema `json:"provider_schemas"`
+}
+
+// ProviderSchema holds the resource and data source schemas of a provider
+type ProviderSchema struct {
+	ResourceSchemas   map[string]*Schema `json:"resource_schemas"`
+	DataSourceSchemas map[string]*Schema `json:"data_source_schemas"`
+}
+
+// Schema is the schema of a resource type or data source
+type Schema struct {
+	Version int          `json:"version"`
+	Block   *SchemaBlock `json:"block"`
+}
+
+// SchemaBlock describes the attributes and nested blocks of a block
+type SchemaBlock struct {
+	Attributes map[string]*SchemaAttribute   `json:"attributes"`
+	BlockTypes map[string]*SchemaNestedBlock `json:"block_types"`
+	Deprecated bool                          `json:"deprecated"`
+}
+
+// SchemaAttribute describes an attribute of a block
+type SchemaAttribute struct {
+	Type        json.RawMessage `json:"type"`
+	Description string          `json:"description"`
+	Required    bool            `json:"required"`
+	Optional    bool            `json:"optional"`
+	Computed    bool            `json:"computed"`
+	Sensitive   bool            `json:"sensitive"`
+	Deprecated  bool            `json:"deprecated"`
+}
+
+// SchemaNestedBlock describes a nested block type
+type SchemaNestedBlock struct {
+	NestingMode string       `json:"nesting_mode"`
+	Block       *SchemaBlock `json:"block"`
+	MinItems    int          `json:"min_items"`
+	MaxItems    int          `json:"max_items"`
+}
+
+// SchemaRegistry holds the resource and data source schemas of all loaded
+// providers
+type SchemaRegistry struct {
+	mu          sync.RWMutex
+	resources   map[string]*Schema
+	dataSources map[string]*Schema
+}
+
+// NewSchemaRegistry creates an empty schema registry
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{
+		resources:   make(map[string]*Schema),
+		dataSources: make(map[string]*Schema),
+	}
+}
+
+// LoadProviderSchemas loads all *.json files of a directory, each holding the
+// output of terraform providers schema -json. A missing directory yields no
+// schemas.
+func LoadProviderSchemas(dir string) ([]*ProviderSchemas, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read schema directory: %w", err)
+	}
+
+	var schemas []*ProviderSchemas
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read schema file %s: %w", entry.Name(), err)
+		}
+
+		var schema ProviderSchemas
+		if err := json.Unmarshal(data, &schema); err != nil {
+			return nil, fmt.Errorf("failed to parse schema file %s: %w", entry.Name(), err)
+		}
+		if schema.ProviderSchemas == nil {
+			return nil, fmt.Errorf("schema file %s has no provider_schemas", entry.Name())
+		}
+		schemas = append(schemas, &schema)
+	}
+
+	return schemas, nil
+}
+
+// Add adds the schemas of every provider. Later schemas replace earlier
+// ones for the same resource type.
+func (r *SchemaRegistry) Add(schemas *ProviderSchemas) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, provider := range schemas.ProviderSchemas {
+		for name, schema := range provider.ResourceSchemas {
+			if schema != nil && schema.Block != nil {
+				r.resources[name] = schema
+			}
+		}
+		for name, schema := range provider.DataSourceSchemas {
+			if schema != nil && schema.Block != nil {
+				r.dataSources[name] = schema
+			}
+		}
+	}
+}
+
+// Resource returns the schema of a resource type
+func (r *SchemaRegistry) Resource(resourceType string) (*Schema, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	schema, ok := r.resources[resourceType]
+	return schema, ok
+}
+
+// DataSource returns the schema of a data source
+func (r *SchemaRegistry) DataSource(dataType string) (*Schema, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	schema, ok := r.dataSources[dataType]
+	return schema, ok
+}
+
+// Count returns the number of resource and data source schemas
+func (r *SchemaRegistry) Count() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.resources) + len(r.dataSources)
+}
+
+// schemaFor returns the schema of a resource or data block
+func (r *SchemaRegistry) schemaFor(block *Block) (*Schema, bool) {
+	switch block.Type {
+	case "resource":
+		return r.Resource(block.Label(0))
+	case "data":
+		return r.DataSource(block.Label(0))
+	}
+	return nil, false
+}
+
+// Taggable reports whether a resource type has a tags attribute. The second
+// return value is false when no schema is loaded for the type.
+func (r *SchemaRegistry) Taggable(resourceType string) (bool, bool) {
+	schema, ok := r.Resource(resourceType)
+	if !ok {
+		return false, false
+	}
+	attr, ok := schema.Block.Attributes["tags"]
+	return ok && (attr.Optional || attr.Required), true
+}
+
+// metaArguments are the arguments and blocks Terraform handles for every
+// resource and data source
+var metaArguments = map[string]bool{
+	"count":       true,
+	"for_each":    true,
+	"provider":    true,
+	"depends_on":  true,
+	"lifecycle":   true,
+	"provisioner": true,
+	"connection":  true,
+}
+
+// SchemaValidator checks resources and data sources against the loaded
+// provider schemas
+type SchemaValidator struct {
+	schemas *SchemaRegistry
+}
+
+// Name returns the name of the validator
+func (v *SchemaValidator) Name() string {
+	return "SchemaValidator"
+}
+
+// Validate validates the resources and data sources of a Terraform
+// configuration against their provider schemas. Types without a loaded
+// schema are skipped.
+func (v *SchemaValidator) Validate(config *TerraformConfiguration) []ValidationIssue {
+	var issues []ValidationIssue
+	if v.schemas == nil || v.schemas.Count() == 0 {
+		return issues
+	}
+
+	for _, file := range config.ParsedFiles() {
+		for _, block := range file.Blocks {
+			schema, ok := v.schemas.schemaFor(block)
+			if !ok {
+				continue
+			}
+
+			kind := "Resource type"
+			if block.Type == "data" {
+				kind = "Data source"
+			}
+			if schema.Block.Deprecated {
+				issues = append(issues, ValidationIssue{
+					Message:      fmt.Sprintf("%s '%s' is deprecated", kind, block.Label(0)),
+					RuleID:       "deprecated-attributes",
+					Severity:     SeverityWarning,
+					Category:     CategoryMaintenance,
+					File:         block.File,
+					Line:         block.Line,
+					BestPractice: "Migrate away from deprecated resource types and attributes before they are removed",
+					Suggestion:   fmt.Sprintf("Check the provider documentation for the replacement of '%s'", block.Label(0)),
+				})
+			}
+
+			issues = append(issues, v.validateBlock(block, schema.Block, block.Address(), true)...)
+		}
+	}
+
+	return issues
+}
+
+// validateBlock checks the attributes and nested blocks of a block against
+// its schema
+func (v *SchemaValidator) validateBlock(block *Block, schema *SchemaBlock, address string, topLevel bool) []ValidationIssue {
+	var issues []ValidationIssue
+
+	for _, attr := range sortedAttributes(block.Attributes) {
+		if topLevel && metaArguments[attr.Name] {
+			continue
+		}
+		attrSchema, ok := schema.Attributes[attr.Name]
+		switch {
+		case !ok:
+			issues = append(issues, unknownArgumentIssue(address, "argument", attr.Name, attr.File, attr.Line, schema))
+		case !attrSchema.Required && !attrSchema.Optional:
+			issues = append(issues, ValidationIssue{
+				Message:      fmt.Sprintf("Attribute '%s' of %s is read-only and cannot be set", attr.Name, address),
+				RuleID:       "unknown-attributes",
+				Severity:     SeverityError,
+				Category:     CategoryStructure,
+				File:         attr.File,
+				Line:         attr.Line,
+				BestPractice: "Only set arguments that the provider schema accepts",
+				Suggestion:   fmt.Sprintf("Remove '%s'; it is computed by the provider", attr.Name),
+			})
+		case attrSchema.Deprecated:
+			issues = append(issues, deprecatedArgumentIssue(address, "Attribute", attr.Name, attr.File, attr.Line))
+		}
+	}
+
+	// Count the nested blocks of each type, including dynamic blocks
+	counts := make(map[string]int)
+	dynamic := make(map[string]bool)
+	for _, nested := range block.Blocks {
+		blockType, body := nested.Type, nested
+		if nested.Type == "dynamic" {
+			blockType = nested.Label(0)
+			dynamic[blockType] = true
+			body = nil
+			if content := nested.NestedBlocks("content"); len(content) > 0 {
+				body = content[0]
+			}
+		} else if topLevel && metaArguments[nested.Type] {
+			continue
+		}
+
+		blockSchema, ok := schema.BlockTypes[blockType]
+		if !ok {
+			issues = append(issues, unknownArgumentIssue(address, "block", blockType, nested.File, nested.Line, schema))
+			continue
+		}
+		counts[blockType]++
+		if blockSchema.Block == nil {
+			continue
+		}
+		if blockSchema.Block.Deprecated {
+			issues = append(issues, deprecatedArgumentIssue(address, "Block", blockType, nested.File, nested.Line))
+		}
+		if body != nil {
+			issues = append(issues, v.validateBlock(body, blockSchema.Block, address+"."+blockType, false)...)
+		}
+	}
+
+	// Check for missing required arguments and blocks
+	for _, name := range sortedSchemaAttributes(schema) {
+		if !schema.Attributes[name].Required {
+			continue
+		}
+		if _, ok := block.Attributes[name]; ok {
+			continue
+		}
+		issues = append(issues, missingArgumentIssue(block, address, "argument", name))
+	}
+	for _, name := range sortedSchemaBlockTypes(schema) {
+		if schema.BlockTypes[name].MinItems == 0 || dynamic[name] {
+			continue
+		}
+		if counts[name] >= schema.BlockTypes[name].MinItems {
+			continue
+		}
+		issues = append(issues, missingArgumentIssue(block, address, "block", name))
+	}
+
+	return issues
+}
+
+func unknownArgumentIssue(address, kind, name, file string, line int, schema *SchemaBlock) ValidationIssue {
+	suggestion := fmt.Sprintf("Remove '%s' or check its spelling", name)
+	if similar := similarName(name, schema); similar != "" {
+		suggestion = fmt.Sprintf("Did you mean '%s'?", similar)
+	}
+	return ValidationIssue{
+		Message:      fmt.Sprintf("%s does not support the %s '%s'", address, kind, name),
+		RuleID:       "unknown-attributes",
+		Severity:     SeverityError,
+		Category:     CategoryStructure,
+		File:         file,
+		Line:         line,
+		BestPractice: "Only set arguments that the provider schema accepts",
+		Suggestion:   suggestion,
+	}
+}
+
+func deprecatedArgumentIssue(address, kind, name, file string, line int) ValidationIssue {
+	return ValidationIssue{
+		Message:      fmt.Sprintf("%s '%s' of %s is deprecated", kind, name, address),
+		RuleID:       "deprecated-attributes",
+		Severity:     SeverityWarning,
+		Category:     CategoryMaintenance,
+		File:         file,
+		Line:         line,
+		BestPractice: "Migrate away from deprecated resource types and attributes before they are removed",
+		Suggestion:   fmt.Sprintf("Check the provider documentation for the replacement of '%s'", name),
+	}
+}
+
+func missingArgumentIssue(block *Block, address, kind, name string) ValidationIssue {
+	return ValidationIssue{
+		Message:      fmt.Sprintf("%s is missing the required %s '%s'", address, kind, name),
+		RuleID:       "missing-required-attributes",
+		Severity:     SeverityError,
+		Category:     CategoryStructure,
+		File:         block.File,
+		Line:         block.Line,
+		BestPractice: "Set every argument the provider schema requires",
+		Suggestion:   fmt.Sprintf("Add '%s' to %s", name, address),
+	}
+}
+
+// similarName returns the attribute or block type of a schema closest to a
+// misspelled name, if it is at most two edits away
+func similarName(name string, schema *SchemaBlock) string {
+	best, bestDistance := "", 3
+	for _, candidate := range append(sortedSchemaAttributes(schema), sortedSchemaBlockTypes(schema)...) {
+		if distance := editDistance(strings.ToLower(name), candidate); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = previous[j-1] + cost
+			if previous[j]+1 < current[j] {
+				current[j] = previous[j] + 1
+			}
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1
+			}
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func sortedSchemaAttributes(schema *SchemaBlock) []string {
+	names := make([]string, 0, len(schema.Attributes))
+	for name := range schema.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedSchemaBlockTypes(schema *SchemaBlock) []string {
+	names := make([]string, 0, len(schema.BlockTypes))
+	for name := range schema.BlockTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DescribeRules returns the rules checked by the validator
+func (v *SchemaValidator) DescribeRules() []RuleMetadata {
+	return []RuleMetadata{
+		{ID: "unknown-attributes", Name: "UnknownAttributes", Description: "Only set arguments that the provider schema accepts", Severity: SeverityError, Category: CategoryStructure},
+		{ID: "missing-required-attributes", Name: "MissingRequiredAttributes", Description: "Set every argument the provider schema requires", Severity: SeverityError, Category: CategoryStructure},
+		{ID: "deprecated-attributes", Name: "DeprecatedAttributes", Description: "Migrate away from deprecated resource types and attributes before they are removed", Severity: SeverityWarning, Category: CategoryMaintenance},
+	}
+}
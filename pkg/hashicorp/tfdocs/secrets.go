@@ -0,0 +1,504 @@
+// pkg/hashicorp/tfdocs/secrets.go
+package tfdocs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultMinEntropy is the Shannon entropy in bits per character above which
+// random-looking strings are reported
+const defaultMinEntropy = 4.5
+
+// minEntropyLength is the length a token must have to be scored by entropy
+const minEntropyLength = 20
+
+// entropyLengthFactor scales the maximum entropy of a token, log2 of its
+// length, into the threshold for tokens too short to reach the minimum
+// entropy. Random base62 tokens of 20 to 40 characters score about 0.9.
+const entropyLengthFactor = 0.85
+
+// minClassTransitions is the share of adjacent characters of a token that
+// must switch between upper case, lower case and digits. Random tokens
+// switch at about 60% of them, words joined in CamelCase at about a third.
+const minClassTransitions = 0.4
+
+// SecretPolicy configures the secrets scanner under secrets in a YAML file
+// of the policy directory
+type SecretPolicy struct {
+	// Allow holds regular expressions of values that are not secrets, such
+	// as test fixtures or documented example keys
+	Allow []string `yaml:"allow"`
+	// Exclude are glob patterns of files that are not scanned
+	Exclude []string `yaml:"exclude"`
+	// MinEntropy overrides the entropy threshold of random-looking strings
+	MinEntropy float64 `yaml:"min_entropy"`
+
+	allow []*regexp.Regexp
+}
+
+// secretPolicyFile is a YAML policy file holding a secrets policy
+type secretPolicyFile struct {
+	Secrets *SecretPolicy `yaml:"secrets"`
+}
+
+// secretFormat is a known credential format. The first group of the pattern
+// is the secret value.
+type secretFormat struct {
+	Kind    string
+	Pattern *regexp.Regexp
+	// Header formats match a marker such as a PEM header that is not secret
+	// itself and is reported as is
+	Header bool
+}
+
+// secretFormats are the known credential formats
+var secretFormats = []secretFormat{
+	{Kind: "AWS access key", Pattern: regexp.MustCompile(`\b((?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16})\b`)},
+	{Kind: "AWS secret access key", Pattern: regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[=:]\s*["']?([A-Za-z0-9/+]{40})\b`)},
+	{Kind: "GitHub token", Pattern: regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,251})\b`)},
+	{Kind: "GitHub token", Pattern: regexp.MustCompile(`\b(github_pat_[A-Za-z0-9_]{22,255})\b`)},
+	{Kind: "Private key", Pattern: regexp.MustCompile(`(-----BEGIN (?:RSA |EC |DSA |OPENSSH |ENCRYPTED |PGP )?PRIVATE KEY(?: BLOCK)?-----)`), Header: true},
+	{Kind: "Azure connection string", Pattern: regexp.MustCompile(`(?i)(?:AccountKey|SharedAccessKey)=([A-Za-z0-9+/]{20,}={0,2})`)},
+	{Kind: "Google API key", Pattern: regexp.MustCompile(`\b(AIza[0-9A-Za-z_\-]{35})\b`)},
+	{Kind: "Slack token", Pattern: regexp.MustCompile(`\b(xox[baprs]-[0-9A-Za-z-]{10,})\b`)},
+}
+
+var (
+	// secretNamePattern matches argument, object key and variable names that
+	// hold secrets, e.g. password or DB_PASSWORD, but not key_name
+	secretNamePattern = regexp.MustCompile(`(?i)(^|[_.-])(password|passwd|passphrase|secret|token|credentials?|api_?key|access_?key|secret_?key|private_?key|account_?key|auth_?key|connection_?string)s?($|[_.-])`)
+	// nonSecretNamePattern matches names that refer to a secret rather than
+	// hold it, e.g. secret_arn or password_length
+	nonSecretNamePattern = regexp.MustCompile(`(?i)[_.-](arn|id|ids|name|names|path|version|length|type|ttl|policy|enabled|rotation|count|file|url|uri)$`)
+	// placeholderPattern matches values that are placeholders for secrets
+	placeholderPattern = regexp.MustCompile(`(?i)^(changeme|change_me|change-me|replace_?me|todo|tbd|none|null|dummy|placeholder|redacted|x{3,}|\*+|<[^>]*>)$`)
+	// secretReferencePattern matches values that identify a secret in a
+	// secrets manager or KMS rather than hold it
+	secretReferencePattern = regexp.MustCompile(`^(arn:|alias/|projects/|https?://)`)
+	// publicKeyPattern matches public keys and certificates, which are long
+	// random-looking strings that are not secret
+	publicKeyPattern = regexp.MustCompile(`^(ssh-(rsa|ed25519|dss)|ecdsa-sha2-)|-----BEGIN (CERTIFICATE|PUBLIC KEY)`)
+	// entropyTokenPattern splits strings into base64-like tokens
+	entropyTokenPattern = regexp.MustCompile(`[A-Za-z0-9+/=_-]+`)
+)
+
+// defaultSecretAllowList holds values that are never reported, such as the
+// example keys of the AWS documentation
+var defaultSecretAllowList = []*regexp.Regexp{
+	regexp.MustCompile(`EXAMPLE`),
+}
+
+// LoadSecretPolicy loads the secrets sections of all *.yaml and *.yml files
+// of a directory. Allow lists and exclusions of several files are combined.
+func LoadSecretPolicy(dir string) (*SecretPolicy, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read policy directory: %w", err)
+	}
+
+	var policy *SecretPolicy
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read policy file %s: %w", entry.Name(), err)
+		}
+
+		filePolicy, err := ParseSecretPolicy(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse policy file %s: %w", entry.Name(), err)
+		}
+		if filePolicy == nil {
+			continue
+		}
+		if policy == nil {
+			policy = filePolicy
+			continue
+		}
+		policy.Allow = append(policy.Allow, filePolicy.Allow...)
+		policy.allow = append(policy.allow, filePolicy.allow...)
+		policy.Exclude = append(policy.Exclude, filePolicy.Exclude...)
+		if filePolicy.MinEntropy != 0 {
+			policy.MinEntropy = filePolicy.MinEntropy
+		}
+	}
+
+	return policy, nil
+}
+
+// ParseSecretPolicy parses and checks the secrets section of a YAML policy
+// file. It returns nil if the file has no secrets policy.
+func ParseSecretPolicy(data []byte) (*SecretPolicy, error) {
+	var file secretPolicyFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	if file.Secrets == nil {
+		return nil, nil
+	}
+
+	policy := file.Secrets
+	for _, pattern := range policy.Allow {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("secrets: invalid allow pattern %q: %w", pattern, err)
+		}
+		policy.allow = append(policy.allow, re)
+	}
+	if policy.MinEntropy < 0 || policy.MinEntropy > 8 {
+		return nil, fmt.Errorf("secrets: min_entropy must be between 0 and 8 bits per character")
+	}
+	return policy, nil
+}
+
+// SecretFinding is a hardcoded secret found in a configuration file. The
+// secret itself is only available in redacted form.
+type SecretFinding struct {
+	Kind     string  `json:"kind"`
+	Redacted string  `json:"redacted"`
+	File     string  `json:"file"`
+	Line     int     `json:"line"`
+	Entropy  float64 `json:"entropy,omitempty"`
+
+	value string
+}
+
+// SecretScanner finds hardcoded secrets in Terraform and variable files by
+// combining known credential formats, secret-looking names and the Shannon
+// entropy of string literals
+type SecretScanner struct {
+	policy *SecretPolicy
+}
+
+// NewSecretScanner creates a secrets scanner with an optional policy
+func NewSecretScanner(policy *SecretPolicy) *SecretScanner {
+	return &SecretScanner{policy: policy}
+}
+
+// minEntropy returns the entropy threshold of the scanner
+func (s *SecretScanner) minEntropy() float64 {
+	if s.policy != nil && s.policy.MinEntropy > 0 {
+		return s.policy.MinEntropy
+	}
+	return defaultMinEntropy
+}
+
+// entropyThreshold returns the entropy threshold for a token of the given
+// length. A token of n characters has at most log2(n) bits per character,
+// so short tokens are scored against the maximum entropy of their length.
+func (s *SecretScanner) entropyThreshold(length int) float64 {
+	return math.Min(s.minEntropy(), entropyLengthFactor*math.Log2(float64(length)))
+}
+
+// allowed reports whether a value is allow-listed
+func (s *SecretScanner) allowed(value string) bool {
+	for _, re := range defaultSecretAllowList {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	if s.policy != nil {
+		for _, re := range s.policy.allow {
+			if re.MatchString(value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// excluded reports whether a file is excluded from scanning
+func (s *SecretScanner) excluded(name string) bool {
+	if s.policy == nil {
+		return false
+	}
+	for _, pattern := range s.policy.Exclude {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isSecretFile reports whether a file is scanned for secrets
+func isSecretFile(name string) bool {
+	return strings.HasSuffix(name, ".tf") || strings.HasSuffix(name, ".tfvars")
+}
+
+// Scan returns the secrets of the .tf and .tfvars files of a configuration,
+// sorted by file and line
+func (s *SecretScanner) Scan(config *TerraformConfiguration) []SecretFinding {
+	names := make([]string, 0, len(config.Files))
+	for name := range config.Files {
+		if isSecretFile(name) && !s.excluded(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var findings []SecretFinding
+	for _, name := range names {
+		parsed := config.ParsedFile(name)
+		if parsed == nil {
+			parsed = parseFile(name, config.Files[name])
+		}
+		findings = append(findings, s.scanFile(parsed)...)
+	}
+	return findings
+}
+
+// scanFile scans a single parsed file. Known formats are matched against the
+// raw source, so secrets in heredocs and comments are found as well.
+func (s *SecretScanner) scanFile(file *ParsedFile) []SecretFinding {
+	// Each secret is reported once per file, by the most specific check
+	var findings []SecretFinding
+	seen := make(map[string]bool)
+	lines := make(map[int]bool)
+	add := func(finding SecretFinding) {
+		if seen[finding.value] || s.allowed(finding.value) {
+			return
+		}
+		seen[finding.value], lines[finding.Line] = true, true
+		findings = append(findings, finding)
+	}
+
+	source := string(file.Source)
+	for _, format := range secretFormats {
+		for _, match := range format.Pattern.FindAllStringSubmatchIndex(source, -1) {
+			value := source[match[2]:match[3]]
+			redacted := RedactSecret(value)
+			if format.Header {
+				redacted = value
+			}
+			add(SecretFinding{
+				Kind:     format.Kind,
+				Redacted: redacted,
+				File:     file.Name,
+				Line:     strings.Count(source[:match[2]], "\n") + 1,
+				value:    value,
+			})
+		}
+	}
+
+	for _, literal := range fileStringLiterals(file) {
+		if finding, ok := s.scoreLiteral(literal); ok && !lines[finding.Line] {
+			finding.File = file.Name
+			add(finding)
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Line < findings[j].Line
+	})
+	return findings
+}
+
+// scoreLiteral decides whether a string literal is a secret, either because
+// it is assigned to a secret-looking name or because of its entropy
+func (s *SecretScanner) scoreLiteral(literal stringLiteral) (SecretFinding, bool) {
+	value := strings.TrimSpace(literal.Value)
+	if value == "" || placeholderPattern.MatchString(value) || secretReferencePattern.MatchString(value) || publicKeyPattern.MatchString(value) {
+		return SecretFinding{}, false
+	}
+
+	if literal.Whole && isSecretName(literal.Name) {
+		return SecretFinding{
+			Kind:     fmt.Sprintf("Value of '%s'", literal.Name),
+			Redacted: RedactSecret(value),
+			Line:     literal.Line,
+			value:    value,
+		}, true
+	}
+
+	for _, token := range entropyTokenPattern.FindAllString(value, -1) {
+		if len(token) < minEntropyLength || !mixedCharacterClasses(token) || classTransitions(token) < minClassTransitions {
+			continue
+		}
+		if entropy := ShannonEntropy(token); entropy >= s.entropyThreshold(len(token)) {
+			return SecretFinding{
+				Kind:     "High-entropy string",
+				Redacted: RedactSecret(token),
+				Line:     literal.Line,
+				Entropy:  math.Round(entropy*100) / 100,
+				value:    token,
+			}, true
+		}
+	}
+	return SecretFinding{}, false
+}
+
+// isSecretName reports whether an argument, key or variable name holds a secret
+func isSecretName(name string) bool {
+	return secretNamePattern.MatchString(name) && !nonSecretNamePattern.MatchString(name)
+}
+
+// mixedCharacterClasses reports whether a token mixes upper case letters,
+// lower case letters and digits like generated keys do. Hex strings such as
+// commit SHAs and words joined by separators do not.
+func mixedCharacterClasses(token string) bool {
+	var upper, lower, digit bool
+	for _, r := range token {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= '0' && r <= '9':
+			digit = true
+		}
+	}
+	return upper && lower && digit
+}
+
+// classTransitions returns the share of adjacent characters of a token that
+// switch between upper case, lower case, digits and other characters
+func classTransitions(token string) float64 {
+	class := func(c byte) int {
+		switch {
+		case c >= 'A' && c <= 'Z':
+			return 0
+		case c >= 'a' && c <= 'z':
+			return 1
+		case c >= '0' && c <= '9':
+			return 2
+		}
+		return 3
+	}
+	if len(token) < 2 {
+		return 0
+	}
+	transitions := 0
+	for i := 1; i < len(token); i++ {
+		if class(token[i]) != class(token[i-1]) {
+			transitions++
+		}
+	}
+	return float64(transitions) / float64(len(token)-1)
+}
+
+// ShannonEntropy returns the Shannon entropy of a string in bits per character
+func ShannonEntropy(value string) float64 {
+	if value == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range value {
+		counts[r]++
+		total++
+	}
+	var entropy float64
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// RedactSecret hides a secret, keeping the first four characters of long
+// values so that findings can still be told apart
+func RedactSecret(value string) string {
+	if len(value) < 16 {
+		return "****"
+	}
+	return value[:4] + "****"
+}
+
+// stringLiteral is a string literal of a configuration file together with
+// the name it is assigned to
+type stringLiteral struct {
+	Name  string
+	Value string
+	Line  int
+	// Whole is false for literal parts of templates with interpolations
+	Whole bool
+}
+
+// fileStringLiterals returns the string literals of a file. Object keys
+// name the values they hold, and variable defaults are named after the
+// variable.
+func fileStringLiterals(file *ParsedFile) []stringLiteral {
+	var literals []stringLiteral
+	for _, attr := range sortedAttributes(file.Attributes) {
+		literals = append(literals, expressionStringLiterals(attr.Name, attr.Expr)...)
+	}
+	var walk func(block *Block)
+	walk = func(block *Block) {
+		for _, attr := range sortedAttributes(block.Attributes) {
+			name := attr.Name
+			if block.Type == "variable" && name == "default" {
+				name = block.Label(0)
+			}
+			literals = append(literals, expressionStringLiterals(name, attr.Expr)...)
+		}
+		for _, nested := range block.Blocks {
+			walk(nested)
+		}
+	}
+	for _, block := range file.Blocks {
+		walk(block)
+	}
+	return literals
+}
+
+// expressionStringLiterals returns the string literals of an expression
+func expressionStringLiterals(name string, expr hclsyntax.Expression) []stringLiteral {
+	var literals []stringLiteral
+	switch e := expr.(type) {
+	case *hclsyntax.LiteralValueExpr:
+		if e.Val.Type() == cty.String && !e.Val.IsNull() {
+			literals = append(literals, stringLiteral{Name: name, Value: e.Val.AsString(), Line: e.SrcRange.Start.Line, Whole: true})
+		}
+	case *hclsyntax.TemplateExpr:
+		whole := e.IsStringLiteral()
+		for _, part := range e.Parts {
+			if literal, ok := part.(*hclsyntax.LiteralValueExpr); ok && literal.Val.Type() == cty.String {
+				literals = append(literals, stringLiteral{Name: name, Value: literal.Val.AsString(), Line: literal.SrcRange.Start.Line, Whole: whole})
+			}
+		}
+	case *hclsyntax.ObjectConsExpr:
+		for _, item := range e.Items {
+			key := hcl.ExprAsKeyword(item.KeyExpr)
+			if key == "" {
+				if value, diags := item.KeyExpr.Value(nil); !diags.HasErrors() && value.Type() == cty.String {
+					key = value.AsString()
+				}
+			}
+			literals = append(literals, expressionStringLiterals(key, item.ValueExpr)...)
+		}
+	case *hclsyntax.TupleConsExpr:
+		for _, element := range e.Exprs {
+			literals = append(literals, expressionStringLiterals(name, element)...)
+		}
+	case *hclsyntax.FunctionCallExpr:
+		for _, arg := range e.Args {
+			literals = append(literals, expressionStringLiterals(name, arg)...)
+		}
+	case *hclsyntax.ConditionalExpr:
+		literals = append(literals, expressionStringLiterals(name, e.TrueResult)...)
+		literals = append(literals, expressionStringLiterals(name, e.FalseResult)...)
+	case *hclsyntax.ParenthesesExpr:
+		literals = append(literals, expressionStringLiterals(name, e.Expression)...)
+	}
+	return literals
+}
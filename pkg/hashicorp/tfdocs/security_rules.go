@@ -0,0 +1,1111 @@
+// pkg/hashicorp/tfdocs/security_rules.go
+package tfdocs
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// securityRule is a built-in security check of the parsed configuration.
+// References name the CWE weakness and the benchmark controls the rule maps to.
+type securityRule struct {
+	RuleMetadata
+	Suggestion string
+	check      func(config *TerraformConfiguration) []securityFinding
+}
+
+// securityFinding is a single violation of a security rule
+type securityFinding struct {
+	Message string
+	File    string
+	Line    int
+}
+
+// securityRules is the built-in security rule pack
+var securityRules = []securityRule{
+	{
+		RuleMetadata: RuleMetadata{
+			ID: "s3-public-access", Name: "S3PublicAccess", Severity: SeverityError, Category: CategorySecurity,
+			Description: "Do not grant public access to S3 buckets",
+			References:  []string{"CWE-284", "CIS AWS Foundations 2.1.5"},
+			Tags:        []string{"security", "aws"},
+		},
+		Suggestion: "Use a private ACL and enable all four settings of aws_s3_bucket_public_access_block",
+		check:      checkS3PublicAccess,
+	},
+	{
+		RuleMetadata: RuleMetadata{
+			ID: "s3-versioning", Name: "S3Versioning", Severity: SeverityWarning, Category: CategorySecurity,
+			Description: "Enable versioning on S3 buckets",
+			References:  []string{"AWS FSBP S3.14"},
+			Tags:        []string{"security", "aws"},
+		},
+		Suggestion: "Add an aws_s3_bucket_versioning resource with status = \"Enabled\"",
+		check:      checkS3Versioning,
+	},
+	{
+		RuleMetadata: RuleMetadata{
+			ID: "s3-access-logging", Name: "S3AccessLogging", Severity: SeverityWarning, Category: CategorySecurity,
+			Description: "Enable server access logging on S3 buckets",
+			References:  []string{"CWE-778", "AWS FSBP S3.9"},
+			Tags:        []string{"security", "aws"},
+		},
+		Suggestion: "Add an aws_s3_bucket_logging resource that delivers access logs to a log bucket",
+		check:      checkS3AccessLogging,
+	},
+	{
+		RuleMetadata: RuleMetadata{
+			ID: "ebs-encryption", Name: "EBSEncryption", Severity: SeverityError, Category: CategorySecurity,
+			Description: "Encrypt EBS volumes at rest",
+			References:  []string{"CWE-311", "CIS AWS Foundations 2.2.1"},
+			Tags:        []string{"security", "aws"},
+		},
+		Suggestion: "Set encrypted = true or enable aws_ebs_encryption_by_default",
+		check:      checkEBSEncryption,
+	},
+	{
+		RuleMetadata: RuleMetadata{
+			ID: "rds-encryption", Name: "RDSEncryption", Severity: SeverityError, Category: CategorySecurity,
+			Description: "Encrypt RDS storage at rest",
+			References:  []string{"CWE-311", "CIS AWS Foundations 2.3.1"},
+			Tags:        []string{"security", "aws"},
+		},
+		Suggestion: "Set storage_encrypted = true",
+		check:      checkRDSEncryption,
+	},
+	{
+		RuleMetadata: RuleMetadata{
+			ID: "database-public-access", Name: "DatabasePublicAccess", Severity: SeverityError, Category: CategorySecurity,
+			Description: "Do not expose databases to the internet",
+			References:  []string{"CWE-284", "AWS FSBP RDS.2", "CIS GCP Foundations 6.5"},
+			Tags:        []string{"security", "aws", "azure", "gcp"},
+		},
+		Suggestion: "Keep databases in private subnets and only allow access from known networks",
+		check:      checkDatabasePublicAccess,
+	},
+	{
+		RuleMetadata: RuleMetadata{
+			ID: "storage-account-secure-transfer", Name: "StorageAccountSecureTransfer", Severity: SeverityError, Category: CategorySecurity,
+			Description: "Require encrypted connections to storage accounts",
+			References:  []string{"CWE-319", "CIS Azure Foundations 3.1"},
+			Tags:        []string{"security", "azure"},
+		},
+		Suggestion: "Require HTTPS traffic only and set min_tls_version = \"TLS1_2\"",
+		check:      checkStorageAccountSecureTransfer,
+	},
+	{
+		RuleMetadata: RuleMetadata{
+			ID: "storage-account-public-access", Name: "StorageAccountPublicAccess", Severity: SeverityError, Category: CategorySecurity,
+			Description: "Do not allow anonymous access to storage account blobs",
+			References:  []string{"CWE-284"},
+			Tags:        []string{"security", "azure"},
+		},
+		Suggestion: "Set allow_nested_items_to_be_public = false and use private containers",
+		check:      checkStorageAccountPublicAccess,
+	},
+	{
+		RuleMetadata: RuleMetadata{
+			ID: "iam-wildcard-actions", Name: "IAMWildcardActions", Severity: SeverityError, Category: CategorySecurity,
+			Description: "Do not allow all actions ('*') in IAM policies",
+			References:  []string{"CWE-269", "CIS AWS Foundations 1.16"},
+			Tags:        []string{"security", "aws"},
+		},
+		Suggestion: "Grant only the actions the principal needs",
+		check:      checkIAMWildcardActions,
+	},
+	{
+		RuleMetadata: RuleMetadata{
+			ID: "iam-wildcard-resources", Name: "IAMWildcardResources", Severity: SeverityWarning, Category: CategorySecurity,
+			Description: "Do not allow service-wide actions on all resources in IAM policies",
+			References:  []string{"CWE-269"},
+			Tags:        []string{"security", "aws"},
+		},
+		Suggestion: "Scope the statement to specific resource ARNs or actions",
+		check:      checkIAMWildcardResources,
+	},
+	{
+		RuleMetadata: RuleMetadata{
+			ID: "open-ssh-rdp", Name: "OpenSSHRDP", Severity: SeverityError, Category: CategorySecurity,
+			Description: "Do not allow SSH or RDP from the internet",
+			References:  []string{"CWE-284", "CIS AWS Foundations 5.2", "CIS Azure Foundations 6.1", "CIS Azure Foundations 6.2", "CIS GCP Foundations 3.6", "CIS GCP Foundations 3.7"},
+			Tags:        []string{"security", "aws", "azure", "gcp"},
+		},
+		Suggestion: "Restrict administrative ports to known networks or use a bastion or session manager",
+		check:      checkOpenSSHRDP,
+	},
+	{
+		RuleMetadata: RuleMetadata{
+			ID: "open-ingress", Name: "OpenIngress", Severity: SeverityWarning, Category: CategorySecurity,
+			Description: "Restrict security group access to specific IP ranges",
+			References:  []string{"CWE-284"},
+			Tags:        []string{"security", "aws", "azure", "gcp"},
+		},
+		Suggestion: "Replace 0.0.0.0/0 with specific IP ranges or use a variable for allowed IPs",
+		check:      checkOpenIngress,
+	},
+	{
+		RuleMetadata: RuleMetadata{
+			ID: "cloudtrail-log-validation", Name: "CloudTrailLogValidation", Severity: SeverityWarning, Category: CategorySecurity,
+			Description: "Enable log file validation on CloudTrail trails",
+			References:  []string{"CWE-354", "CIS AWS Foundations 3.2"},
+			Tags:        []string{"security", "aws"},
+		},
+		Suggestion: "Set enable_log_file_validation = true",
+		check:      checkCloudTrailLogValidation,
+	},
+}
+
+// securityPackIssues runs the built-in security rule pack
+func securityPackIssues(config *TerraformConfiguration) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, rule := range securityRules {
+		for _, finding := range rule.check(config) {
+			issues = append(issues, rule.issue(finding))
+		}
+	}
+	return issues
+}
+
+// securityRuleByID returns a rule of the built-in security rule pack
+func securityRuleByID(id string) securityRule {
+	for _, rule := range securityRules {
+		if rule.ID == id {
+			return rule
+		}
+	}
+	panic(fmt.Sprintf("unknown security rule %s", id))
+}
+
+// issue builds the validation issue of a finding
+func (r securityRule) issue(finding securityFinding) ValidationIssue {
+	return ValidationIssue{
+		Message:      finding.Message,
+		RuleID:       r.ID,
+		Severity:     r.Severity,
+		Category:     r.Category,
+		File:         finding.File,
+		Line:         finding.Line,
+		BestPractice: r.Description,
+		Suggestion:   r.Suggestion,
+		References:   r.References,
+	}
+}
+
+// securityPackRules returns the metadata of the built-in security rule pack
+func securityPackRules() []RuleMetadata {
+	rules := make([]RuleMetadata, 0, len(securityRules))
+	for _, rule := range securityRules {
+		metadata := rule.RuleMetadata
+		if metadata.HelpURI == "" {
+			metadata.HelpURI = cweHelpURI(metadata.References)
+		}
+		rules = append(rules, metadata)
+	}
+	return rules
+}
+
+// cweHelpURI returns the MITRE page of the first CWE reference
+func cweHelpURI(references []string) string {
+	for _, ref := range references {
+		if id := strings.TrimPrefix(ref, "CWE-"); id != ref {
+			return fmt.Sprintf("https://cwe.mitre.org/data/definitions/%s.html", id)
+		}
+	}
+	return ""
+}
+
+// resourcesOfType returns the resources of the given types
+func resourcesOfType(config *TerraformConfiguration, types ...string) []*Block {
+	var blocks []*Block
+	for _, block := range config.Blocks("resource") {
+		if containsString(types, block.Label(0)) {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+// blockFinding builds a finding located at an attribute of the block, or at
+// the block itself when the attribute is not set
+func blockFinding(block *Block, attrName, message string) securityFinding {
+	if attr, ok := block.Attributes[attrName]; ok {
+		return securityFinding{Message: message, File: attr.File, Line: attr.Line}
+	}
+	return securityFinding{Message: message, File: block.File, Line: block.Line}
+}
+
+// attrBool returns the constant boolean value of an attribute. The second
+// return value is false when the attribute is not set or not a constant.
+func attrBool(block *Block, name string) (bool, bool) {
+	value, ok := attrString(block, name)
+	if !ok || (value != "true" && value != "false") {
+		return false, false
+	}
+	return value == "true", true
+}
+
+// attrString returns the constant primitive value of an attribute as a string
+func attrString(block *Block, name string) (string, bool) {
+	attr, ok := block.Attributes[name]
+	if !ok {
+		return "", false
+	}
+	return attr.StringValue()
+}
+
+// attrStrings returns the constant strings of a list, set or string attribute
+func attrStrings(block *Block, name string) ([]string, bool) {
+	attr, ok := block.Attributes[name]
+	if !ok {
+		return nil, false
+	}
+	value, ok := attr.Value()
+	if !ok {
+		return nil, false
+	}
+	return ctyStrings(value)
+}
+
+// ctyStrings converts a string or a collection of primitives into strings
+func ctyStrings(value cty.Value) ([]string, bool) {
+	if value.IsNull() {
+		return nil, true
+	}
+	if !value.CanIterateElements() {
+		s, ok := ctyPrimitiveString(value)
+		if !ok {
+			return nil, false
+		}
+		return []string{s}, true
+	}
+
+	var values []string
+	for it := value.ElementIterator(); it.Next(); {
+		_, element := it.Element()
+		s, ok := ctyPrimitiveString(element)
+		if !ok {
+			return nil, false
+		}
+		values = append(values, s)
+	}
+	return values, true
+}
+
+// isEnabled reports whether a boolean attribute is true or not a constant.
+// Attributes that are not set are treated as false.
+func isEnabled(block *Block, name string) bool {
+	if _, ok := block.Attributes[name]; !ok {
+		return false
+	}
+	value, known := attrBool(block, name)
+	return value || !known
+}
+
+// referencedBy returns the blocks that reference the address
+func referencedBy(blocks []*Block, address string) []*Block {
+	var referencing []*Block
+	for _, block := range blocks {
+		if blockReferences(block, address) {
+			referencing = append(referencing, block)
+		}
+	}
+	return referencing
+}
+
+var publicS3ACLs = []string{"public-read", "public-read-write", "authenticated-read"}
+
+// checkS3PublicAccess reports public canned ACLs and incomplete public access blocks
+func checkS3PublicAccess(config *TerraformConfiguration) []securityFinding {
+	var findings []securityFinding
+	for _, block := range resourcesOfType(config, "aws_s3_bucket", "aws_s3_bucket_acl") {
+		if acl, ok := attrString(block, "acl"); ok && containsString(publicS3ACLs, acl) {
+			findings = append(findings, blockFinding(block, "acl",
+				fmt.Sprintf("'%s' uses the public canned ACL '%s'", block.Address(), acl)))
+		}
+	}
+
+	settings := []string{"block_public_acls", "block_public_policy", "ignore_public_acls", "restrict_public_buckets"}
+	for _, block := range resourcesOfType(config, "aws_s3_bucket_public_access_block") {
+		var disabled []string
+		for _, setting := range settings {
+			if !isEnabled(block, setting) {
+				disabled = append(disabled, setting)
+			}
+		}
+		if len(disabled) > 0 {
+			findings = append(findings, blockFinding(block, disabled[0],
+				fmt.Sprintf("'%s' does not enable %s", block.Address(), strings.Join(disabled, ", "))))
+		}
+	}
+	return findings
+}
+
+// checkS3Versioning reports buckets without versioning
+func checkS3Versioning(config *TerraformConfiguration) []securityFinding {
+	var findings []securityFinding
+	versioning := resourcesOfType(config, "aws_s3_bucket_versioning")
+	for _, bucket := range resourcesOfType(config, "aws_s3_bucket") {
+		inline := bucket.FindAttributes("versioning.enabled")
+		if len(inline) > 0 {
+			if value, known := inline[0].StringValue(); known && value == "false" {
+				findings = append(findings, securityFinding{
+					Message: fmt.Sprintf("Versioning is disabled on '%s'", bucket.Address()),
+					File:    inline[0].File,
+					Line:    inline[0].Line,
+				})
+			}
+			continue
+		}
+
+		configured := referencedBy(versioning, bucket.Address())
+		if len(configured) == 0 {
+			findings = append(findings, securityFinding{
+				Message: fmt.Sprintf("Versioning is not enabled on '%s'", bucket.Address()),
+				File:    bucket.File,
+				Line:    bucket.Line,
+			})
+			continue
+		}
+		for _, resource := range configured {
+			for _, status := range resource.FindAttributes("versioning_configuration.status") {
+				if value, known := status.StringValue(); known && value != "Enabled" {
+					findings = append(findings, securityFinding{
+						Message: fmt.Sprintf("Versioning of '%s' is set to '%s'", bucket.Address(), value),
+						File:    status.File,
+						Line:    status.Line,
+					})
+				}
+			}
+		}
+	}
+	return findings
+}
+
+// checkS3AccessLogging reports buckets without server access logging. Buckets
+// that receive access logs of other buckets are exempt.
+func checkS3AccessLogging(config *TerraformConfiguration) []securityFinding {
+	var findings []securityFinding
+	logging := resourcesOfType(config, "aws_s3_bucket_logging")
+	buckets := resourcesOfType(config, "aws_s3_bucket")
+
+	targets := make(map[string]bool)
+	for _, block := range append(append([]*Block{}, logging...), buckets...) {
+		var attrs []*Attribute
+		attrs = append(attrs, block.FindAttributes("target_bucket")...)
+		attrs = append(attrs, block.FindAttributes("logging.target_bucket")...)
+		for _, attr := range attrs {
+			for _, ref := range attr.References() {
+				targets[referencedResource(ref)] = true
+			}
+		}
+	}
+
+	for _, bucket := range buckets {
+		if len(bucket.NestedBlocks("logging")) > 0 || targets[bucket.Address()] {
+			continue
+		}
+		if len(referencedBy(logging, bucket.Address())) > 0 {
+			continue
+		}
+		findings = append(findings, securityFinding{
+			Message: fmt.Sprintf("Access logging is not enabled on '%s'", bucket.Address()),
+			File:    bucket.File,
+			Line:    bucket.Line,
+		})
+	}
+	return findings
+}
+
+// referencedResource returns the resource address of a reference such as
+// aws_s3_bucket.logs.id
+func referencedResource(ref string) string {
+	parts := strings.Split(ref, ".")
+	if len(parts) >= 2 {
+		return parts[0] + "." + parts[1]
+	}
+	return ref
+}
+
+// checkEBSEncryption reports unencrypted EBS volumes and instance block devices
+func checkEBSEncryption(config *TerraformConfiguration) []securityFinding {
+	for _, block := range resourcesOfType(config, "aws_ebs_encryption_by_default") {
+		if enabled, known := attrBool(block, "enabled"); !known || enabled {
+			return nil
+		}
+	}
+
+	var findings []securityFinding
+	for _, volume := range resourcesOfType(config, "aws_ebs_volume") {
+		if !isEnabled(volume, "encrypted") {
+			findings = append(findings, blockFinding(volume, "encrypted",
+				fmt.Sprintf("EBS volume '%s' is not encrypted", volume.Address())))
+		}
+	}
+	for _, instance := range resourcesOfType(config, "aws_instance") {
+		for _, device := range append(instance.NestedBlocks("root_block_device"), instance.NestedBlocks("ebs_block_device")...) {
+			if !isEnabled(device, "encrypted") {
+				findings = append(findings, blockFinding(device, "encrypted",
+					fmt.Sprintf("The %s of '%s' is not encrypted", device.Type, instance.Address())))
+			}
+		}
+	}
+	return findings
+}
+
+// checkRDSEncryption reports RDS instances and clusters without storage encryption.
+// Read replicas inherit the encryption of their source.
+func checkRDSEncryption(config *TerraformConfiguration) []securityFinding {
+	var findings []securityFinding
+	for _, block := range resourcesOfType(config, "aws_db_instance", "aws_rds_cluster") {
+		if _, ok := block.Attributes["replicate_source_db"]; ok {
+			continue
+		}
+		if _, ok := block.Attributes["snapshot_identifier"]; ok {
+			continue
+		}
+		if mode, ok := attrString(block, "engine_mode"); ok && mode == "serverless" {
+			continue
+		}
+		if !isEnabled(block, "storage_encrypted") {
+			findings = append(findings, blockFinding(block, "storage_encrypted",
+				fmt.Sprintf("Storage of '%s' is not encrypted", block.Address())))
+		}
+	}
+	return findings
+}
+
+// checkDatabasePublicAccess reports publicly accessible RDS instances, Cloud SQL
+// instances authorized for any network and Azure database firewall rules that
+// allow every address
+func checkDatabasePublicAccess(config *TerraformConfiguration) []securityFinding {
+	var findings []securityFinding
+	for _, block := range resourcesOfType(config, "aws_db_instance", "aws_rds_cluster_instance") {
+		if public, known := attrBool(block, "publicly_accessible"); known && public {
+			findings = append(findings, blockFinding(block, "publicly_accessible",
+				fmt.Sprintf("'%s' is publicly accessible", block.Address())))
+		}
+	}
+
+	for _, block := range resourcesOfType(config, "google_sql_database_instance") {
+		for _, attr := range block.FindAttributes("settings.ip_configuration.authorized_networks.value") {
+			if value, known := attr.StringValue(); known && isWorldCIDR(value) {
+				findings = append(findings, securityFinding{
+					Message: fmt.Sprintf("'%s' authorizes %s", block.Address(), value),
+					File:    attr.File,
+					Line:    attr.Line,
+				})
+			}
+		}
+	}
+
+	for _, block := range config.Blocks("resource") {
+		resType := block.Label(0)
+		if !strings.HasPrefix(resType, "azurerm_") || !strings.HasSuffix(resType, "_firewall_rule") {
+			continue
+		}
+		start, startKnown := attrString(block, "start_ip_address")
+		end, endKnown := attrString(block, "end_ip_address")
+		if startKnown && endKnown && start == "0.0.0.0" && end == "255.255.255.255" {
+			findings = append(findings, blockFinding(block, "start_ip_address",
+				fmt.Sprintf("'%s' allows connections from every IP address", block.Address())))
+		}
+	}
+	return findings
+}
+
+// checkStorageAccountSecureTransfer reports storage accounts that accept
+// plain HTTP or outdated TLS versions
+func checkStorageAccountSecureTransfer(config *TerraformConfiguration) []securityFinding {
+	var findings []securityFinding
+	for _, block := range resourcesOfType(config, "azurerm_storage_account") {
+		for _, name := range []string{"https_traffic_only_enabled", "enable_https_traffic_only"} {
+			if enabled, known := attrBool(block, name); known && !enabled {
+				findings = append(findings, blockFinding(block, name,
+					fmt.Sprintf("Storage account '%s' allows unencrypted HTTP traffic", block.Address())))
+			}
+		}
+		if version, ok := attrString(block, "min_tls_version"); ok && (version == "TLS1_0" || version == "TLS1_1") {
+			findings = append(findings, blockFinding(block, "min_tls_version",
+				fmt.Sprintf("Storage account '%s' accepts %s", block.Address(), version)))
+		}
+	}
+	return findings
+}
+
+// checkStorageAccountPublicAccess reports storage accounts and containers that
+// allow anonymous blob access
+func checkStorageAccountPublicAccess(config *TerraformConfiguration) []securityFinding {
+	var findings []securityFinding
+	for _, block := range resourcesOfType(config, "azurerm_storage_account") {
+		for _, name := range []string{"allow_nested_items_to_be_public", "allow_blob_public_access"} {
+			if public, known := attrBool(block, name); known && public {
+				findings = append(findings, blockFinding(block, name,
+					fmt.Sprintf("Storage account '%s' allows public blob access", block.Address())))
+			}
+		}
+	}
+	for _, block := range resourcesOfType(config, "azurerm_storage_container") {
+		if access, ok := attrString(block, "container_access_type"); ok && access != "private" {
+			findings = append(findings, blockFinding(block, "container_access_type",
+				fmt.Sprintf("Storage container '%s' allows anonymous %s access", block.Address(), access)))
+		}
+	}
+	return findings
+}
+
+// policyStatement is an Allow statement of an IAM policy
+type policyStatement struct {
+	Owner     string
+	Actions   []string
+	Resources []string
+	File      string
+	Line      int
+}
+
+// allowStatements returns the Allow statements of the IAM policies of the
+// configuration, from policy JSON and aws_iam_policy_document data sources
+func allowStatements(config *TerraformConfiguration) []policyStatement {
+	var statements []policyStatement
+
+	var policies []*Attribute
+	owners := make(map[*Attribute]string)
+	for _, block := range resourcesOfType(config, "aws_iam_policy", "aws_iam_role_policy", "aws_iam_user_policy", "aws_iam_group_policy", "aws_iam_role") {
+		for _, attr := range append(block.FindAttributes("policy"), block.FindAttributes("inline_policy.policy")...) {
+			policies = append(policies, attr)
+			owners[attr] = block.Address()
+		}
+	}
+	for _, attr := range policies {
+		document, ok := policyDocument(attr)
+		if !ok {
+			continue
+		}
+		for _, statement := range documentStatements(document) {
+			if effect, _ := statement["Effect"].(string); !strings.EqualFold(effect, "Allow") {
+				continue
+			}
+			statements = append(statements, policyStatement{
+				Owner:     owners[attr],
+				Actions:   jsonStrings(statement["Action"]),
+				Resources: jsonStrings(statement["Resource"]),
+				File:      attr.File,
+				Line:      attr.Line,
+			})
+		}
+	}
+
+	for _, block := range config.Blocks("data") {
+		if block.Label(0) != "aws_iam_policy_document" {
+			continue
+		}
+		for _, statement := range block.NestedBlocks("statement") {
+			if effect, ok := attrString(statement, "effect"); ok && effect != "Allow" {
+				continue
+			}
+			actions, _ := attrStrings(statement, "actions")
+			resources, _ := attrStrings(statement, "resources")
+			statements = append(statements, policyStatement{
+				Owner:     block.Address(),
+				Actions:   actions,
+				Resources: resources,
+				File:      statement.File,
+				Line:      statement.Line,
+			})
+		}
+	}
+	return statements
+}
+
+// policyDocument decodes a constant policy attribute, either a JSON string or
+// a jsonencode call
+func policyDocument(attr *Attribute) (map[string]interface{}, bool) {
+	var data []byte
+	if call, ok := attr.Expr.(*hclsyntax.FunctionCallExpr); ok {
+		if call.Name != "jsonencode" || len(call.Args) != 1 || len(call.Args[0].Variables()) > 0 {
+			return nil, false
+		}
+		value, diags := call.Args[0].Value(nil)
+		if diags.HasErrors() || !value.IsWhollyKnown() {
+			return nil, false
+		}
+		encoded, err := ctyjson.Marshal(value, value.Type())
+		if err != nil {
+			return nil, false
+		}
+		data = encoded
+	} else {
+		value, ok := attr.StringValue()
+		if !ok {
+			return nil, false
+		}
+		data = []byte(value)
+	}
+
+	var document map[string]interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, false
+	}
+	return document, true
+}
+
+// documentStatements returns the statements of a policy document, which may
+// be a single object or a list
+func documentStatements(document map[string]interface{}) []map[string]interface{} {
+	var statements []map[string]interface{}
+	switch statement := document["Statement"].(type) {
+	case map[string]interface{}:
+		statements = append(statements, statement)
+	case []interface{}:
+		for _, item := range statement {
+			if s, ok := item.(map[string]interface{}); ok {
+				statements = append(statements, s)
+			}
+		}
+	}
+	return statements
+}
+
+// jsonStrings returns a JSON string or list of strings as a slice
+func jsonStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// checkIAMWildcardActions reports Allow statements that grant every action
+func checkIAMWildcardActions(config *TerraformConfiguration) []securityFinding {
+	var findings []securityFinding
+	for _, statement := range allowStatements(config) {
+		if containsString(statement.Actions, "*") || containsString(statement.Actions, "*:*") {
+			findings = append(findings, securityFinding{
+				Message: fmt.Sprintf("Policy '%s' allows all actions ('*')", statement.Owner),
+				File:    statement.File,
+				Line:    statement.Line,
+			})
+		}
+	}
+	return findings
+}
+
+// checkIAMWildcardResources reports Allow statements that grant all actions of
+// a service on every resource
+func checkIAMWildcardResources(config *TerraformConfiguration) []securityFinding {
+	var findings []securityFinding
+	for _, statement := range allowStatements(config) {
+		if !containsString(statement.Resources, "*") {
+			continue
+		}
+		if containsString(statement.Actions, "*") || containsString(statement.Actions, "*:*") {
+			continue
+		}
+		for _, action := range statement.Actions {
+			if strings.HasSuffix(action, ":*") {
+				findings = append(findings, securityFinding{
+					Message: fmt.Sprintf("Policy '%s' allows '%s' on all resources", statement.Owner, action),
+					File:    statement.File,
+					Line:    statement.Line,
+				})
+				break
+			}
+		}
+	}
+	return findings
+}
+
+// ingressRule is an inbound firewall rule that is open to the internet
+type ingressRule struct {
+	Owner  string
+	Source string
+	// Ports are inclusive port ranges; nil means the ports are not known
+	Ports [][2]int
+	File  string
+	Line  int
+}
+
+// allPorts is the port range of rules that apply to every port
+var allPorts = [][2]int{{0, 65535}}
+
+// adminPorts are the remote administration ports checked by open-ssh-rdp
+var adminPorts = []struct {
+	Port int
+	Name string
+}{
+	{22, "SSH"},
+	{3389, "RDP"},
+}
+
+// isWorldCIDR reports whether a source address allows the whole internet
+func isWorldCIDR(source string) bool {
+	switch strings.ToLower(source) {
+	case "0.0.0.0/0", "::/0", "*", "internet", "any":
+		return true
+	}
+	return false
+}
+
+// worldSource returns the first source that allows the whole internet
+func worldSource(sources []string) string {
+	for _, source := range sources {
+		if isWorldCIDR(source) {
+			return source
+		}
+	}
+	return ""
+}
+
+// resourceValues gives uniform access to the arguments of a resource block
+// and to the values of a resource instance in a plan
+type resourceValues interface {
+	String(name string) (string, bool)
+	Strings(name string) ([]string, bool)
+	Has(name string) bool
+	Nested(name string) []resourceValues
+	Location() (string, int)
+}
+
+// blockValues reads the constant arguments of a block
+type blockValues struct {
+	block *Block
+}
+
+// String returns the constant primitive value of an argument
+func (v blockValues) String(name string) (string, bool) {
+	return attrString(v.block, name)
+}
+
+// Strings returns the constant strings of a list or string argument
+func (v blockValues) Strings(name string) ([]string, bool) {
+	return attrStrings(v.block, name)
+}
+
+// Location returns the file and line of the block
+func (v blockValues) Location() (string, int) {
+	return v.block.File, v.block.Line
+}
+
+// Has reports whether an argument is set
+func (v blockValues) Has(name string) bool {
+	_, ok := v.block.Attributes[name]
+	return ok
+}
+
+// Nested returns the nested blocks of a type
+func (v blockValues) Nested(name string) []resourceValues {
+	var nested []resourceValues
+	for _, block := range v.block.NestedBlocks(name) {
+		nested = append(nested, blockValues{block: block})
+	}
+	return nested
+}
+
+// mapValues reads the decoded JSON values of a resource instance
+type mapValues struct {
+	values map[string]interface{}
+}
+
+// String returns a primitive value
+func (v mapValues) String(name string) (string, bool) {
+	return jsonPrimitiveString(v.values[name])
+}
+
+// Strings returns the strings of a list or primitive value
+func (v mapValues) Strings(name string) ([]string, bool) {
+	switch value := v.values[name].(type) {
+	case nil:
+		return nil, true
+	case []interface{}:
+		var values []string
+		for _, item := range value {
+			s, ok := jsonPrimitiveString(item)
+			if !ok {
+				return nil, false
+			}
+			values = append(values, s)
+		}
+		return values, true
+	}
+	s, ok := jsonPrimitiveString(v.values[name])
+	if !ok {
+		return nil, false
+	}
+	return []string{s}, true
+}
+
+// Has reports whether a value is set and not null
+func (v mapValues) Has(name string) bool {
+	value, ok := v.values[name]
+	return ok && value != nil
+}
+
+// Nested returns the objects of a list value, such as the ingress rules of
+// a security group
+func (v mapValues) Nested(name string) []resourceValues {
+	var nested []resourceValues
+	items, _ := v.values[name].([]interface{})
+	for _, item := range items {
+		if values, ok := item.(map[string]interface{}); ok {
+			nested = append(nested, mapValues{values: values})
+		}
+	}
+	return nested
+}
+
+// Location returns no location, as plan values are not tied to a file
+func (v mapValues) Location() (string, int) {
+	return "", 0
+}
+
+// jsonPrimitiveString renders a decoded JSON string, number or bool
+func jsonPrimitiveString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case bool:
+		return strconv.FormatBool(v), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case json.Number:
+		return v.String(), true
+	}
+	return "", false
+}
+
+// openIngressRules returns the inbound rules of AWS security groups, Azure
+// network security groups and GCP firewalls that are open to the internet
+func openIngressRules(config *TerraformConfiguration) []ingressRule {
+	var rules []ingressRule
+	for _, block := range config.Blocks("resource") {
+		rules = append(rules, resourceIngressRules(block.Label(0), block.Address(), blockValues{block: block})...)
+	}
+	return rules
+}
+
+// resourceIngressRules returns the inbound rules of a resource that are open
+// to the internet
+func resourceIngressRules(resType, address string, values resourceValues) []ingressRule {
+	var rules []ingressRule
+	add := func(source string, ports [][2]int, location resourceValues) {
+		file, line := location.Location()
+		rules = append(rules, ingressRule{Owner: address, Source: source, Ports: ports, File: file, Line: line})
+	}
+
+	switch resType {
+	case "aws_security_group":
+		for _, ingress := range values.Nested("ingress") {
+			if source := awsIngressSource(ingress, "cidr_blocks", "ipv6_cidr_blocks"); source != "" {
+				add(source, awsIngressPorts(ingress, "protocol"), ingress)
+			}
+		}
+	case "aws_security_group_rule":
+		if kind, ok := values.String("type"); !ok || kind != "ingress" {
+			break
+		}
+		if source := awsIngressSource(values, "cidr_blocks", "ipv6_cidr_blocks"); source != "" {
+			add(source, awsIngressPorts(values, "protocol"), values)
+		}
+	case "aws_vpc_security_group_ingress_rule":
+		if source := awsIngressSource(values, "cidr_ipv4", "cidr_ipv6"); source != "" {
+			add(source, awsIngressPorts(values, "ip_protocol"), values)
+		}
+	case "azurerm_network_security_rule":
+		if source := azureIngressSource(values); source != "" {
+			add(source, azureIngressPorts(values), values)
+		}
+	case "azurerm_network_security_group":
+		for _, rule := range values.Nested("security_rule") {
+			if source := azureIngressSource(rule); source != "" {
+				add(source, azureIngressPorts(rule), rule)
+			}
+		}
+	case "google_compute_firewall":
+		if direction, ok := values.String("direction"); ok && direction != "" && direction != "INGRESS" {
+			break
+		}
+		sources, _ := values.Strings("source_ranges")
+		source := worldSource(sources)
+		if source == "" {
+			break
+		}
+		for _, allow := range values.Nested("allow") {
+			add(source, gcpAllowPorts(allow), allow)
+		}
+	}
+	return rules
+}
+
+// awsIngressSource returns the world source of an AWS ingress rule
+func awsIngressSource(values resourceValues, ipv4, ipv6 string) string {
+	for _, name := range []string{ipv4, ipv6} {
+		if sources, ok := values.Strings(name); ok {
+			if source := worldSource(sources); source != "" {
+				return source
+			}
+		}
+	}
+	return ""
+}
+
+// awsIngressPorts returns the port range of an AWS ingress rule
+func awsIngressPorts(values resourceValues, protocolAttr string) [][2]int {
+	if protocol, ok := values.String(protocolAttr); ok && (protocol == "-1" || protocol == "all") {
+		return allPorts
+	}
+	from, fromOK := values.String("from_port")
+	to, toOK := values.String("to_port")
+	if !fromOK || !toOK {
+		return nil
+	}
+	return parsePortRanges([]string{from + "-" + to})
+}
+
+// azureIngressSource returns the world source of an inbound Allow rule of an
+// Azure network security group
+func azureIngressSource(values resourceValues) string {
+	direction, directionOK := values.String("direction")
+	access, accessOK := values.String("access")
+	if !directionOK || !accessOK || !strings.EqualFold(direction, "Inbound") || !strings.EqualFold(access, "Allow") {
+		return ""
+	}
+	var sources []string
+	for _, name := range []string{"source_address_prefix", "source_address_prefixes"} {
+		prefixes, _ := values.Strings(name)
+		sources = append(sources, prefixes...)
+	}
+	return worldSource(sources)
+}
+
+// azureIngressPorts returns the destination ports of an Azure security rule
+func azureIngressPorts(values resourceValues) [][2]int {
+	var ports []string
+	for _, name := range []string{"destination_port_range", "destination_port_ranges"} {
+		if !values.Has(name) {
+			continue
+		}
+		ranges, ok := values.Strings(name)
+		if !ok {
+			return nil
+		}
+		ports = append(ports, ranges...)
+	}
+	return parsePortRanges(ports)
+}
+
+// gcpAllowPorts returns the TCP ports of an allow block of a GCP firewall
+func gcpAllowPorts(values resourceValues) [][2]int {
+	if protocol, ok := values.String("protocol"); ok && protocol != "tcp" && protocol != "all" {
+		return [][2]int{}
+	}
+	if !values.Has("ports") {
+		return allPorts
+	}
+	ports, ok := values.Strings("ports")
+	if !ok {
+		return nil
+	}
+	if len(ports) == 0 {
+		return allPorts
+	}
+	return parsePortRanges(ports)
+}
+
+// parsePortRanges parses ports such as "22", "1000-2000" and "*". Nil is
+// returned when any port cannot be parsed.
+func parsePortRanges(ports []string) [][2]int {
+	var ranges [][2]int
+	for _, port := range ports {
+		if port == "*" {
+			return allPorts
+		}
+		from, to := port, port
+		if i := strings.Index(port, "-"); i > 0 {
+			from, to = port[:i], port[i+1:]
+		}
+		low, err := strconv.Atoi(strings.TrimSpace(from))
+		if err != nil {
+			return nil
+		}
+		high, err := strconv.Atoi(strings.TrimSpace(to))
+		if err != nil {
+			return nil
+		}
+		// AWS uses 0 to 0 together with the -1 protocol for all ports
+		if low == 0 && high == 0 {
+			return allPorts
+		}
+		ranges = append(ranges, [2]int{low, high})
+	}
+	return ranges
+}
+
+// exposedAdminPorts returns the names of the administration ports a rule opens
+func exposedAdminPorts(rule ingressRule) []string {
+	var names []string
+	for _, admin := range adminPorts {
+		for _, r := range rule.Ports {
+			if r[0] <= admin.Port && admin.Port <= r[1] {
+				names = append(names, fmt.Sprintf("%s (port %d)", admin.Name, admin.Port))
+				break
+			}
+		}
+	}
+	return names
+}
+
+// checkOpenSSHRDP reports rules that open SSH or RDP to the internet
+func checkOpenSSHRDP(config *TerraformConfiguration) []securityFinding {
+	var findings []securityFinding
+	for _, rule := range openIngressRules(config) {
+		if names := exposedAdminPorts(rule); len(names) > 0 {
+			findings = append(findings, securityFinding{
+				Message: fmt.Sprintf("'%s' allows %s from %s", rule.Owner, strings.Join(names, " and "), rule.Source),
+				File:    rule.File,
+				Line:    rule.Line,
+			})
+		}
+	}
+	return findings
+}
+
+// checkOpenIngress reports other rules that are open to the internet
+func checkOpenIngress(config *TerraformConfiguration) []securityFinding {
+	var findings []securityFinding
+	for _, rule := range openIngressRules(config) {
+		if len(exposedAdminPorts(rule)) > 0 {
+			continue
+		}
+		findings = append(findings, securityFinding{
+			Message: fmt.Sprintf("'%s' allows access from %s (any IP)", rule.Owner, rule.Source),
+			File:    rule.File,
+			Line:    rule.Line,
+		})
+	}
+	return findings
+}
+
+// checkCloudTrailLogValidation reports trails without log file validation
+func checkCloudTrailLogValidation(config *TerraformConfiguration) []securityFinding {
+	var findings []securityFinding
+	for _, block := range resourcesOfType(config, "aws_cloudtrail") {
+		if !isEnabled(block, "enable_log_file_validation") {
+			findings = append(findings, blockFinding(block, "enable_log_file_validation",
+				fmt.Sprintf("Log file validation is not enabled on '%s'", block.Address())))
+		}
+	}
+	return findings
+}
@@ -0,0 +1,588 @@
+// pkg/hashicorp/tfdocs/state.go
+package tfdocs
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// State is a Terraform state file in format version 4, as written by
+// Terraform 0.12 and later
+type State struct {
+	Version          int                    `json:"version"`
+	TerraformVersion string                 `json:"terraform_version"`
+	Serial           int                    `json:"serial"`
+	Lineage          string                 `json:"lineage"`
+	Outputs          map[string]StateOutput `json:"outputs"`
+	Resources        []StateResource        `json:"resources"`
+}
+
+// StateOutput is a root module output recorded in the state
+type StateOutput struct {
+	Value     interface{} `json:"value"`
+	Sensitive bool        `json:"sensitive,omitempty"`
+}
+
+// StateResource is a resource of the state with all of its instances
+type StateResource struct {
+	Module    string          `json:"module,omitempty"`
+	Mode      string          `json:"mode"`
+	Type      string          `json:"type"`
+	Name      string          `json:"name"`
+	Provider  string          `json:"provider"`
+	Instances []StateInstance `json:"instances"`
+}
+
+// StateInstance is a resource instance of the state
+type StateInstance struct {
+	IndexKey   interface{}            `json:"index_key,omitempty"`
+	Status     string                 `json:"status,omitempty"`
+	Attributes map[string]interface{} `json:"attributes"`
+	// SensitiveAttributes holds the paths of the attributes marked sensitive,
+	// each a list of steps
+	SensitiveAttributes []json.RawMessage `json:"sensitive_attributes,omitempty"`
+}
+
+// StateSummary holds statistics of a state
+type StateSummary struct {
+	TerraformVersion string         `json:"terraform_version"`
+	Serial           int            `json:"serial"`
+	Resources        int            `json:"resources"`
+	DataSources      int            `json:"data_sources"`
+	Outputs          int            `json:"outputs"`
+	Modules          []string       `json:"modules"`
+	Providers        map[string]int `json:"providers"`
+}
+
+// ParseState parses a Terraform state file. Only format version 4 is
+// supported.
+func ParseState(data []byte) (*State, error) {
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse state: %w", err)
+	}
+	if state.Version != 4 {
+		return nil, fmt.Errorf("failed to parse state: unsupported state version %d, expected 4", state.Version)
+	}
+	return &state, nil
+}
+
+// Address returns the address of a resource instance, e.g.
+// module.app.aws_instance.web[0]
+func (r StateResource) Address(instance StateInstance) string {
+	address := r.Type + "." + r.Name
+	if r.Mode == "data" {
+		address = "data." + address
+	}
+	if r.Module != "" {
+		address = r.Module + "." + address
+	}
+	switch key := instance.IndexKey.(type) {
+	case string:
+		address += fmt.Sprintf("[%q]", key)
+	case float64:
+		address += "[" + strconv.FormatFloat(key, 'f', -1, 64) + "]"
+	}
+	return address
+}
+
+// ProviderSource returns the source address of the provider of a resource,
+// e.g. hashicorp/aws. The default registry hostname is omitted.
+func (r StateResource) ProviderSource() string {
+	provider := r.Provider
+	if start := strings.Index(provider, `["`); start >= 0 {
+		if end := strings.Index(provider[start+2:], `"]`); end >= 0 {
+			provider = provider[start+2 : start+2+end]
+		}
+	} else {
+		provider = strings.TrimPrefix(provider, "provider.")
+	}
+	return strings.TrimPrefix(provider, "registry.terraform.io/")
+}
+
+// Instances returns the managed resource instances of the state in the form
+// of planned resources, so that the plan checks apply to them
+func (s *State) Instances() []PlanResource {
+	var resources []PlanResource
+	for _, resource := range s.Resources {
+		if resource.Mode == "data" {
+			continue
+		}
+		for _, instance := range resource.Instances {
+			resources = append(resources, PlanResource{
+				Address:      resource.Address(instance),
+				Mode:         resource.Mode,
+				Type:         resource.Type,
+				Name:         resource.Name,
+				ProviderName: resource.ProviderSource(),
+				Values:       instance.Attributes,
+			})
+		}
+	}
+	return resources
+}
+
+// Summary returns the number of resource instances, data sources and
+// outputs of the state, the module addresses and the resource instances per
+// provider
+func (s *State) Summary() StateSummary {
+	summary := StateSummary{
+		TerraformVersion: s.TerraformVersion,
+		Serial:           s.Serial,
+		Outputs:          len(s.Outputs),
+		Modules:          []string{},
+		Providers:        make(map[string]int),
+	}
+	modules := make(map[string]bool)
+	for _, resource := range s.Resources {
+		if resource.Module != "" && !modules[resource.Module] {
+			modules[resource.Module] = true
+			summary.Modules = append(summary.Modules, resource.Module)
+		}
+		if resource.Mode == "data" {
+			summary.DataSources += len(resource.Instances)
+			continue
+		}
+		summary.Resources += len(resource.Instances)
+		summary.Providers[resource.ProviderSource()] += len(resource.Instances)
+	}
+	sort.Strings(summary.Modules)
+	return summary
+}
+
+// StateValidator is the interface for validators of state files
+type StateValidator interface {
+	ValidateState(state *State) []ValidationIssue
+	Name() string
+}
+
+// AnalyzeState runs the state rules against a state file. Module addresses
+// of the state are only checked when the configuration is given.
+func (e *ValidationEngine) AnalyzeState(state *State, config *TerraformConfiguration) (*ValidationResult, error) {
+	e.logger.Info("Analyzing Terraform state", "resources", len(state.Resources))
+
+	result := &ValidationResult{
+		Issues: []ValidationIssue{},
+	}
+	for _, validator := range e.stateValidators {
+		e.logger.Debug("Running state validator", "name", validator.Name())
+		result.Issues = append(result.Issues, validator.ValidateState(state)...)
+	}
+	if config != nil {
+		result.FileCount = len(config.Files)
+		result.Issues = append(result.Issues, orphanedModuleIssues(state, config)...)
+	}
+
+	for _, issue := range result.Issues {
+		switch issue.Severity {
+		case SeverityError:
+			result.ErrorCount++
+		case SeverityWarning:
+			result.WarnCount++
+		case SeverityInfo:
+			result.InfoCount++
+		}
+	}
+
+	return result, nil
+}
+
+// driftConflict describes a resource whose attribute is also managed by
+// standalone resources, so that the two keep changing each other
+type driftConflict struct {
+	Type       string
+	Attributes []string
+	// Key is the attribute of the resource the standalone resources refer to
+	Key         string
+	Standalone  []string
+	Reference   string
+	Explanation string
+}
+
+// driftConflicts are the known combinations of inline attributes and
+// standalone resources that cause permanent drift
+var driftConflicts = []driftConflict{
+	{Type: "aws_security_group", Attributes: []string{"ingress", "egress"}, Key: "id", Standalone: []string{"aws_security_group_rule", "aws_vpc_security_group_ingress_rule", "aws_vpc_security_group_egress_rule"}, Reference: "security_group_id", Explanation: "inline rules and rule resources overwrite each other on every apply"},
+	{Type: "aws_network_acl", Attributes: []string{"ingress", "egress"}, Key: "id", Standalone: []string{"aws_network_acl_rule"}, Reference: "network_acl_id", Explanation: "inline rules and rule resources overwrite each other on every apply"},
+	{Type: "aws_route_table", Attributes: []string{"route"}, Key: "id", Standalone: []string{"aws_route"}, Reference: "route_table_id", Explanation: "inline routes and route resources overwrite each other on every apply"},
+	{Type: "azurerm_network_security_group", Attributes: []string{"security_rule"}, Key: "name", Standalone: []string{"azurerm_network_security_rule"}, Reference: "network_security_group_name", Explanation: "inline rules and rule resources overwrite each other on every apply"},
+	{Type: "aws_autoscaling_group", Attributes: []string{"desired_capacity"}, Key: "name", Standalone: []string{"aws_autoscaling_policy"}, Reference: "autoscaling_group_name", Explanation: "scaling changes the desired capacity outside Terraform unless it is listed in ignore_changes"},
+}
+
+// exclusiveResourceTypes are resource types that manage a set of
+// attachments or bindings exclusively and revert changes made elsewhere
+var exclusiveResourceTypes = map[string]string{
+	"aws_iam_policy_attachment":  "detaches the policy from every role, user and group attached outside of it",
+	"aws_iam_group_membership":   "removes users added to the group outside of it",
+	"google_project_iam_policy":  "replaces the whole IAM policy of the project, including bindings managed elsewhere",
+	"google_folder_iam_policy":   "replaces the whole IAM policy of the folder, including bindings managed elsewhere",
+	"google_storage_bucket_acl":  "replaces the whole ACL of the bucket",
+	"google_project_iam_binding": "removes members granted the role outside of it",
+}
+
+// StateResourceValidator validates the resources recorded in a state
+type StateResourceValidator struct{}
+
+// Name returns the name of the validator
+func (v *StateResourceValidator) Name() string {
+	return "StateResourceValidator"
+}
+
+// ValidateState reports drift-prone resources of the state
+func (v *StateResourceValidator) ValidateState(state *State) []ValidationIssue {
+	resources := state.Instances()
+	var issues []ValidationIssue
+	for _, resource := range resources {
+		if explanation, ok := exclusiveResourceTypes[resource.Type]; ok {
+			issues = append(issues, driftIssue(fmt.Sprintf("'%s' is authoritative and %s", resource.Address, explanation)))
+		}
+
+		for _, conflict := range driftConflicts {
+			if conflict.Type != resource.Type {
+				continue
+			}
+			var attributes []string
+			for _, attribute := range conflict.Attributes {
+				if isSetValue(resource.Values[attribute]) {
+					attributes = append(attributes, attribute)
+				}
+			}
+			key, ok := jsonPrimitiveString(resource.Values[conflict.Key])
+			if len(attributes) == 0 || !ok || key == "" {
+				continue
+			}
+
+			var standalone []string
+			for _, other := range resources {
+				if !containsString(conflict.Standalone, other.Type) {
+					continue
+				}
+				if reference, ok := jsonPrimitiveString(other.Values[conflict.Reference]); ok && reference == key {
+					standalone = append(standalone, other.Address)
+				}
+			}
+			if len(standalone) > 0 {
+				issues = append(issues, driftIssue(fmt.Sprintf("'%s' sets %s and is also managed by %s: %s",
+					resource.Address, strings.Join(attributes, " and "), strings.Join(standalone, ", "), conflict.Explanation)))
+			}
+		}
+	}
+	return issues
+}
+
+// driftIssue returns a state-drift-prone issue
+func driftIssue(message string) ValidationIssue {
+	return ValidationIssue{
+		Message:      message,
+		RuleID:       "state-drift-prone",
+		Severity:     SeverityWarning,
+		Category:     CategoryMaintenance,
+		BestPractice: "Manage each setting in exactly one place",
+		Suggestion:   "Use either the inline attribute or the standalone resources, prefer non-authoritative resources, or add the attribute to lifecycle ignore_changes",
+	}
+}
+
+// isSetValue reports whether a state value is set to something other than
+// null, zero or an empty collection
+func isSetValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case string:
+		return v != ""
+	case float64:
+		return v != 0
+	case bool:
+		return v
+	case []interface{}:
+		return len(v) > 0
+	case map[string]interface{}:
+		return len(v) > 0
+	}
+	return true
+}
+
+// orphanedModuleIssues reports module addresses of the state whose module
+// blocks no longer exist in the configuration. Modules of remote sources are
+// not inspected.
+func orphanedModuleIssues(state *State, config *TerraformConfiguration) []ValidationIssue {
+	tree := config.ModuleTree()
+
+	counts := make(map[string]int)
+	var orphans []string
+	for _, resource := range state.Resources {
+		if resource.Module == "" {
+			continue
+		}
+		orphan, ok := undeclaredModule(tree.Root, resource.Module)
+		if !ok {
+			continue
+		}
+		if counts[orphan] == 0 {
+			orphans = append(orphans, orphan)
+		}
+		counts[orphan] += len(resource.Instances)
+	}
+	sort.Strings(orphans)
+
+	var issues []ValidationIssue
+	for _, orphan := range orphans {
+		issues = append(issues, ValidationIssue{
+			Message:      fmt.Sprintf("State holds %d resource instances of '%s', which is not declared in the configuration and will be destroyed on the next apply", counts[orphan], orphan),
+			RuleID:       "state-orphaned-modules",
+			Severity:     SeverityWarning,
+			Category:     CategoryMaintenance,
+			BestPractice: "Keep the state in sync with the module calls of the configuration",
+			Suggestion:   "Add a moved block if the module was renamed, or remove the resources with terraform state rm once they are no longer needed",
+		})
+	}
+	return issues
+}
+
+// undeclaredModule returns the first module of a module address, including
+// its parents, that is not declared by the module blocks of the tree
+func undeclaredModule(root *ModuleNode, address string) (string, bool) {
+	node := root
+	var prefix []string
+	for _, step := range moduleAddressSteps(address) {
+		prefix = append(prefix, step.address)
+
+		var call *Block
+		for _, block := range node.Config.Blocks("module") {
+			if block.Label(0) == step.name {
+				call = block
+			}
+		}
+		if call == nil {
+			return strings.Join(prefix, "."), true
+		}
+
+		// Modules with remote sources are not part of the configuration
+		var next *ModuleNode
+		for _, local := range node.Calls {
+			if local.Block == call {
+				next = local.Module
+			}
+		}
+		if next == nil {
+			return "", false
+		}
+		node = next
+	}
+	return "", false
+}
+
+// moduleAddressStep is a module call of a module address
+type moduleAddressStep struct {
+	name string
+	// address is the step with its instance key, e.g. module.app["eu"]
+	address string
+}
+
+// moduleAddressSteps splits a module address such as
+// module.app["eu.west"].module.network into its module calls
+func moduleAddressSteps(address string) []moduleAddressStep {
+	var steps []moduleAddressStep
+	for rest := address; strings.HasPrefix(rest, "module."); {
+		end := len("module.")
+		for end < len(rest) && rest[end] != '.' && rest[end] != '[' {
+			end++
+		}
+		step := moduleAddressStep{name: rest[len("module."):end]}
+
+		// Skip the instance key, which may contain dots inside quotes
+		if end < len(rest) && rest[end] == '[' {
+			quoted := false
+			for end++; end < len(rest); end++ {
+				if rest[end] == '"' && rest[end-1] != '\\' {
+					quoted = !quoted
+				}
+				if rest[end] == ']' && !quoted {
+					end++
+					break
+				}
+			}
+		}
+		step.address = rest[:end]
+		steps = append(steps, step)
+		rest = strings.TrimPrefix(rest[end:], ".")
+	}
+	return steps
+}
+
+// DescribeRules returns the rules checked by the validator
+func (v *StateResourceValidator) DescribeRules() []RuleMetadata {
+	return []RuleMetadata{
+		{ID: "state-drift-prone", Name: "StateDriftProne", Description: "Manage each setting in exactly one place", Severity: SeverityWarning, Category: CategoryMaintenance, Tags: []string{"state"}},
+		{ID: "state-orphaned-modules", Name: "StateOrphanedModules", Description: "Keep the state in sync with the module calls of the configuration", Severity: SeverityWarning, Category: CategoryMaintenance, Tags: []string{"state"}},
+		{ID: "state-plaintext-secrets", Name: "StatePlaintextSecrets", Description: "Mark secrets stored in the state as sensitive", Severity: SeverityError, Category: CategorySecurity, HelpURI: cweHelpURI([]string{"CWE-312"}), Tags: []string{"state", "security"}, References: []string{"CWE-312"}},
+	}
+}
+
+// ValidateState reports secrets held by attributes and outputs of the state
+// that are not marked sensitive, so they are shown in plan output and by
+// terraform output
+func (v *SecurityValidator) ValidateState(state *State) []ValidationIssue {
+	scanner := v.secrets
+	if scanner == nil {
+		scanner = NewSecretScanner(nil)
+	}
+
+	var issues []ValidationIssue
+	report := func(owner string, finding SecretFinding) {
+		issues = append(issues, ValidationIssue{
+			Message:      fmt.Sprintf("State stores %s of %s in plain text without marking it sensitive (%s)", finding.Kind, owner, finding.Redacted),
+			RuleID:       "state-plaintext-secrets",
+			Severity:     SeverityError,
+			Category:     CategorySecurity,
+			BestPractice: "Mark secrets stored in the state as sensitive",
+			Suggestion:   "Mark the variable or output sensitive, read the secret from a secrets manager at runtime, and encrypt the state backend",
+			References:   []string{"CWE-312"},
+		})
+	}
+
+	for _, resource := range state.Resources {
+		if resource.Mode == "data" {
+			continue
+		}
+		for _, instance := range resource.Instances {
+			sensitive := sensitivePaths(instance.SensitiveAttributes)
+			owner := fmt.Sprintf("'%s'", resource.Address(instance))
+			walkStateStrings("", "", instance.Attributes, func(path, name, value string) {
+				if isSensitivePath(sensitive, path) {
+					return
+				}
+				if finding, ok := scanner.stateSecret(name, value); ok {
+					report(owner, finding)
+				}
+			})
+		}
+	}
+
+	names := make([]string, 0, len(state.Outputs))
+	for name := range state.Outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		output := state.Outputs[name]
+		if output.Sensitive {
+			continue
+		}
+		walkStateStrings("", name, output.Value, func(path, key, value string) {
+			if finding, ok := scanner.stateSecret(key, value); ok {
+				report(fmt.Sprintf("output '%s'", name), finding)
+			}
+		})
+	}
+	return issues
+}
+
+// stateSecret decides whether a string of the state is a secret, either in a
+// known credential format or because of its name. Entropy is not used, as
+// state is full of generated identifiers and hashes.
+func (s *SecretScanner) stateSecret(name, value string) (SecretFinding, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" || s.allowed(value) {
+		return SecretFinding{}, false
+	}
+
+	// Identifiers such as the ID of an access key are not secret
+	if name != "id" && !nonSecretNamePattern.MatchString(name) {
+		for _, format := range secretFormats {
+			match := format.Pattern.FindStringSubmatch(value)
+			if match == nil || s.allowed(match[1]) {
+				continue
+			}
+			redacted := RedactSecret(match[1])
+			if format.Header {
+				redacted = match[1]
+			}
+			return SecretFinding{Kind: fmt.Sprintf("%s in '%s'", format.Kind, name), Redacted: redacted, value: match[1]}, true
+		}
+	}
+
+	if !isSecretName(name) || placeholderPattern.MatchString(value) || secretReferencePattern.MatchString(value) || publicKeyPattern.MatchString(value) {
+		return SecretFinding{}, false
+	}
+	return SecretFinding{Kind: fmt.Sprintf("the value of '%s'", name), Redacted: RedactSecret(value), value: value}, true
+}
+
+// walkStateStrings calls fn for every string in a state value with its
+// dotted path and the name of the closest attribute or map key
+func walkStateStrings(path, name string, value interface{}, fn func(path, name, value string)) {
+	join := func(step string) string {
+		if path == "" {
+			return step
+		}
+		return path + "." + step
+	}
+
+	switch v := value.(type) {
+	case string:
+		fn(path, name, v)
+	case []interface{}:
+		for i, item := range v {
+			walkStateStrings(join(strconv.Itoa(i)), name, item, fn)
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			walkStateStrings(join(key), key, v[key], fn)
+		}
+	}
+}
+
+// sensitivePaths returns the dotted paths of the sensitive attributes of an
+// instance. Steps are either attribute names or index keys.
+func sensitivePaths(raw []json.RawMessage) map[string]bool {
+	paths := make(map[string]bool)
+	for _, message := range raw {
+		var steps []struct {
+			Type  string      `json:"type"`
+			Value interface{} `json:"value"`
+		}
+		if err := json.Unmarshal(message, &steps); err != nil {
+			continue
+		}
+
+		parts := make([]string, 0, len(steps))
+		for _, step := range steps {
+			switch value := step.Value.(type) {
+			case map[string]interface{}:
+				parts = append(parts, fmt.Sprint(value["value"]))
+			default:
+				parts = append(parts, fmt.Sprint(value))
+			}
+		}
+		paths[strings.Join(parts, ".")] = true
+	}
+	return paths
+}
+
+// isSensitivePath reports whether a path or one of its parents is sensitive
+func isSensitivePath(sensitive map[string]bool, path string) bool {
+	for {
+		if sensitive[path] {
+			return true
+		}
+		i := strings.LastIndex(path, ".")
+		if i < 0 {
+			return false
+		}
+		path = path[:i]
+	}
+}
+
+// ValidateState checks the tags recorded in the state against the tagging
+// policy
+func (v *TagValidator) ValidateState(state *State) []ValidationIssue {
+	return v.resourceTagIssues(state.Instances())
+}
@@ -0,0 +1,690 @@
+// pkg/hashicorp/tfdocs/tags.go
+package tfdocs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"gopkg.in/yaml.v3"
+)
+
+// TagPolicy is a tagging policy declared under tag_policy in a YAML file of
+// the policy directory
+type TagPolicy struct {
+	Severity ValidationSeverity `yaml:"severity"`
+	// Required lists the tag keys every taggable resource must have
+	Required []string `yaml:"required"`
+	// Tags holds the allowed values of individual tags
+	Tags map[string]*TagRule `yaml:"tags"`
+	// KeyCase is the casing of tag keys: pascal, camel, snake, kebab, lower
+	// or upper. GCP labels are always lowercase and are not checked.
+	KeyCase string `yaml:"key_case"`
+	// Resources are glob patterns of the resource types the policy applies
+	// to; all taggable resources if empty
+	Resources []string `yaml:"resources"`
+	Exclude   []string `yaml:"exclude"`
+	// AssumeVariableDefaults resolves variables to their defaults when
+	// computing the tags of a resource
+	AssumeVariableDefaults bool `yaml:"assume_variable_defaults"`
+
+	keyCase *regexp.Regexp
+}
+
+// TagRule restricts the values of a tag to a list or a regular expression
+type TagRule struct {
+	Values  []string `yaml:"values"`
+	Pattern string   `yaml:"pattern"`
+
+	pattern *regexp.Regexp
+}
+
+// tagKeyCases are the supported tag key casing rules
+var tagKeyCases = map[string]*regexp.Regexp{
+	"pascal": regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`),
+	"camel":  regexp.MustCompile(`^[a-z][A-Za-z0-9]*$`),
+	"snake":  regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`),
+	"kebab":  regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`),
+	"lower":  regexp.MustCompile(`^[^A-Z]*$`),
+	"upper":  regexp.MustCompile(`^[^a-z]*$`),
+}
+
+var (
+	gcpLabelKeyPattern   = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,62}$`)
+	gcpLabelValuePattern = regexp.MustCompile(`^[a-z0-9_-]{0,63}$`)
+)
+
+// tagPolicyFile is a YAML policy file holding a tagging policy
+type tagPolicyFile struct {
+	TagPolicy *TagPolicy `yaml:"tag_policy"`
+}
+
+// LoadTagPolicy loads the tag_policy sections of all *.yaml and *.yml files
+// of a directory. Required tags and tag rules of several files are combined.
+// It returns nil if no file declares a tagging policy.
+func LoadTagPolicy(dir string) (*TagPolicy, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read policy directory: %w", err)
+	}
+
+	var policy *TagPolicy
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read policy file %s: %w", entry.Name(), err)
+		}
+
+		filePolicy, err := ParseTagPolicy(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse policy file %s: %w", entry.Name(), err)
+		}
+		if filePolicy == nil {
+			continue
+		}
+		if policy == nil {
+			policy = filePolicy
+			continue
+		}
+		policy.merge(filePolicy)
+	}
+
+	return policy, nil
+}
+
+// ParseTagPolicy parses and checks the tag_policy section of a YAML policy
+// file. It returns nil if the file has no tagging policy.
+func ParseTagPolicy(data []byte) (*TagPolicy, error) {
+	var file tagPolicyFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	if file.TagPolicy == nil {
+		return nil, nil
+	}
+	if err := file.TagPolicy.compile(); err != nil {
+		return nil, err
+	}
+	return file.TagPolicy, nil
+}
+
+// compile checks the policy and fills in defaults
+func (p *TagPolicy) compile() error {
+	switch p.Severity {
+	case "":
+		p.Severity = SeverityWarning
+	case SeverityError, SeverityWarning, SeverityInfo:
+	default:
+		return fmt.Errorf("tag_policy: unknown severity %q", p.Severity)
+	}
+
+	if p.KeyCase != "" {
+		pattern, ok := tagKeyCases[p.KeyCase]
+		if !ok {
+			return fmt.Errorf("tag_policy: unknown key_case %q", p.KeyCase)
+		}
+		p.keyCase = pattern
+	}
+
+	for key, rule := range p.Tags {
+		if rule == nil {
+			return fmt.Errorf("tag_policy: tag %s has no rule", key)
+		}
+		if rule.Pattern != "" {
+			pattern, err := regexp.Compile(rule.Pattern)
+			if err != nil {
+				return fmt.Errorf("tag_policy: invalid pattern for tag %s: %w", key, err)
+			}
+			rule.pattern = pattern
+		}
+	}
+
+	return nil
+}
+
+// merge adds the required tags and tag rules of another policy
+func (p *TagPolicy) merge(other *TagPolicy) {
+	for _, key := range other.Required {
+		if !containsString(p.Required, key) {
+			p.Required = append(p.Required, key)
+		}
+	}
+	if p.Tags == nil {
+		p.Tags = make(map[string]*TagRule)
+	}
+	for key, rule := range other.Tags {
+		p.Tags[key] = rule
+	}
+	if other.KeyCase != "" {
+		p.KeyCase, p.keyCase = other.KeyCase, other.keyCase
+	}
+	p.Resources = append(p.Resources, other.Resources...)
+	p.Exclude = append(p.Exclude, other.Exclude...)
+	p.AssumeVariableDefaults = p.AssumeVariableDefaults || other.AssumeVariableDefaults
+}
+
+// appliesTo reports whether the policy applies to a resource type
+func (p *TagPolicy) appliesTo(resType string) bool {
+	for _, pattern := range p.Exclude {
+		if ok, _ := path.Match(pattern, resType); ok {
+			return false
+		}
+	}
+	if len(p.Resources) == 0 {
+		return true
+	}
+	for _, pattern := range p.Resources {
+		if ok, _ := path.Match(pattern, resType); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// tagConvention describes how a provider tags its resources
+type tagConvention struct {
+	provider string
+	// attribute is the resource attribute holding the tags
+	attribute string
+	// labels is set for GCP labels, which must be lowercase
+	labels bool
+}
+
+// tagConventions are the tagging conventions by resource type prefix
+var tagConventions = []tagConvention{
+	{provider: "aws", attribute: "tags"},
+	{provider: "azurerm", attribute: "tags"},
+	{provider: "google", attribute: "labels", labels: true},
+}
+
+// tagConventionFor returns the tagging convention of a resource type, or nil
+// if the resource type cannot be tagged. The provider schema decides when
+// one is loaded; otherwise a few known untaggable types are excluded.
+func tagConventionFor(resType string, schemas *SchemaRegistry) *tagConvention {
+	var convention *tagConvention
+	for i := range tagConventions {
+		if strings.HasPrefix(resType, tagConventions[i].provider+"_") {
+			convention = &tagConventions[i]
+			break
+		}
+	}
+	if convention == nil {
+		return nil
+	}
+
+	if schemas != nil {
+		if schema, ok := schemas.Resource(resType); ok {
+			attr, ok := schema.Block.Attributes[convention.attribute]
+			if ok && (attr.Optional || attr.Required) {
+				return convention
+			}
+			return nil
+		}
+	}
+
+	switch convention.provider {
+	case "aws":
+		if strings.Contains(resType, "aws_iam_role_policy") ||
+			strings.Contains(resType, "aws_iam_policy") ||
+			strings.Contains(resType, "aws_route") {
+			return nil
+		}
+	case "azurerm":
+		if strings.HasSuffix(resType, "_association") || strings.Contains(resType, "_role_") {
+			return nil
+		}
+	case "google":
+		if strings.Contains(resType, "_iam_") {
+			return nil
+		}
+	}
+	return convention
+}
+
+// tagValue is a statically resolved tag. Value is nil when it is only known
+// at plan time.
+type tagValue struct {
+	Value *string
+	File  string
+	Line  int
+}
+
+// tagSet is the statically resolved tags of a resource. Complete is false
+// when some keys could not be resolved.
+type tagSet struct {
+	Tags     map[string]tagValue
+	Complete bool
+}
+
+// merge adds the tags of another set, overriding existing keys
+func (s *tagSet) merge(other tagSet) {
+	for key, value := range other.Tags {
+		s.Tags[key] = value
+	}
+	s.Complete = s.Complete && other.Complete
+}
+
+// sortedKeys returns the tag keys in order
+func (s tagSet) sortedKeys() []string {
+	keys := make([]string, 0, len(s.Tags))
+	for key := range s.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// tagTarget is the resource whose tags are checked, either a resource block
+// or a resource instance of a plan or state
+type tagTarget struct {
+	Address string
+	Name    string
+	File    string
+	Line    int
+}
+
+// blockTagTarget returns the tag target of a resource block
+func blockTagTarget(block *Block, line int) tagTarget {
+	return tagTarget{Address: block.Address(), Name: block.Label(1), File: block.File, Line: line}
+}
+
+// tagResolver resolves tag expressions to their keys and values, following
+// merge() calls, locals and optionally variable defaults
+type tagResolver struct {
+	config          *TerraformConfiguration
+	locals          map[string]*Attribute
+	variables       map[string]*Block
+	variableDefault bool
+}
+
+// maxTagResolveDepth limits how many locals are followed
+const maxTagResolveDepth = 8
+
+func newTagResolver(config *TerraformConfiguration, variableDefaults bool) *tagResolver {
+	r := &tagResolver{
+		config:          config,
+		locals:          make(map[string]*Attribute),
+		variables:       make(map[string]*Block),
+		variableDefault: variableDefaults,
+	}
+	for _, block := range config.Blocks("locals") {
+		for name, attr := range block.Attributes {
+			r.locals[name] = attr
+		}
+	}
+	for _, block := range config.Blocks("variable") {
+		r.variables[block.Label(0)] = block
+	}
+	return r
+}
+
+// resolve resolves a tag expression declared in a file
+func (r *tagResolver) resolve(expr hclsyntax.Expression, file string, depth int) tagSet {
+	set := tagSet{Tags: make(map[string]tagValue), Complete: true}
+	if depth > maxTagResolveDepth {
+		set.Complete = false
+		return set
+	}
+
+	switch e := expr.(type) {
+	case *hclsyntax.ObjectConsExpr:
+		for _, item := range e.Items {
+			key, ok := objectKey(item.KeyExpr)
+			if !ok {
+				set.Complete = false
+				continue
+			}
+			value := tagValue{File: file, Line: item.KeyExpr.Range().Start.Line}
+			if len(item.ValueExpr.Variables()) == 0 {
+				if v, diags := item.ValueExpr.Value(nil); !diags.HasErrors() && v.IsWhollyKnown() {
+					if s, ok := ctyPrimitiveString(v); ok {
+						value.Value = &s
+					}
+				}
+			}
+			set.Tags[key] = value
+		}
+
+	case *hclsyntax.FunctionCallExpr:
+		if e.Name != "merge" && !(e.Name == "tomap" && len(e.Args) == 1) {
+			set.Complete = false
+			break
+		}
+		for _, arg := range e.Args {
+			set.merge(r.resolve(arg, file, depth))
+		}
+
+	case *hclsyntax.ParenthesesExpr:
+		return r.resolve(e.Expression, file, depth)
+
+	case *hclsyntax.ScopeTraversalExpr:
+		if len(e.Traversal) != 2 {
+			set.Complete = false
+			break
+		}
+		step, ok := e.Traversal[1].(hcl.TraverseAttr)
+		if !ok {
+			set.Complete = false
+			break
+		}
+		switch e.Traversal.RootName() {
+		case "local":
+			if attr, ok := r.locals[step.Name]; ok {
+				return r.resolve(attr.Expr, attr.File, depth+1)
+			}
+		case "var":
+			if variable, ok := r.variables[step.Name]; ok && r.variableDefault {
+				if attr, ok := variable.Attributes["default"]; ok {
+					return r.resolve(attr.Expr, attr.File, depth+1)
+				}
+			}
+		}
+		set.Complete = false
+
+	default:
+		set.Complete = false
+	}
+
+	return set
+}
+
+// objectKey returns the static key of an object constructor item
+func objectKey(expr hclsyntax.Expression) (string, bool) {
+	if key := hcl.ExprAsKeyword(expr); key != "" {
+		return key, true
+	}
+	if wrapped, ok := expr.(*hclsyntax.ObjectConsKeyExpr); ok && wrapped.ForceNonLiteral {
+		return "", false
+	}
+	value, diags := expr.Value(nil)
+	if diags.HasErrors() || !value.IsWhollyKnown() {
+		return "", false
+	}
+	return ctyPrimitiveString(value)
+}
+
+// defaultTags returns the default tags of each provider configuration, keyed
+// by provider name and alias, e.g. aws or aws.west
+func (r *tagResolver) defaultTags() map[string]tagSet {
+	defaults := make(map[string]tagSet)
+	for _, block := range r.config.Blocks("provider") {
+		name := block.Label(0)
+		if attr, ok := block.Attributes["alias"]; ok {
+			if alias, known := attr.StringValue(); known {
+				name += "." + alias
+			}
+		}
+
+		switch block.Label(0) {
+		case "aws":
+			for _, defaultTags := range block.NestedBlocks("default_tags") {
+				if attr, ok := defaultTags.Attributes["tags"]; ok {
+					defaults[name] = r.resolve(attr.Expr, attr.File, 0)
+				}
+			}
+		case "google", "google-beta":
+			if attr, ok := block.Attributes["default_labels"]; ok {
+				defaults[name] = r.resolve(attr.Expr, attr.File, 0)
+			}
+		}
+	}
+	return defaults
+}
+
+// resourceProvider returns the provider configuration of a resource, e.g.
+// aws or aws.west
+func resourceProvider(block *Block, convention *tagConvention) string {
+	if attr, ok := block.Attributes["provider"]; ok {
+		if refs := attr.References(); len(refs) == 1 {
+			return refs[0]
+		}
+	}
+	return convention.provider
+}
+
+// TagValidator checks that resources carry tags, or GCP labels, and that
+// they satisfy the tagging policy
+type TagValidator struct {
+	policy  *TagPolicy
+	schemas *SchemaRegistry
+}
+
+// NewTagValidator creates a validator for a tagging policy. Without a
+// policy only missing tags and invalid GCP labels are reported.
+func NewTagValidator(policy *TagPolicy) *TagValidator {
+	return &TagValidator{policy: policy}
+}
+
+// Name returns the name of the validator
+func (v *TagValidator) Name() string {
+	return "TagValidator"
+}
+
+// SetPolicy replaces the tagging policy of the validator
+func (v *TagValidator) SetPolicy(policy *TagPolicy) {
+	v.policy = policy
+}
+
+// Policy returns the tagging policy of the validator
+func (v *TagValidator) Policy() *TagPolicy {
+	return v.policy
+}
+
+// Validate validates the tags of the resources of a Terraform configuration
+func (v *TagValidator) Validate(config *TerraformConfiguration) []ValidationIssue {
+	var issues []ValidationIssue
+
+	resolver := newTagResolver(config, v.policy != nil && v.policy.AssumeVariableDefaults)
+	defaults := resolver.defaultTags()
+
+	for _, block := range config.Blocks("resource") {
+		resType := block.Label(0)
+		convention := tagConventionFor(resType, v.schemas)
+		if convention == nil {
+			continue
+		}
+
+		// Combine the provider default tags with the resource tags
+		tags := tagSet{Tags: make(map[string]tagValue), Complete: true}
+		providerTags, hasDefaults := defaults[resourceProvider(block, convention)]
+		if hasDefaults {
+			tags.merge(providerTags)
+		}
+		attr, hasTags := block.Attributes[convention.attribute]
+		if hasTags {
+			tags.merge(resolver.resolve(attr.Expr, attr.File, 0))
+		}
+
+		line := block.Line
+		if hasTags {
+			line = attr.Line
+		}
+
+		if !hasTags && !hasDefaults && (v.policy == nil || len(v.policy.Required) == 0) {
+			issues = append(issues, ValidationIssue{
+				Message:      fmt.Sprintf("Resource '%s' of type '%s' is missing %s", block.Label(1), resType, convention.attribute),
+				RuleID:       "resource-tags",
+				Severity:     SeverityInfo,
+				Category:     CategoryMaintenance,
+				File:         block.File,
+				Line:         block.Line,
+				BestPractice: "Apply consistent tagging to all resources for better management",
+				Suggestion:   fmt.Sprintf("Add %s to resource '%s'", convention.attribute, block.Label(1)),
+			})
+		}
+
+		target := blockTagTarget(block, line)
+		if convention.labels {
+			issues = append(issues, labelFormatIssues(target, tags)...)
+		}
+
+		if v.policy != nil && v.policy.appliesTo(resType) {
+			issues = append(issues, v.policyIssues(target, convention, tags)...)
+		}
+	}
+
+	return issues
+}
+
+// labelFormatIssues reports GCP labels whose keys or values are not lowercase
+// letters, digits, underscores and dashes
+func labelFormatIssues(target tagTarget, tags tagSet) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, key := range tags.sortedKeys() {
+		value := tags.Tags[key]
+		var problem string
+		switch {
+		case !gcpLabelKeyPattern.MatchString(key):
+			problem = fmt.Sprintf("label key '%s' must start with a lowercase letter and contain only lowercase letters, digits, underscores and dashes", key)
+		case value.Value != nil && !gcpLabelValuePattern.MatchString(*value.Value):
+			problem = fmt.Sprintf("value '%s' of label '%s' may only contain lowercase letters, digits, underscores and dashes", *value.Value, key)
+		default:
+			continue
+		}
+		issues = append(issues, ValidationIssue{
+			Message:      fmt.Sprintf("Resource '%s': %s", target.Address, problem),
+			RuleID:       "gcp-label-format",
+			Severity:     SeverityError,
+			Category:     CategoryStructure,
+			File:         value.File,
+			Line:         value.Line,
+			BestPractice: "GCP labels are lowercase; keys and values are limited to 63 letters, digits, underscores and dashes",
+			Suggestion:   fmt.Sprintf("Use '%s' instead", strings.ToLower(strings.NewReplacer(" ", "_", ".", "_", ":", "_", "/", "_").Replace(key))),
+		})
+	}
+	return issues
+}
+
+// policyIssues reports tags violating the tagging policy
+func (v *TagValidator) policyIssues(target tagTarget, convention *tagConvention, tags tagSet) []ValidationIssue {
+	var issues []ValidationIssue
+	policy := v.policy
+
+	// GCP labels are matched against policy keys regardless of case and
+	// separators, e.g. CostCenter matches cost_center
+	lookup := func(key string) (string, bool) {
+		if _, ok := tags.Tags[key]; ok {
+			return key, true
+		}
+		if convention.labels {
+			for existing := range tags.Tags {
+				if normalizeLabelKey(existing) == normalizeLabelKey(key) {
+					return existing, true
+				}
+			}
+		}
+		return "", false
+	}
+
+	// Missing keys can only be reported when all tags are known
+	if tags.Complete {
+		var missing []string
+		for _, key := range policy.Required {
+			if _, ok := lookup(key); !ok {
+				missing = append(missing, key)
+			}
+		}
+		if len(missing) > 0 {
+			issues = append(issues, ValidationIssue{
+				Message:      fmt.Sprintf("Resource '%s' is missing required %s: %s", target.Address, convention.attribute, strings.Join(missing, ", ")),
+				RuleID:       "required-tags",
+				Severity:     policy.Severity,
+				Category:     CategoryMaintenance,
+				File:         target.File,
+				Line:         target.Line,
+				BestPractice: fmt.Sprintf("Every resource must carry the %s required by the tagging policy", convention.attribute),
+				Suggestion:   fmt.Sprintf("Add %s to resource '%s' or to the provider default tags", strings.Join(missing, ", "), target.Name),
+			})
+		}
+	}
+
+	// Check the values of tags with rules
+	keys := make([]string, 0, len(policy.Tags))
+	for key := range policy.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		rule := policy.Tags[key]
+		existing, ok := lookup(key)
+		if !ok || tags.Tags[existing].Value == nil {
+			continue
+		}
+		value := tags.Tags[existing]
+		if len(rule.Values) > 0 && !containsString(rule.Values, *value.Value) {
+			issues = append(issues, tagValueIssue(target, policy, existing, value,
+				fmt.Sprintf("must be one of %s", strings.Join(rule.Values, ", "))))
+		} else if rule.pattern != nil && !rule.pattern.MatchString(*value.Value) {
+			issues = append(issues, tagValueIssue(target, policy, existing, value,
+				fmt.Sprintf("must match %s", rule.Pattern)))
+		}
+	}
+
+	// Check the casing of tag keys. Namespaced keys such as
+	// kubernetes.io/cluster/name and keys named by the policy are exempt.
+	if policy.keyCase != nil && !convention.labels {
+		for _, key := range tags.sortedKeys() {
+			if policy.keyCase.MatchString(key) || strings.ContainsAny(key, ":/") ||
+				containsString(policy.Required, key) || policy.Tags[key] != nil {
+				continue
+			}
+			value := tags.Tags[key]
+			issues = append(issues, ValidationIssue{
+				Message:      fmt.Sprintf("Tag key '%s' of resource '%s' is not %s case", key, target.Address, policy.KeyCase),
+				RuleID:       "tag-key-case",
+				Severity:     policy.Severity,
+				Category:     CategoryNaming,
+				File:         value.File,
+				Line:         value.Line,
+				BestPractice: fmt.Sprintf("Use %s case for tag keys", policy.KeyCase),
+				Suggestion:   fmt.Sprintf("Rename tag '%s'", key),
+			})
+		}
+	}
+
+	return issues
+}
+
+func tagValueIssue(target tagTarget, policy *TagPolicy, key string, value tagValue, constraint string) ValidationIssue {
+	return ValidationIssue{
+		Message:      fmt.Sprintf("Tag '%s' of resource '%s' has value '%s', which %s", key, target.Address, *value.Value, constraint),
+		RuleID:       "tag-values",
+		Severity:     policy.Severity,
+		Category:     CategoryMaintenance,
+		File:         value.File,
+		Line:         value.Line,
+		BestPractice: "Tag values must follow the tagging policy",
+		Suggestion:   fmt.Sprintf("Change the value of tag '%s'; it %s", key, constraint),
+	}
+}
+
+// normalizeLabelKey lowercases a key and drops separators
+func normalizeLabelKey(key string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+}
+
+// DescribeRules returns the rules checked by the validator
+func (v *TagValidator) DescribeRules() []RuleMetadata {
+	return []RuleMetadata{
+		{ID: "resource-tags", Name: "ResourceTags", Description: "Apply consistent tagging to all resources for better management", Severity: SeverityInfo, Category: CategoryMaintenance},
+		{ID: "gcp-label-format", Name: "GCPLabelFormat", Description: "GCP labels are lowercase; keys and values are limited to 63 letters, digits, underscores and dashes", Severity: SeverityError, Category: CategoryStructure},
+		{ID: "required-tags", Name: "RequiredTags", Description: "Every resource must carry the tags required by the tagging policy", Severity: SeverityWarning, Category: CategoryMaintenance},
+		{ID: "tag-values", Name: "TagValues", Description: "Tag values must follow the tagging policy", Severity: SeverityWarning, Category: CategoryMaintenance},
+		{ID: "tag-key-case", Name: "TagKeyCase", Description: "Tag keys must follow the casing of the tagging policy", Severity: SeverityWarning, Category: CategoryNaming},
+	}
+}
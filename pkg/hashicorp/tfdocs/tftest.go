@@ -0,0 +1,606 @@
+// pkg/hashicorp/tfdocs/tftest.go
+package tfdocs
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// GeneratedTests are terraform test files generated for a module
+type GeneratedTests struct {
+	Files map[string]string `json:"files"`
+	// Runs are the names of the generated run blocks
+	Runs []string `json:"runs"`
+	// Untested are the variables with validations no failing value could be
+	// derived for
+	Untested []string `json:"untested,omitempty"`
+}
+
+// Patterns recognizing the constraints of validation conditions
+var (
+	containsPattern = regexp.MustCompile(`\bcontains\(\s*\[`)
+	cidrPattern     = regexp.MustCompile(`\bcidr(host|subnet|netmask)\(`)
+	regexPattern    = regexp.MustCompile(`\bregex\(\s*("(?:[^"\\]|\\.)*")`)
+)
+
+// invalidCandidates are tried in order as values failing a regular expression
+var invalidCandidates = []string{"", "INVALID VALUE!", "invalid"}
+
+// validCandidates are tried in order as values matching a regular expression
+var validCandidates = []string{"example", "example-1", "test", "10.0.0.0/16", "arn:aws:iam::123456789012:role/example", "us-east-1", "Example"}
+
+// overrideDuringVersion is the first Terraform version supporting
+// override_during in mock providers
+var overrideDuringVersion = Version{Segments: [3]int{1, 11, 0}}
+
+// isTestFile reports whether a file is a terraform test file
+func isTestFile(name string) bool {
+	return strings.HasSuffix(name, ".tftest.hcl") || strings.HasSuffix(name, ".tftest.json")
+}
+
+// hasTests reports whether a module has test files in its directory or in
+// its tests directory
+func hasTests(config *TerraformConfiguration) bool {
+	for name := range config.Files {
+		if dir := path.Dir(name); isTestFile(name) && (dir == "." || dir == "tests") {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateTests generates terraform test files for the root module of a
+// configuration. tests/<name>.tftest.hcl plans the module with sample values
+// for its required variables and asserts that its outputs are set, and
+// tests/validation.tftest.hcl checks that each validation rejects an invalid
+// value. The providers are mocked and generate computed values at plan time,
+// which requires Terraform 1.11 or later. Variables and outputs of modules
+// in subdirectories are ignored, but their providers are mocked too.
+func GenerateTests(config *TerraformConfiguration, name string) *GeneratedTests {
+	return generateTests(config, name, true)
+}
+
+// generateTests generates the test files of GenerateTests. Without
+// overrideDuring the mock providers generate computed values at apply time
+// only, so the outputs are checked by applying the module against them.
+func generateTests(config *TerraformConfiguration, name string, overrideDuring bool) *GeneratedTests {
+	generated := &GeneratedTests{Files: make(map[string]string), Runs: []string{}}
+	root := config.ModuleTree().Root.Config
+	usages := variableUsages(root)
+
+	var header strings.Builder
+	header.WriteString(mockProviders(config, overrideDuring))
+	var required []string
+	for _, block := range root.Blocks("variable") {
+		if _, ok := block.Attributes["default"]; !ok {
+			required = append(required, fmt.Sprintf("  %s = %s\n", block.Label(0), sampleValue(block, usages[block.Label(0)])))
+		}
+	}
+	if len(required) > 0 {
+		header.WriteString("variables {\n" + strings.Join(required, "") + "}\n\n")
+	}
+
+	// Plan the module and check that its outputs are set
+	command := "plan"
+	if !overrideDuring {
+		command = "apply"
+	}
+	var b strings.Builder
+	b.WriteString(header.String())
+	fmt.Fprintf(&b, "run %q {\n  command = %s\n", command, command)
+	for _, block := range root.Blocks("output") {
+		attr, ok := block.Attributes["value"]
+		if !ok || mayBeNull(root, attr.Expr, make(map[string]bool)) {
+			continue
+		}
+		fmt.Fprintf(&b, "\n  assert {\n    condition     = output.%s != null\n    error_message = %q\n  }\n",
+			block.Label(0), fmt.Sprintf("Output %s must be set.", block.Label(0)))
+	}
+	b.WriteString("}\n")
+	generated.Files[path.Join("tests", name+".tftest.hcl")] = b.String()
+	generated.Runs = append(generated.Runs, command)
+
+	// Check that each validation rejects an invalid value
+	var runs []string
+	for _, block := range root.Blocks("variable") {
+		variable := block.Label(0)
+		for i, validation := range block.NestedBlocks("validation") {
+			condition, ok := validation.Attributes["condition"]
+			if !ok {
+				continue
+			}
+			value, ok := invalidValue(block, condition.Source)
+			if !ok {
+				if !containsString(generated.Untested, variable) {
+					generated.Untested = append(generated.Untested, variable)
+				}
+				continue
+			}
+			run := "invalid_" + variable
+			if i > 0 {
+				run = fmt.Sprintf("%s_%d", run, i+1)
+			}
+			runs = append(runs, fmt.Sprintf("run %q {\n  command = plan\n\n  variables {\n    %s = %s\n  }\n\n  expect_failures = [\n    var.%s,\n  ]\n}\n",
+				run, variable, value, variable))
+			generated.Runs = append(generated.Runs, run)
+		}
+	}
+	if len(runs) > 0 {
+		generated.Files["tests/validation.tftest.hcl"] = header.String() + strings.Join(runs, "\n")
+	}
+
+	for file, content := range generated.Files {
+		if formatted, err := FormatHCL(file, content); err == nil {
+			generated.Files[file] = formatted
+		}
+	}
+	return generated
+}
+
+// mockProviders returns a mock_provider block for each provider of a module,
+// generating values for computed attributes at plan time if overrideDuring
+// is set
+func mockProviders(config *TerraformConfiguration, overrideDuring bool) string {
+	override := ""
+	if overrideDuring {
+		override = "  override_during = plan\n"
+	}
+
+	var b strings.Builder
+	for _, provider := range usedProviders(config) {
+		// The built-in terraform provider cannot be mocked
+		if provider == "terraform" {
+			continue
+		}
+		fmt.Fprintf(&b, "mock_provider %q {\n%s}\n\n", provider, override)
+	}
+	for _, block := range config.Blocks("provider") {
+		if attr, ok := block.Attributes["alias"]; ok {
+			if alias, ok := attr.StringValue(); ok {
+				fmt.Fprintf(&b, "mock_provider %q {\n  alias = %q\n%s}\n\n", block.Label(0), alias, override)
+			}
+		}
+	}
+	return b.String()
+}
+
+// raiseRequiredVersion returns a Terraform version constraint allowing the
+// versions of a constraint from minimum on, keeping its upper bound and
+// exclusions. It returns false if the constraint is invalid or allows no
+// version from minimum on.
+func raiseRequiredVersion(value string, minimum Version) (string, bool) {
+	constraints, err := ParseVersionConstraints(value)
+	if err != nil {
+		return "", false
+	}
+	rng := ConstraintRange(constraints)
+	if rng.Lower != nil && rng.Lower.Version.Compare(minimum) >= 0 {
+		return value, true
+	}
+	raised := rng.Intersect(VersionRange{Lower: &VersionBound{Version: minimum, Inclusive: true}})
+	if raised.Empty() {
+		return "", false
+	}
+
+	parts := []string{">= " + minimum.String()}
+	if raised.Upper != nil {
+		operator := "<"
+		if raised.Upper.Inclusive {
+			operator = "<="
+		}
+		parts = append(parts, operator+" "+raised.Upper.Version.String())
+	}
+	for _, c := range constraints {
+		if c.Operator == "!=" && c.Version.Compare(minimum) >= 0 {
+			parts = append(parts, "!= "+c.Version.String())
+		}
+	}
+	return strings.Join(parts, ", "), true
+}
+
+// mayBeNull reports whether an output value may be null: literal nulls,
+// conditionals with a null branch, try, lookup and one calls, and variables
+// and locals that may be null
+func mayBeNull(config *TerraformConfiguration, expr hclsyntax.Expression, visited map[string]bool) bool {
+	switch e := expr.(type) {
+	case *hclsyntax.LiteralValueExpr:
+		return e.Val.IsNull()
+	case *hclsyntax.ParenthesesExpr:
+		return mayBeNull(config, e.Expression, visited)
+	case *hclsyntax.ConditionalExpr:
+		return mayBeNull(config, e.TrueResult, visited) || mayBeNull(config, e.FalseResult, visited)
+	case *hclsyntax.FunctionCallExpr:
+		switch e.Name {
+		case "one":
+			return true
+		case "try", "lookup", "coalesce":
+			for _, arg := range e.Args {
+				if mayBeNull(config, arg, visited) {
+					return true
+				}
+			}
+		}
+	case *hclsyntax.ScopeTraversalExpr:
+		if len(e.Traversal) < 2 {
+			return false
+		}
+		attr, ok := e.Traversal[1].(hcl.TraverseAttr)
+		if !ok {
+			return false
+		}
+		address := e.Traversal.RootName() + "." + attr.Name
+		if visited[address] {
+			return false
+		}
+		visited[address] = true
+		switch e.Traversal.RootName() {
+		case "var":
+			for _, block := range config.Blocks("variable") {
+				if block.Label(0) != attr.Name {
+					continue
+				}
+				if def, ok := block.Attributes["default"]; ok {
+					return mayBeNull(config, def.Expr, visited)
+				}
+			}
+		case "local":
+			for _, block := range config.Blocks("locals") {
+				if local, ok := block.Attributes[attr.Name]; ok {
+					return mayBeNull(config, local.Expr, visited)
+				}
+			}
+		}
+	}
+	return false
+}
+
+// variableKind returns the kind of value a variable holds: string, number,
+// bool, list, map or object, or an empty string if unknown
+func variableKind(block *Block) string {
+	attr, ok := block.Attributes["type"]
+	if !ok {
+		return ""
+	}
+	switch e := attr.Expr.(type) {
+	case *hclsyntax.ScopeTraversalExpr:
+		return e.Traversal.RootName()
+	case *hclsyntax.FunctionCallExpr:
+		switch e.Name {
+		case "list", "set", "tuple":
+			return "list"
+		case "map", "object":
+			return e.Name
+		}
+	}
+	return ""
+}
+
+// conditionLimits returns the bounds a validation condition places on the
+// length of a variable and on its numeric value
+func conditionLimits(variable, condition string) (lengthMin, lengthMax, valueMin, valueMax *int) {
+	name := regexp.QuoteMeta(variable)
+	length := regexp.MustCompile(`\blength\(\s*var\.` + name + `\s*\)\s*(<=|<|>=|>)\s*(\d+)`)
+	value := regexp.MustCompile(`\bvar\.` + name + `\s*(<=|<|>=|>)\s*(-?\d+)`)
+
+	bound := func(pattern *regexp.Regexp) (min, max *int) {
+		for _, match := range pattern.FindAllStringSubmatch(condition, -1) {
+			n, err := strconv.Atoi(match[2])
+			if err != nil {
+				continue
+			}
+			switch match[1] {
+			case ">=":
+				min = &n
+			case ">":
+				n++
+				min = &n
+			case "<=":
+				max = &n
+			case "<":
+				n--
+				max = &n
+			}
+		}
+		return min, max
+	}
+	lengthMin, lengthMax = bound(length)
+	valueMin, valueMax = bound(value)
+	return lengthMin, lengthMax, valueMin, valueMax
+}
+
+// conditionPatterns returns the regular expressions of a validation
+// condition that compile in Go, which uses the same syntax as Terraform
+func conditionPatterns(condition string) []*regexp.Regexp {
+	var patterns []*regexp.Regexp
+	for _, match := range regexPattern.FindAllStringSubmatch(condition, -1) {
+		source, err := strconv.Unquote(match[1])
+		if err != nil {
+			continue
+		}
+		if pattern, err := regexp.Compile(source); err == nil {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// invalidValue returns a value of a variable failing a validation condition,
+// for conditions checking allowed values, CIDR blocks, regular expressions,
+// lengths or numeric bounds
+func invalidValue(block *Block, condition string) (string, bool) {
+	kind := variableKind(block)
+	value := func(s string) string {
+		if kind == "list" {
+			return fmt.Sprintf("[%q]", s)
+		}
+		return strconv.Quote(s)
+	}
+	if kind != "" && kind != "string" && kind != "list" && kind != "number" {
+		return "", false
+	}
+
+	lengthMin, lengthMax, valueMin, valueMax := conditionLimits(block.Label(0), condition)
+	switch {
+	case kind == "number" && valueMin != nil:
+		return strconv.Itoa(*valueMin - 1), true
+	case kind == "number" && valueMax != nil:
+		return strconv.Itoa(*valueMax + 1), true
+	case kind == "number":
+		return "", false
+	case containsPattern.MatchString(condition):
+		return value("invalid"), true
+	case cidrPattern.MatchString(condition):
+		return value("not-a-cidr"), true
+	}
+
+	if patterns := conditionPatterns(condition); len(patterns) > 0 {
+		for _, candidate := range invalidCandidates {
+			if !patterns[0].MatchString(candidate) {
+				return value(candidate), true
+			}
+		}
+	}
+	if kind == "string" || kind == "" {
+		switch {
+		case lengthMax != nil:
+			return strconv.Quote(strings.Repeat("a", *lengthMax+1)), true
+		case lengthMin != nil && *lengthMin > 0:
+			return `""`, true
+		}
+	}
+	if kind == "list" && lengthMin != nil && *lengthMin > 0 {
+		return "[]", true
+	}
+	return "", false
+}
+
+// sampleValue returns a realistic value for a variable, inferred from its
+// name, its type, the values it is compared with and its validations
+func sampleValue(block *Block, usage *variableUsage) string {
+	name := block.Label(0)
+	kind := variableKind(block)
+	if usage != nil && len(usage.Enums) > 0 && (kind == "" || kind == "string") {
+		return strconv.Quote(usage.Enums[0])
+	}
+
+	var conditions []string
+	for _, validation := range block.NestedBlocks("validation") {
+		if attr, ok := validation.Attributes["condition"]; ok {
+			conditions = append(conditions, attr.Source)
+		}
+	}
+
+	var typ hclsyntax.Expression
+	if attr, ok := block.Attributes["type"]; ok {
+		typ = attr.Expr
+	}
+	value := sampleTypeValue(name, typ)
+
+	switch kind {
+	case "", "string":
+		s, err := strconv.Unquote(value)
+		if err != nil {
+			return value
+		}
+		for _, condition := range conditions {
+			lengthMin, lengthMax, _, _ := conditionLimits(name, condition)
+			if lengthMax != nil && len(s) > *lengthMax {
+				s = s[:*lengthMax]
+			}
+			if lengthMin != nil && len(s) < *lengthMin {
+				s += strings.Repeat("a", *lengthMin-len(s))
+			}
+			for _, pattern := range conditionPatterns(condition) {
+				if pattern.MatchString(s) {
+					continue
+				}
+				for _, candidate := range validCandidates {
+					if pattern.MatchString(candidate) {
+						s = candidate
+						break
+					}
+				}
+			}
+		}
+		return strconv.Quote(s)
+	case "number":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return value
+		}
+		for _, condition := range conditions {
+			_, _, valueMin, valueMax := conditionLimits(name, condition)
+			if valueMin != nil && n < *valueMin {
+				n = *valueMin
+			}
+			if valueMax != nil && n > *valueMax {
+				n = *valueMax
+			}
+		}
+		return strconv.Itoa(n)
+	}
+	return value
+}
+
+// sampleTypeValue returns a value of a type expression, using the name of
+// the variable or attribute to pick realistic values. Values of variables
+// without a type are strings.
+func sampleTypeValue(name string, typ hclsyntax.Expression) string {
+	switch e := typ.(type) {
+	case *hclsyntax.ScopeTraversalExpr:
+		switch e.Traversal.RootName() {
+		case "number":
+			return sampleNumber(name)
+		case "bool":
+			return "true"
+		}
+	case *hclsyntax.FunctionCallExpr:
+		if len(e.Args) == 0 {
+			break
+		}
+		element := strings.TrimSuffix(name, "s")
+		switch e.Name {
+		case "optional":
+			return sampleTypeValue(name, e.Args[0])
+		case "list", "set":
+			if isStringTypeExpr(e.Args[0]) && !strings.HasSuffix(name, "_ids") {
+				switch {
+				case isCIDRName(name) || strings.HasSuffix(name, "subnets"):
+					return `["10.0.1.0/24", "10.0.2.0/24"]`
+				case strings.HasSuffix(name, "zones"):
+					return `["us-east-1a", "us-east-1b"]`
+				}
+			}
+			return "[" + sampleTypeValue(element, e.Args[0]) + "]"
+		case "map":
+			if isStringTypeExpr(e.Args[0]) {
+				switch {
+				case strings.Contains(name, "tags"):
+					return `{ Environment = "test" }`
+				case strings.Contains(name, "labels"):
+					return `{ environment = "test" }`
+				}
+			}
+			return "{ example = " + sampleTypeValue(element, e.Args[0]) + " }"
+		case "tuple":
+			tuple, ok := e.Args[0].(*hclsyntax.TupleConsExpr)
+			if !ok {
+				break
+			}
+			values := make([]string, len(tuple.Exprs))
+			for i, item := range tuple.Exprs {
+				values[i] = sampleTypeValue(element, item)
+			}
+			return "[" + strings.Join(values, ", ") + "]"
+		case "object":
+			object, ok := e.Args[0].(*hclsyntax.ObjectConsExpr)
+			if !ok {
+				break
+			}
+			var attributes []string
+			for _, item := range object.Items {
+				key := hcl.ExprAsKeyword(item.KeyExpr)
+				if key == "" {
+					continue
+				}
+				attributes = append(attributes, fmt.Sprintf("%s = %s", key, sampleTypeValue(key, item.ValueExpr)))
+			}
+			sort.Strings(attributes)
+			return "{ " + strings.Join(attributes, ", ") + " }"
+		}
+	}
+	return strconv.Quote(sampleString(name))
+}
+
+// isStringTypeExpr reports whether a type expression is string
+func isStringTypeExpr(typ hclsyntax.Expression) bool {
+	traversal, ok := typ.(*hclsyntax.ScopeTraversalExpr)
+	return ok && traversal.Traversal.RootName() == "string"
+}
+
+// sampleIDPrefixes are the prefixes of AWS resource IDs by name
+var sampleIDPrefixes = map[string]string{
+	"vpc":               "vpc-",
+	"subnet":            "subnet-",
+	"security_group":    "sg-",
+	"ami":               "ami-",
+	"instance":          "i-",
+	"internet_gateway":  "igw-",
+	"nat_gateway":       "nat-",
+	"route_table":       "rtb-",
+	"network_interface": "eni-",
+}
+
+// sampleString returns a realistic string value for a name
+func sampleString(name string) string {
+	switch {
+	case strings.HasSuffix(name, "_id"):
+		if prefix := sampleIDPrefix(name); prefix != "" {
+			return prefix + "0123456789abcdef0"
+		}
+		return "example-id"
+	case isCIDRName(name) || strings.HasSuffix(name, "subnet"):
+		return "10.0.0.0/16"
+	case strings.HasSuffix(name, "_ip") || strings.Contains(name, "dns_server"):
+		return "10.0.0.4"
+	case strings.HasSuffix(name, "_arn"):
+		return "arn:aws:iam::123456789012:role/example"
+	case strings.HasSuffix(name, "zone"):
+		return "us-east-1a"
+	case strings.Contains(name, "region"):
+		return "us-east-1"
+	case strings.Contains(name, "location"):
+		return "eastus"
+	case strings.Contains(name, "project"):
+		return "my-project"
+	case strings.Contains(name, "email"):
+		return "admin@example.com"
+	case strings.Contains(name, "domain"):
+		return "example.com"
+	case strings.Contains(name, "environment") || name == "env":
+		return "test"
+	case name == "instance_type":
+		return "t3.micro"
+	case strings.HasSuffix(name, "ami"):
+		return "ami-0123456789abcdef0"
+	}
+	return "example"
+}
+
+// sampleIDPrefix returns the ID prefix of the longest resource name an ID
+// name ends with, e.g. sg- for source_security_group_id
+func sampleIDPrefix(name string) string {
+	prefix, length := "", 0
+	for resource, p := range sampleIDPrefixes {
+		if strings.HasSuffix(name, resource+"_id") && len(resource) > length {
+			prefix, length = p, len(resource)
+		}
+	}
+	return prefix
+}
+
+// isCIDRName reports whether a name refers to CIDR blocks, e.g. vpc_cidr or
+// the address_space of an Azure virtual network
+func isCIDRName(name string) bool {
+	return strings.Contains(name, "cidr") || strings.Contains(name, "address_space") || strings.Contains(name, "address_prefix")
+}
+
+// sampleNumber returns a realistic number for a name
+func sampleNumber(name string) string {
+	switch {
+	case strings.Contains(name, "port"):
+		return "443"
+	case strings.Contains(name, "days") || strings.Contains(name, "retention"):
+		return "30"
+	case strings.HasSuffix(name, "asn"):
+		return "64514"
+	}
+	return "1"
+}
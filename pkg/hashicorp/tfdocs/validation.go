@@ -2,11 +2,15 @@
 package tfdocs
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 )
 
 // ValidationSeverity represents the severity of a validation issue
@@ -33,12 +37,16 @@ const (
 // ValidationIssue represents an issue found during validation
 type ValidationIssue struct {
 	Message      string             `json:"message"`
+	RuleID       string              `json:"rule_id,omitempty"`
 	Severity     ValidationSeverity  `json:"severity"`
 	Category     ValidationCategory  `json:"category"`
 	File         string              `json:"file,omitempty"`
 	Line         int                 `json:"line,omitempty"`
 	BestPractice string              `json:"best_practice,omitempty"`
 	Suggestion   string              `json:"suggestion,omitempty"`
+	Fix          *Fix                `json:"fix,omitempty"`
+	References   []string            `json:"references,omitempty"`
+	Diff         string              `json:"diff,omitempty"`
 }
 
 // ValidationResult represents the result of a validation
@@ -53,13 +61,52 @@ type ValidationResult struct {
 // TerraformConfiguration represents a Terraform configuration
 type TerraformConfiguration struct {
 	Files map[string]string
+
+	parsed map[string]*ParsedFile
 }
 
 // ValidationEngine validates Terraform configurations against best practices
 type ValidationEngine struct {
-	docIndexer   *Indexer
-	logger       Logger
-	validators   []Validator
+	docIndexer      *Indexer
+	logger          Logger
+	validators      []Validator
+	planValidators  []PlanValidator
+	stateValidators []StateValidator
+	policyPath      string
+	customRules     *CustomRuleValidator
+	regoPath        string
+	regoPolicies    *RegoValidator
+	moduleTree      *ModuleTreeValidator
+	schemaPath      string
+	schemas         *SchemaRegistry
+	tags            *TagValidator
+	security        *SecurityValidator
+}
+
+// ValidationEngineOption is a function that configures a ValidationEngine
+type ValidationEngineOption func(*ValidationEngine)
+
+// WithPolicyPath sets the directory custom policy rules are loaded from
+func WithPolicyPath(path string) ValidationEngineOption {
+	return func(e *ValidationEngine) {
+		e.policyPath = path
+	}
+}
+
+// WithRegoPolicyPath sets the directory Rego policies are loaded from.
+// Rego evaluation is disabled when no directory is configured.
+func WithRegoPolicyPath(path string) ValidationEngineOption {
+	return func(e *ValidationEngine) {
+		e.regoPath = path
+	}
+}
+
+// WithSchemaPath sets the directory provider schemas are loaded from. Each
+// file holds the output of terraform providers schema -json.
+func WithSchemaPath(path string) ValidationEngineOption {
+	return func(e *ValidationEngine) {
+		e.schemaPath = path
+	}
 }
 
 // Validator is the interface for validators
@@ -69,26 +116,107 @@ type Validator interface {
 }
 
 // NewValidationEngine creates a new validation engine
-func NewValidationEngine(docIndexer *Indexer, logger Logger) *ValidationEngine {
+func NewValidationEngine(docIndexer *Indexer, logger Logger, options ...ValidationEngineOption) *ValidationEngine {
 	engine := &ValidationEngine{
-		docIndexer: docIndexer,
-		logger:     logger,
+		docIndexer:   docIndexer,
+		logger:       logger,
+		customRules:  &CustomRuleValidator{},
+		regoPolicies: &RegoValidator{},
+		moduleTree:   &ModuleTreeValidator{},
+		schemas:      NewSchemaRegistry(),
+	}
+	engine.tags = &TagValidator{schemas: engine.schemas}
+	engine.security = &SecurityValidator{}
+
+	// Apply options
+	for _, option := range options {
+		option(engine)
 	}
 
 	// Register validators
 	engine.validators = []Validator{
 		&StructureValidator{},
 		&NamingValidator{},
-		&SecurityValidator{},
+		engine.security,
 		&DocumentationValidator{},
 		&ModuleValidator{},
 		&ResourceValidator{},
+		engine.tags,
+		&ReferenceValidator{},
+		&VersionValidator{},
+		&SchemaValidator{schemas: engine.schemas},
+		&FormatValidator{},
+		engine.customRules,
+		engine.regoPolicies,
+	}
+	engine.planValidators = []PlanValidator{
+		&PlanChangeValidator{},
+		engine.security,
+		engine.tags,
+	}
+	engine.stateValidators = []StateValidator{
+		&StateResourceValidator{},
+		engine.security,
+		engine.tags,
 	}
 
 	return engine
 }
 
-// ValidateConfiguration validates a Terraform configuration
+// Initialize loads the custom policy rules, Rego policies and provider
+// schemas from their directories
+func (e *ValidationEngine) Initialize(ctx context.Context) error {
+	e.logger.Info("Initializing validation engine", "policyPath", e.policyPath, "regoPath", e.regoPath, "schemaPath", e.schemaPath)
+
+	if e.policyPath != "" {
+		rules, err := LoadCustomRules(e.policyPath)
+		if err != nil {
+			return fmt.Errorf("failed to load custom rules: %w", err)
+		}
+		e.customRules.SetRules(rules)
+
+		policy, err := LoadTagPolicy(e.policyPath)
+		if err != nil {
+			return fmt.Errorf("failed to load tag policy: %w", err)
+		}
+		e.tags.SetPolicy(policy)
+
+		secrets, err := LoadSecretPolicy(e.policyPath)
+		if err != nil {
+			return fmt.Errorf("failed to load secrets policy: %w", err)
+		}
+		e.security.SetSecretPolicy(secrets)
+	}
+
+	if e.regoPath != "" {
+		policies, err := LoadRegoPolicies(ctx, e.regoPath)
+		if err != nil {
+			return fmt.Errorf("failed to load rego policies: %w", err)
+		}
+		e.regoPolicies.queries = policies.queries
+	}
+
+	if e.schemaPath != "" {
+		schemas, err := LoadProviderSchemas(e.schemaPath)
+		if err != nil {
+			return fmt.Errorf("failed to load provider schemas: %w", err)
+		}
+		for _, schema := range schemas {
+			e.schemas.Add(schema)
+		}
+	}
+
+	e.logger.Info("Validation engine initialized",
+		"customRuleCount", len(e.customRules.Rules()),
+		"tagPolicy", e.tags.Policy() != nil,
+		"regoQueryCount", len(e.regoPolicies.queries),
+		"schemaCount", e.schemas.Count())
+	return nil
+}
+
+// ValidateConfiguration validates a Terraform configuration. Configurations
+// with local modules in subdirectories are split into a module tree: each
+// module is validated independently, followed by checks across modules.
 func (e *ValidationEngine) ValidateConfiguration(config *TerraformConfiguration) (*ValidationResult, error) {
 	e.logger.Info("Validating Terraform configuration")
 
@@ -99,11 +227,24 @@ func (e *ValidationEngine) ValidateConfiguration(config *TerraformConfiguration)
 	// Count files
 	result.FileCount = len(config.Files)
 
-	// Run each validator
-	for _, validator := range e.validators {
-		e.logger.Debug("Running validator", "name", validator.Name())
-		issues := validator.Validate(config)
-		result.Issues = append(result.Issues, issues...)
+	tree := config.ModuleTree()
+	if len(tree.Modules) == 1 {
+		result.Issues = append(result.Issues, e.validateModule(config)...)
+	} else {
+		for _, module := range tree.Modules {
+			if module.IsRoot() && !module.HasTerraformFiles() {
+				continue
+			}
+			e.logger.Debug("Validating module", "path", module.Path)
+			for _, issue := range e.validateModule(module.Config) {
+				// Renaming an input of a called module would break its callers
+				if issue.RuleID == "variable-naming" && len(module.Callers) > 0 {
+					issue.Fix = nil
+				}
+				result.Issues = append(result.Issues, relocateIssue(issue, module))
+			}
+		}
+		result.Issues = append(result.Issues, e.moduleTree.Validate(config)...)
 	}
 
 	// Count issues by severity
@@ -121,6 +262,35 @@ func (e *ValidationEngine) ValidateConfiguration(config *TerraformConfiguration)
 	return result, nil
 }
 
+// validateModule runs each validator against the configuration of one module
+func (e *ValidationEngine) validateModule(config *TerraformConfiguration) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, validator := range e.validators {
+		e.logger.Debug("Running validator", "name", validator.Name())
+		issues = append(issues, validator.Validate(config)...)
+	}
+	return issues
+}
+
+// relocateIssue makes the file paths of an issue found in a module relative
+// to the configuration. Issues of child modules that are not tied to a file
+// are reported against the module's main file.
+func relocateIssue(issue ValidationIssue, module *ModuleNode) ValidationIssue {
+	if module.IsRoot() {
+		return issue
+	}
+
+	if issue.File == "" {
+		issue.File = firstTerraformFile(module)
+		issue.Line = 1
+	}
+	issue.File = module.FilePath(issue.File)
+	if issue.Fix != nil {
+		issue.Fix = relocateFix(issue.Fix, module.Path)
+	}
+	return issue
+}
+
 // SuggestImprovements suggests improvements for a Terraform configuration
 func (e *ValidationEngine) SuggestImprovements(config *TerraformConfiguration) (map[string]string, error) {
 	improvements := make(map[string]string)
@@ -131,37 +301,53 @@ func (e *ValidationEngine) SuggestImprovements(config *TerraformConfiguration) (
 		return nil, err
 	}
 
-	// Generate improvements for common issues
-	if !hasMainTF(config) {
-		improvements["main.tf"] = "// main.tf\n" + generateMainTF(config)
-	}
-
+	// Generate improvements for common issues. A missing main.tf is not
+	// generated, since its resources cannot be derived from the configuration.
 	if !hasVariablesTF(config) {
-		improvements["variables.tf"] = "// variables.tf\n" + generateVariablesTF(config)
+		if variables := generateVariablesTF(config); variables != "" {
+			improvements["variables.tf"] = variables
+		}
 	}
 
 	if !hasOutputsTF(config) {
-		improvements["outputs.tf"] = "// outputs.tf\n" + generateOutputsTF(config)
+		if outputs := generateOutputsTF(config); outputs != "" {
+			improvements["outputs.tf"] = outputs
+		}
+	}
+
+	if !hasVersionConstraints(config) {
+		improvements["versions.tf"] = generateVersionsTF(config)
 	}
 
 	if !hasReadmeMD(config) {
 		improvements["README.md"] = generateReadmeMD(config)
+	} else if readme, ok := config.Files["README.md"]; ok && strings.Contains(readme, DocsBeginMarker) {
+		// Refresh the generated section of an existing README
+		if updated := UpdateReadme(readme, config.ModuleDocs()); updated != readme {
+			improvements["README.md"] = updated
+		}
 	}
 
-	// Add specific improvements based on validation issues
+	// Apply the machine-applicable fixes of the validation issues
+	var fixable []ValidationIssue
 	for _, issue := range result.Issues {
-		if issue.Severity == SeverityError || issue.Severity == SeverityWarning {
-			if issue.File != "" && issue.Suggestion != "" {
-				if _, ok := improvements[issue.File]; !ok {
-					if content, ok := config.Files[issue.File]; ok {
-						improvements[issue.File] = content
-					} else {
-						improvements[issue.File] = ""
-					}
-				}
-				// Add comment with improvement suggestion
-				improvements[issue.File] = fmt.Sprintf("// TODO: %s\n%s", issue.Message, improvements[issue.File])
-			}
+		if issue.Fix != nil && (issue.Severity == SeverityError || issue.Severity == SeverityWarning) {
+			fixable = append(fixable, issue)
+		}
+	}
+	fixed := ApplyIssueFixes(config.Files, fixable)
+	for _, name := range fixed.Changed {
+		improvements[name] = fixed.Files[name]
+	}
+
+	// Format the improved files, since fixes and generated files are not
+	// aligned like terraform fmt does
+	for name, content := range improvements {
+		if !isFormattedFile(name) {
+			continue
+		}
+		if formatted, err := FormatHCL(name, content); err == nil {
+			improvements[name] = formatted
 		}
 	}
 
@@ -184,6 +370,7 @@ func (v *StructureValidator) Validate(config *TerraformConfiguration) []Validati
 	if !hasMainTF(config) {
 		issues = append(issues, ValidationIssue{
 			Message:      "Missing main.tf file",
+			RuleID:       "main-tf",
 			Severity:     SeverityError,
 			Category:     CategoryStructure,
 			BestPractice: "Include a main.tf file with core resource definitions",
@@ -194,6 +381,7 @@ func (v *StructureValidator) Validate(config *TerraformConfiguration) []Validati
 	if !hasVariablesTF(config) {
 		issues = append(issues, ValidationIssue{
 			Message:      "Missing variables.tf file",
+			RuleID:       "variables-tf",
 			Severity:     SeverityWarning,
 			Category:     CategoryStructure,
 			BestPractice: "Include a variables.tf file for input variable definitions",
@@ -204,6 +392,7 @@ func (v *StructureValidator) Validate(config *TerraformConfiguration) []Validati
 	if !hasOutputsTF(config) {
 		issues = append(issues, ValidationIssue{
 			Message:      "Missing outputs.tf file",
+			RuleID:       "outputs-tf",
 			Severity:     SeverityWarning,
 			Category:     CategoryStructure,
 			BestPractice: "Include an outputs.tf file for output definitions",
@@ -218,6 +407,7 @@ func (v *StructureValidator) Validate(config *TerraformConfiguration) []Validati
 			if lineCount > 500 {
 				issues = append(issues, ValidationIssue{
 					Message:      fmt.Sprintf("File %s is too large (%d lines). Consider splitting it into multiple files.", name, lineCount),
+					RuleID:       "file-size",
 					Severity:     SeverityWarning,
 					Category:     CategoryMaintenance,
 					File:         name,
@@ -239,6 +429,7 @@ func (v *StructureValidator) Validate(config *TerraformConfiguration) []Validati
 	if len(missingFiles) > 0 {
 		issues = append(issues, ValidationIssue{
 			Message:      fmt.Sprintf("Module is missing standard files: %s", strings.Join(missingFiles, ", ")),
+			RuleID:       "standard-module-files",
 			Severity:     SeverityInfo,
 			Category:     CategoryStructure,
 			BestPractice: "Follow standard module structure with main.tf, variables.tf, outputs.tf, and README.md",
@@ -246,9 +437,78 @@ func (v *StructureValidator) Validate(config *TerraformConfiguration) []Validati
 		})
 	}
 
+	// Check for tests of modules managing infrastructure behind an interface.
+	// Examples and other callers only declaring module blocks are skipped.
+	managed := len(config.Blocks("resource")) > 0 || len(config.Blocks("data")) > 0
+	if managed && !hasTests(config) && (len(config.Blocks("variable")) > 0 || len(config.Blocks("output")) > 0) {
+		fix := &Fix{Description: "Generate terraform test files for the module"}
+		versionEdits, overrideDuring := testsVersionEdits(config)
+		if overrideDuring && len(versionEdits) > 0 {
+			fix.Description += fmt.Sprintf(" and require Terraform %s for the mock providers", overrideDuringVersion)
+			fix.Edits = versionEdits
+		}
+
+		tests := generateTests(config, "main", overrideDuring)
+		names := make([]string, 0, len(tests.Files))
+		for name := range tests.Files {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fix.Edits = append(fix.Edits, TextEdit{File: name, NewText: tests.Files[name]})
+		}
+		issues = append(issues, ValidationIssue{
+			Message:      "Module has no terraform test files",
+			RuleID:       "module-tests",
+			Severity:     SeverityInfo,
+			Category:     CategoryStructure,
+			BestPractice: "Test modules with .tftest.hcl files planning the module and checking its outputs and validations",
+			Suggestion:   "Add a tests directory with run blocks asserting the outputs and expect_failures for the variable validations",
+			Fix:          fix,
+		})
+	}
+
 	return issues
 }
 
+// testsVersionEdits returns the edits raising the required_version of a
+// module to a version supporting override_during in mock providers, and
+// whether the generated tests may use it. Modules without a terraform block,
+// or whose constraints exclude all versions supporting it, get tests
+// without override_during.
+func testsVersionEdits(config *TerraformConfiguration) ([]TextEdit, bool) {
+	blocks := config.Blocks("terraform")
+	if len(blocks) == 0 {
+		return nil, false
+	}
+
+	var edits []TextEdit
+	constrained := false
+	for _, block := range blocks {
+		attr, ok := block.Attributes["required_version"]
+		if !ok {
+			continue
+		}
+		value, known := attr.StringValue()
+		if !known {
+			return nil, false
+		}
+		raised, ok := raiseRequiredVersion(value, overrideDuringVersion)
+		if !ok {
+			return nil, false
+		}
+		constrained = true
+		if raised != value {
+			edits = append(edits, replaceRange(attr.Expr.Range(), fmt.Sprintf("%q", raised)))
+		}
+	}
+	if !constrained {
+		value := fmt.Sprintf("%q", ">= "+overrideDuringVersion.String())
+		edits = append(edits, addAttributeFix(blocks[0], "required_version", value, "").Edits...)
+	}
+	return edits, true
+}
+
 // NamingValidator validates naming conventions in a Terraform configuration
 type NamingValidator struct{}
 
@@ -262,33 +522,34 @@ func (v *NamingValidator) Validate(config *TerraformConfiguration) []ValidationI
 	var issues []ValidationIssue
 
 	// Check variable naming conventions
-	varPattern := regexp.MustCompile(`variable\s+"([^"]+)"\s+{`)
-	for name, content := range config.Files {
-		if strings.HasSuffix(name, ".tf") {
-			matches := varPattern.FindAllStringSubmatch(content, -1)
-			for _, match := range matches {
-				varName := match[1]
-				if strings.Contains(varName, "-") {
-					issues = append(issues, ValidationIssue{
-						Message:      fmt.Sprintf("Variable name '%s' uses hyphens instead of underscores", varName),
-						Severity:     SeverityWarning,
-						Category:     CategoryNaming,
-						File:         name,
-						BestPractice: "Use underscores, not hyphens, in variable names",
-						Suggestion:   fmt.Sprintf("Rename variable '%s' to use underscores instead of hyphens", varName),
-					})
-				}
-				if strings.ToLower(varName) != varName {
-					issues = append(issues, ValidationIssue{
-						Message:      fmt.Sprintf("Variable name '%s' uses uppercase letters", varName),
-						Severity:     SeverityInfo,
-						Category:     CategoryNaming,
-						File:         name,
-						BestPractice: "Use lowercase letters in variable names",
-						Suggestion:   fmt.Sprintf("Rename variable '%s' to use all lowercase letters", varName),
-					})
-				}
-			}
+	for _, block := range config.Blocks("variable") {
+		varName := block.Label(0)
+		fixedName := strings.ToLower(strings.ReplaceAll(varName, "-", "_"))
+		if strings.Contains(varName, "-") {
+			issues = append(issues, ValidationIssue{
+				Message:      fmt.Sprintf("Variable name '%s' uses hyphens instead of underscores", varName),
+				RuleID:       "variable-naming",
+				Severity:     SeverityWarning,
+				Category:     CategoryNaming,
+				File:         block.File,
+				Line:         block.Line,
+				BestPractice: "Use underscores, not hyphens, in variable names",
+				Suggestion:   fmt.Sprintf("Rename variable '%s' to use underscores instead of hyphens", varName),
+				Fix:          renameVariableFix(config, block, fixedName),
+			})
+		}
+		if strings.ToLower(varName) != varName {
+			issues = append(issues, ValidationIssue{
+				Message:      fmt.Sprintf("Variable name '%s' uses uppercase letters", varName),
+				RuleID:       "variable-naming",
+				Severity:     SeverityInfo,
+				Category:     CategoryNaming,
+				File:         block.File,
+				Line:         block.Line,
+				BestPractice: "Use lowercase letters in variable names",
+				Suggestion:   fmt.Sprintf("Rename variable '%s' to use all lowercase letters", varName),
+				Fix:          renameVariableFix(config, block, fixedName),
+			})
 		}
 	}
 
@@ -305,6 +566,7 @@ func (v *NamingValidator) Validate(config *TerraformConfiguration) []ValidationI
 				}
 				issues = append(issues, ValidationIssue{
 					Message:      fmt.Sprintf("Resource name '%s' doesn't follow naming convention", resName),
+					RuleID:       "resource-naming",
 					Severity:     SeverityInfo,
 					Category:     CategoryNaming,
 					File:         name,
@@ -319,7 +581,15 @@ func (v *NamingValidator) Validate(config *TerraformConfiguration) []ValidationI
 }
 
 // SecurityValidator validates security practices in a Terraform configuration
-type SecurityValidator struct{}
+type SecurityValidator struct {
+	secrets *SecretScanner
+}
+
+// SetSecretPolicy replaces the allow list and entropy threshold of the
+// secrets scanner
+func (v *SecurityValidator) SetSecretPolicy(policy *SecretPolicy) {
+	v.secrets = NewSecretScanner(policy)
+}
 
 // Name returns the name of the validator
 func (v *SecurityValidator) Name() string {
@@ -331,58 +601,57 @@ func (v *SecurityValidator) Validate(config *TerraformConfiguration) []Validatio
 	var issues []ValidationIssue
 
 	// Check for hardcoded credentials
-	secretPattern := regexp.MustCompile(`(?i)(password|secret|key|token|credential)s?\s*=\s*"[^"]+"`)
-	for name, content := range config.Files {
-		if strings.HasSuffix(name, ".tf") {
-			matches := secretPattern.FindAllStringSubmatch(content, -1)
-			for _, match := range matches {
-				issues = append(issues, ValidationIssue{
-					Message:      fmt.Sprintf("Possible hardcoded secret found: %s", match[0]),
-					Severity:     SeverityError,
-					Category:     CategorySecurity,
-					File:         name,
-					BestPractice: "Never hardcode sensitive values in Terraform configuration",
-					Suggestion:   "Use variables with sensitive = true or integrate with a secrets management solution",
-				})
-			}
+	scanner := v.secrets
+	if scanner == nil {
+		scanner = NewSecretScanner(nil)
+	}
+	for _, finding := range scanner.Scan(config) {
+		message := fmt.Sprintf("Possible hardcoded secret found: %s (%s)", finding.Kind, finding.Redacted)
+		if finding.Entropy > 0 {
+			message = fmt.Sprintf("Possible hardcoded secret found: %s (%s, %.2f bits per character)", finding.Kind, finding.Redacted, finding.Entropy)
 		}
+		issues = append(issues, ValidationIssue{
+			Message:      message,
+			RuleID:       "hardcoded-secrets",
+			Severity:     SeverityError,
+			Category:     CategorySecurity,
+			File:         finding.File,
+			Line:         finding.Line,
+			BestPractice: "Never hardcode sensitive values in Terraform configuration",
+			Suggestion:   "Use variables with sensitive = true or integrate with a secrets management solution",
+			References:   []string{"CWE-798"},
+		})
 	}
 
 	// Check for sensitive variables
-	sensitivePattern := regexp.MustCompile(`variable\s+"([^"]+)"\s+{(?:(?:.|\n)(?!^\}$))*sensitive\s*=\s*true`)
-	for name, content := range config.Files {
-		if strings.HasSuffix(name, ".tf") && strings.Contains(strings.ToLower(name), "variable") {
-			if !sensitivePattern.MatchString(content) && secretPattern.MatchString(content) {
-				issues = append(issues, ValidationIssue{
-					Message:      "Sensitive variables should be marked with sensitive = true",
-					Severity:     SeverityWarning,
-					Category:     CategorySecurity,
-					File:         name,
-					BestPractice: "Mark sensitive variables with sensitive = true",
-					Suggestion:   "Add sensitive = true to variable definitions containing sensitive information",
-				})
-			}
+	sensitiveNamePattern := regexp.MustCompile(`(?i)(password|passwd|secret|token|credential|private_key|api_key|access_key)`)
+	for _, block := range config.Blocks("variable") {
+		varName := block.Label(0)
+		if !sensitiveNamePattern.MatchString(varName) {
+			continue
 		}
-	}
-
-	// Check for overly permissive security groups
-	sgPattern := regexp.MustCompile(`(?i)ingress\s+{[^}]*cidr_blocks\s*=\s*\[\s*"0\.0\.0\.0/0"\s*\]`)
-	for name, content := range config.Files {
-		if strings.HasSuffix(name, ".tf") {
-			matches := sgPattern.FindAllString(content, -1)
-			for range matches {
-				issues = append(issues, ValidationIssue{
-					Message:      "Security group allows access from 0.0.0.0/0 (any IP)",
-					Severity:     SeverityWarning,
-					Category:     CategorySecurity,
-					File:         name,
-					BestPractice: "Restrict security group access to specific IP ranges",
-					Suggestion:   "Replace 0.0.0.0/0 with specific IP ranges or use a variable for allowed IPs",
-				})
+		if attr, ok := block.Attributes["sensitive"]; ok {
+			if value, known := attr.StringValue(); !known || value == "true" {
+				continue
 			}
 		}
+		issues = append(issues, ValidationIssue{
+			Message:      fmt.Sprintf("Sensitive variable '%s' should be marked with sensitive = true", varName),
+			RuleID:       "sensitive-variables",
+			Severity:     SeverityWarning,
+			Category:     CategorySecurity,
+			File:         block.File,
+			Line:         block.Line,
+			BestPractice: "Mark sensitive variables with sensitive = true",
+			Suggestion:   fmt.Sprintf("Add sensitive = true to variable '%s'", varName),
+			Fix:          sensitiveFix(block),
+			References:   []string{"CWE-532"},
+		})
 	}
 
+	// Check the built-in security rule pack
+	issues = append(issues, securityPackIssues(config)...)
+
 	return issues
 }
 
@@ -402,6 +671,7 @@ func (v *DocumentationValidator) Validate(config *TerraformConfiguration) []Vali
 	if !hasReadmeMD(config) {
 		issues = append(issues, ValidationIssue{
 			Message:      "Missing README.md file",
+			RuleID:       "readme",
 			Severity:     SeverityWarning,
 			Category:     CategoryDocumentation,
 			BestPractice: "Include a README.md file with module documentation",
@@ -410,50 +680,47 @@ func (v *DocumentationValidator) Validate(config *TerraformConfiguration) []Vali
 	}
 
 	// Check variable descriptions
-	varPattern := regexp.MustCompile(`variable\s+"([^"]+)"\s+{(?:(?:.|\n)(?!^\}$))*}`)
-	descPattern := regexp.MustCompile(`description\s*=\s*"[^"]+"`)
-	for name, content := range config.Files {
-		if strings.HasSuffix(name, ".tf") && strings.Contains(strings.ToLower(name), "variable") {
-			varMatches := varPattern.FindAllStringSubmatch(content, -1)
-			for _, varMatch := range varMatches {
-				varDef := varMatch[0]
-				varName := varMatch[1]
-				if !descPattern.MatchString(varDef) {
-					issues = append(issues, ValidationIssue{
-						Message:      fmt.Sprintf("Variable '%s' is missing a description", varName),
-						Severity:     SeverityWarning,
-						Category:     CategoryDocumentation,
-						File:         name,
-						BestPractice: "Include descriptions for all variables",
-						Suggestion:   fmt.Sprintf("Add a description attribute to variable '%s'", varName),
-					})
-				}
-			}
+	for _, block := range config.Blocks("variable") {
+		varName := block.Label(0)
+		if !hasDescription(block) {
+			issues = append(issues, ValidationIssue{
+				Message:      fmt.Sprintf("Variable '%s' is missing a description", varName),
+				RuleID:       "variable-description",
+				Severity:     SeverityWarning,
+				Category:     CategoryDocumentation,
+				File:         block.File,
+				Line:         block.Line,
+				BestPractice: "Include descriptions for all variables",
+				Suggestion:   fmt.Sprintf("Add a description attribute to variable '%s'", varName),
+				Fix:          descriptionFix(block),
+			})
 		}
 	}
 
 	// Check output descriptions
-	outPattern := regexp.MustCompile(`output\s+"([^"]+)"\s+{(?:(?:.|\n)(?!^\}$))*}`)
-	for name, content := range config.Files {
-		if strings.HasSuffix(name, ".tf") && strings.Contains(strings.ToLower(name), "output") {
-			outMatches := outPattern.FindAllStringSubmatch(content, -1)
-			for _, outMatch := range outMatches {
-				outDef := outMatch[0]
-				outName := outMatch[1]
-				if !descPattern.MatchString(outDef) {
-					issues = append(issues, ValidationIssue{
-						Message:      fmt.Sprintf("Output '%s' is missing a description", outName),
-						Severity:     SeverityInfo,
-						Category:     CategoryDocumentation,
-						File:         name,
-						BestPractice: "Include descriptions for all outputs",
-						Suggestion:   fmt.Sprintf("Add a description attribute to output '%s'", outName),
-					})
-				}
-			}
+	for _, block := range config.Blocks("output") {
+		outName := block.Label(0)
+		if !hasDescription(block) {
+			issues = append(issues, ValidationIssue{
+				Message:      fmt.Sprintf("Output '%s' is missing a description", outName),
+				RuleID:       "output-description",
+				Severity:     SeverityInfo,
+				Category:     CategoryDocumentation,
+				File:         block.File,
+				Line:         block.Line,
+				BestPractice: "Include descriptions for all outputs",
+				Suggestion:   fmt.Sprintf("Add a description attribute to output '%s'", outName),
+				Fix:          descriptionFix(block),
+			})
 		}
 	}
 
+	// Check variable types, nullability and validations
+	issues = append(issues, variableQualityIssues(config)...)
+
+	// Check for key resource attributes that are not exposed as outputs
+	issues = append(issues, suggestedOutputsIssue(config)...)
+
 	return issues
 }
 
@@ -470,54 +737,52 @@ func (v *ModuleValidator) Validate(config *TerraformConfiguration) []ValidationI
 	var issues []ValidationIssue
 
 	// Check module version pinning
-	modulePattern := regexp.MustCompile(`module\s+"([^"]+)"\s+{(?:(?:.|\n)(?!^\}$))*}`)
-	sourcePattern := regexp.MustCompile(`source\s*=\s*"([^"]+)"`)
-	versionPattern := regexp.MustCompile(`version\s*=\s*"([^"]+)"`)
-	for name, content := range config.Files {
-		if strings.HasSuffix(name, ".tf") {
-			modMatches := modulePattern.FindAllStringSubmatch(content, -1)
-			for _, modMatch := range modMatches {
-				modDef := modMatch[0]
-				modName := modMatch[1]
-				sourceMatch := sourcePattern.FindStringSubmatch(modDef)
-				if sourceMatch != nil {
-					source := sourceMatch[1]
-					if strings.Contains(source, "github.com") || strings.Contains(source, "terraform-aws-modules") || 
-					   strings.Contains(source, "registry.terraform.io") {
-						if !versionPattern.MatchString(modDef) {
-							issues = append(issues, ValidationIssue{
-								Message:      fmt.Sprintf("Module '%s' does not specify a version", modName),
-								Severity:     SeverityWarning,
-								Category:     CategoryMaintenance,
-								File:         name,
-								BestPractice: "Always pin module versions for consistency and stability",
-								Suggestion:   fmt.Sprintf("Add version constraint to module '%s'", modName),
-							})
-						}
-					}
-				}
-			}
+	for _, block := range config.Blocks("module") {
+		modName := block.Label(0)
+		sourceAttr, ok := block.Attributes["source"]
+		if !ok {
+			continue
+		}
+		source, known := sourceAttr.StringValue()
+		if !known {
+			continue
 		}
-	}
 
-	// Check for local modules
-	localModulesDir := hasDir(config, "modules")
-	moduleUsage := false
-	for _, content := range config.Files {
-		if strings.Contains(content, "module ") {
-			moduleUsage = true
-			break
+		switch sourceType := ClassifyModuleSource(source); sourceType {
+		case ModuleSourceRegistry:
+			if _, ok := block.Attributes["version"]; ok {
+				continue
+			}
+			issues = append(issues, ValidationIssue{
+				Message:      fmt.Sprintf("Module '%s' does not specify a version", modName),
+				RuleID:       "module-version",
+				Severity:     SeverityWarning,
+				Category:     CategoryMaintenance,
+				File:         block.File,
+				Line:         block.Line,
+				BestPractice: "Always pin module versions for consistency and stability",
+				Suggestion:   fmt.Sprintf("Add version constraint to module '%s'", modName),
+			})
+		case ModuleSourceGit, ModuleSourceHTTP:
+			if sourcePinned(source, sourceType) {
+				continue
+			}
+			suggestion := fmt.Sprintf("Add ?ref=<tag or commit> to the source of module '%s'", modName)
+			if sourceType == ModuleSourceHTTP {
+				suggestion = fmt.Sprintf("Point module '%s' at a versioned archive", modName)
+			}
+			issues = append(issues, ValidationIssue{
+				Message:      fmt.Sprintf("Module '%s' uses an unpinned %s source '%s'", modName, sourceType, source),
+				RuleID:       "module-source-unpinned",
+				Severity:     SeverityWarning,
+				Category:     CategoryMaintenance,
+				File:         sourceAttr.File,
+				Line:         sourceAttr.Line,
+				BestPractice: "Pin git sources to a tag or commit and http sources to a versioned archive",
+				Suggestion:   suggestion,
+			})
 		}
 	}
-	if localModulesDir && !moduleUsage {
-		issues = append(issues, ValidationIssue{
-			Message:      "Local modules directory exists but modules are not used",
-			Severity:     SeverityInfo,
-			Category:     CategoryMaintenance,
-			BestPractice: "Use a modular approach for complex configurations",
-			Suggestion:   "Consider using the modules in your configuration for better organization",
-		})
-	}
 
 	return issues
 }
@@ -534,61 +799,29 @@ func (v *ResourceValidator) Name() string {
 func (v *ResourceValidator) Validate(config *TerraformConfiguration) []ValidationIssue {
 	var issues []ValidationIssue
 
-	// Check for missing tags on resources
-	tagPattern := regexp.MustCompile(`resource\s+"(aws_[^"]+)"\s+"([^"]+)"\s+{(?:(?:.|\n)(?!^\}$))*}`)
-	tagsAttrPattern := regexp.MustCompile(`tags\s*=\s*`)
-	for name, content := range config.Files {
-		if strings.HasSuffix(name, ".tf") {
-			resMatches := tagPattern.FindAllStringSubmatch(content, -1)
-			for _, resMatch := range resMatches {
-				resType := resMatch[1]
-				resName := resMatch[2]
-				resDef := resMatch[0]
-
-				// Skip resources that don't support tags
-				if strings.Contains(resType, "aws_iam_role_policy") || 
-				   strings.Contains(resType, "aws_iam_policy") ||
-				   strings.Contains(resType, "aws_route") {
-					continue
-				}
-
-				// Check for resources that typically should have tags
-				if (strings.HasPrefix(resType, "aws_") || 
-					strings.HasPrefix(resType, "azurerm_") || 
-					strings.HasPrefix(resType, "google_")) && 
-					!tagsAttrPattern.MatchString(resDef) {
-					issues = append(issues, ValidationIssue{
-						Message:      fmt.Sprintf("Resource '%s' of type '%s' is missing tags", resName, resType),
-						Severity:     SeverityInfo,
-						Category:     CategoryMaintenance,
-						File:         name,
-						BestPractice: "Apply consistent tagging to all resources for better management",
-						Suggestion:   fmt.Sprintf("Add tags to resource '%s'", resName),
-					})
-				}
-			}
-		}
-	}
-
 	// Check for resource count vs for_each
-	countPattern := regexp.MustCompile(`resource\s+"([^"]+)"\s+"([^"]+)"\s+{(?:(?:.|\n)(?!^\}$))*\s+count\s*=\s*length\(([^)]+)\)`)
-	for name, content := range config.Files {
-		if strings.HasSuffix(name, ".tf") {
-			countMatches := countPattern.FindAllStringSubmatch(content, -1)
-			for _, countMatch := range countMatches {
-				resType := countMatch[1]
-				resName := countMatch[2]
-				countVar := countMatch[3]
-				issues = append(issues, ValidationIssue{
-					Message:      fmt.Sprintf("Resource '%s' uses count with length(%s), consider using for_each", resName, countVar),
-					Severity:     SeverityInfo,
-					Category:     CategoryMaintenance,
-					File:         name,
-					BestPractice: "Use for_each instead of count when iterating over complex values",
-					Suggestion:   fmt.Sprintf("Change 'count = length(%s)' to 'for_each = toset(%s)'", countVar, countVar),
-				})
-			}
+	for _, block := range config.Blocks("resource") {
+		countAttr, ok := block.Attributes["count"]
+		if !ok {
+			continue
+		}
+		call, ok := countAttr.Expr.(*hclsyntax.FunctionCallExpr)
+		if !ok || call.Name != "length" || len(call.Args) != 1 {
+			continue
 		}
+		resName := block.Label(1)
+		countVar := strings.TrimSuffix(strings.TrimPrefix(countAttr.Source, "length("), ")")
+		issues = append(issues, ValidationIssue{
+			Message:      fmt.Sprintf("Resource '%s' uses count with length(%s), consider using for_each", resName, countVar),
+			RuleID:       "prefer-for-each",
+			Severity:     SeverityInfo,
+			Category:     CategoryMaintenance,
+			File:         block.File,
+			Line:         countAttr.Line,
+			BestPractice: "Use for_each instead of count when iterating over complex values",
+			Suggestion:   fmt.Sprintf("Change 'count = length(%s)' to 'for_each = toset(%s)', replace %s[count.index] with each.value, update references to indexed instances and add moved blocks from %s[<index>] to %s[<key>] so existing instances are not recreated", countVar, countVar, countVar, block.Address(), block.Address()),
+			Fix:          countToForEachFix(config, block, countAttr),
+		})
 	}
 
 	return issues
@@ -631,159 +864,124 @@ func hasReadmeMD(config *TerraformConfiguration) bool {
 	return ok
 }
 
-func hasDir(config *TerraformConfiguration, dir string) bool {
-	for name := range config.Files {
-		if strings.HasPrefix(name, dir+"/") {
+func hasDescription(block *Block) bool {
+	attr, ok := block.Attributes["description"]
+	if !ok {
+		return false
+	}
+	value, known := attr.StringValue()
+	return !known || value != ""
+}
+
+func descriptionFix(block *Block) *Fix {
+	description := fmt.Sprintf("%q", fmt.Sprintf("The %s", humanize(block.Label(0))))
+	if attr, ok := block.Attributes["description"]; ok {
+		return &Fix{
+			Description: fmt.Sprintf("Fill in the description of %s '%s'", block.Type, block.Label(0)),
+			Edits:       []TextEdit{replaceRange(attr.Range, "description = "+description)},
+		}
+	}
+	return addAttributeFix(block, "description", description,
+		fmt.Sprintf("Add a description to %s '%s'", block.Type, block.Label(0)))
+}
+
+func sensitiveFix(block *Block) *Fix {
+	if attr, ok := block.Attributes["sensitive"]; ok {
+		return &Fix{
+			Description: fmt.Sprintf("Set sensitive = true on variable '%s'", block.Label(0)),
+			Edits:       []TextEdit{replaceRange(attr.Range, "sensitive = true")},
+		}
+	}
+	return addAttributeFix(block, "sensitive", "true",
+		fmt.Sprintf("Add sensitive = true to variable '%s'", block.Label(0)))
+}
+
+func hasVersionConstraints(config *TerraformConfiguration) bool {
+	for _, block := range config.Blocks("terraform") {
+		if _, ok := block.Attributes["required_version"]; ok {
+			return true
+		}
+		if len(block.NestedBlocks("required_providers")) > 0 {
 			return true
 		}
 	}
 	return false
 }
 
-// Generates a simple main.tf file
-func generateMainTF(config *TerraformConfiguration) string {
+// Generates a versions.tf file for the providers used by the configuration
+func generateVersionsTF(config *TerraformConfiguration) string {
 	var sb strings.Builder
 
-	sb.WriteString(`# Main configuration for Terraform
-# Contains the primary resources defined in this module
-
-provider "aws" {
-  region = var.region
-}
-
-# Example resource:
-# resource "aws_s3_bucket" "example" {
-#   bucket = var.bucket_name
-#   tags   = var.tags
-# }
-
-# Example module usage:
-# module "vpc" {
-#   source  = "terraform-aws-modules/vpc/aws"
-#   version = "3.14.0"
-#
-#   name = var.vpc_name
-#   cidr = var.vpc_cidr
-#
-#   azs             = var.availability_zones
-#   private_subnets = var.private_subnets
-#   public_subnets  = var.public_subnets
-#
-#   tags = var.tags
-# }
-`)
+	sb.WriteString(`# Terraform and provider version constraints
 
-	return sb.String()
-}
+terraform {
+  required_version = ">= 1.0.0"
+`)
 
-// Generates a simple variables.tf file
-func generateVariablesTF(config *TerraformConfiguration) string {
-	var sb strings.Builder
+	providers := usedProviders(config)
+	if len(providers) > 0 {
+		sb.WriteString("\n  required_providers {\n")
+		for _, provider := range providers {
+			sb.WriteString(fmt.Sprintf("    %s = {\n", provider))
+			sb.WriteString(fmt.Sprintf("      source  = \"hashicorp/%s\"\n", provider))
+			sb.WriteString("    }\n")
+		}
+		sb.WriteString("  }\n")
+	}
 
-	sb.WriteString(`# Input variables for the module
+	sb.WriteString("}\n")
 
-variable "region" {
-  description = "AWS region where resources will be created"
-  type        = string
-  default     = "us-west-2"
+	return sb.String()
 }
 
-# Example variable:
-# variable "bucket_name" {
-#   description = "Name of the S3 bucket to create"
-#   type        = string
-# }
-
-# Example variable with validation:
-# variable "environment" {
-#   description = "Environment where resources will be deployed"
-#   type        = string
-#   validation {
-#     condition     = contains(["dev", "staging", "prod"], var.environment)
-#     error_message = "Environment must be one of: dev, staging, prod."
-#   }
-# }
+// usedProviders returns the sorted local names of the providers used by
+// resources, data sources and provider blocks
+func usedProviders(config *TerraformConfiguration) []string {
+	seen := make(map[string]bool)
+	for _, block := range config.Blocks("") {
+		switch block.Type {
+		case "resource", "data":
+			if i := strings.Index(block.Label(0), "_"); i > 0 {
+				seen[block.Label(0)[:i]] = true
+			}
+		case "provider":
+			seen[block.Label(0)] = true
+		}
+	}
 
-variable "tags" {
-  description = "A map of tags to apply to all resources"
-  type        = map(string)
-  default     = {}
+	providers := make([]string, 0, len(seen))
+	for provider := range seen {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+	return providers
 }
-`)
 
-	return sb.String()
+// Generates a variables.tf file declaring the variables the root module
+// references but does not declare, or an empty string if there are none
+func generateVariablesTF(config *TerraformConfiguration) string {
+	root := config.ModuleTree().Root.Config
+	names := undeclaredVariables(root.ReferenceGraph())
+	if len(names) == 0 {
+		return ""
+	}
+	return "# Input variables for the module\n\n" + variableDeclarations(root, names)
 }
 
-// Generates a simple outputs.tf file
+// Generates an outputs.tf file exposing the key attributes of the resources
+// of the root module, or an empty string if there are none
 func generateOutputsTF(config *TerraformConfiguration) string {
-	var sb strings.Builder
-
-	sb.WriteString(`# Output values from the module
-
-# Example output:
-# output "bucket_id" {
-#   description = "The ID of the S3 bucket"
-#   value       = aws_s3_bucket.example.id
-# }
-
-# Example output with sensitive data:
-# output "database_password" {
-#   description = "The password for the database"
-#   value       = aws_db_instance.example.password
-#   sensitive   = true
-# }
-`)
-
-	return sb.String()
+	suggestions := config.ModuleTree().Root.Config.SuggestedOutputs()
+	if len(suggestions) == 0 {
+		return ""
+	}
+	return "# Output values from the module\n\n" + outputDeclarations(suggestions)
 }
 
-// Generates a simple README.md file
+// Generates a README.md file documenting the inputs, outputs and resources
+// of the module
 func generateReadmeMD(config *TerraformConfiguration) string {
-	var sb strings.Builder
-
-	sb.WriteString(`# Terraform Module
-
-This module provisions AWS resources following best practices.
-
-## Usage
-
-```hcl
-module "example" {
-  source = "./path/to/module"
-
-  region = "us-west-2"
-  
-  tags = {
-    Environment = "production"
-    Project     = "example"
-  }
-}
-```
-
-## Requirements
-
-| Name | Version |
-|------|---------|
-| terraform | >= 1.0.0 |
-| aws | >= 4.0.0 |
-
-## Inputs
-
-| Name | Description | Type | Default | Required |
-|------|-------------|------|---------|:--------:|
-| region | AWS region where resources will be created | `string` | `"us-west-2"` | no |
-| tags | A map of tags to apply to all resources | `map(string)` | `{}` | no |
-
-## Outputs
-
-No outputs.
-
-## Resources
-
-No resources.
-`)
-
-	return sb.String()
+	return GenerateReadme(config)
 }
 
 // ParseTerraformConfiguration parses a Terraform configuration from a string map
@@ -835,24 +1033,25 @@ func FormatValidationResult(result *ValidationResult) string {
 	return sb.String()
 }
 
-// FormatImprovementSuggestions formats improvement suggestions as a string
-func FormatImprovementSuggestions(improvements map[string]string) string {
+// FormatImprovementSuggestions formats improvement suggestions as a string,
+// showing each file as a diff against the original configuration
+func FormatImprovementSuggestions(original, improvements map[string]string) string {
 	var sb strings.Builder
 
-	sb.WriteString(fmt.Sprintf("Suggested improvements for %d files:\n\n", len(improvements)))
+	diffs := ImprovementDiffs(original, improvements)
+	sb.WriteString(fmt.Sprintf("Suggested improvements for %d files:\n\n", len(diffs)))
 
-	for file, content := range improvements {
-		sb.WriteString(fmt.Sprintf("File: %s\n", file))
-		
-		// Limit the content length for display
-		preview := content
-		if len(content) > 500 {
-			preview = content[:500] + "...\n(content truncated for display)"
-		}
-		
-		sb.WriteString("```\n")
-		sb.WriteString(preview)
-		sb.WriteString("\n```\n\n")
+	names := make([]string, 0, len(diffs))
+	for name := range diffs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		sb.WriteString(fmt.Sprintf("File: %s\n", name))
+		sb.WriteString("```diff\n")
+		sb.WriteString(diffs[name])
+		sb.WriteString("```\n\n")
 	}
 
 	return sb.String()
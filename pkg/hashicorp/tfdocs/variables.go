@@ -0,0 +1,214 @@
+// pkg/hashicorp/tfdocs/variables.go
+package tfdocs
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// variablesPracticeID is the best practice the variable quality rules refer to
+const variablesPracticeID = "variables-documentation"
+
+// variablesHelpURI documents input variables
+const variablesHelpURI = "https://developer.hashicorp.com/terraform/language/values/variables"
+
+// anyTypePattern matches type constraints accepting values of any type
+var anyTypePattern = regexp.MustCompile(`\bany\b`)
+
+// variableQualityIssues reports variables without a type, with types that
+// accept any value, accepting null despite a default, or without a
+// validation for values of a recognizable shape
+func variableQualityIssues(config *TerraformConfiguration) []ValidationIssue {
+	var issues []ValidationIssue
+
+	usages := variableUsages(config)
+	for _, block := range config.Blocks("variable") {
+		name := block.Label(0)
+		usage := usages[name]
+
+		typeAttr, hasType := block.Attributes["type"]
+		if !hasType {
+			inferred := firstType(usage.usageType(), defaultType(block), argumentType(name), "string")
+			issues = append(issues, variableIssue(block, "variable-type", SeverityWarning,
+				fmt.Sprintf("Variable '%s' has no type", name),
+				"Declare an explicit type for all variables",
+				fmt.Sprintf("Add type = %s to variable '%s'", inferred, name),
+				typeFix(block, inferred)))
+		} else if declared := strings.Join(strings.Fields(typeAttr.Source), ""); anyTypePattern.MatchString(declared) {
+			if inferred := usage.usageType(); inferred != "" && !anyTypePattern.MatchString(inferred) {
+				issues = append(issues, variableIssue(block, "variable-any-type", SeverityInfo,
+					fmt.Sprintf("Variable '%s' has type %s, but its usage implies %s", name, declared, inferred),
+					"Prefer specific types over any so invalid values are rejected early",
+					fmt.Sprintf("Change the type of variable '%s' to %s", name, inferred),
+					&Fix{
+						Description: fmt.Sprintf("Change the type of variable '%s' to %s", name, inferred),
+						Edits:       []TextEdit{replaceRange(typeAttr.Expr.Range(), inferred)},
+					}))
+			} else {
+				issues = append(issues, variableIssue(block, "variable-any-type", SeverityInfo,
+					fmt.Sprintf("Variable '%s' has type %s, which accepts values of any type", name, declared),
+					"Prefer specific types over any so invalid values are rejected early",
+					fmt.Sprintf("Declare the type of the values variable '%s' accepts", name),
+					nil))
+			}
+		}
+
+		// Callers passing null override the default unless nullable = false
+		defaultAttr, hasDefault := block.Attributes["default"]
+		_, hasNullable := block.Attributes["nullable"]
+		if hasDefault && !hasNullable {
+			if value, ok := defaultAttr.Value(); ok && !value.IsNull() {
+				indent := strings.Repeat(" ", block.Range.Start.Column-1)
+				issues = append(issues, variableIssue(block, "variable-nullable", SeverityInfo,
+					fmt.Sprintf("Variable '%s' has a default but accepts null, which replaces the default when passed", name),
+					"Set nullable = false on variables with a non-null default",
+					fmt.Sprintf("Add nullable = false to variable '%s'", name),
+					&Fix{
+						Description: fmt.Sprintf("Add nullable = false to variable '%s'", name),
+						Edits:       []TextEdit{insertAt(block.File, defaultAttr.Range.End, "\n"+indent+"  nullable = false")},
+					}))
+			}
+		}
+
+		// Suggest validations for values of a recognizable shape
+		if !hasType || len(block.NestedBlocks("validation")) > 0 {
+			continue
+		}
+		condition, message := suggestValidation(name, strings.Join(strings.Fields(typeAttr.Source), ""), usage)
+		if condition == "" {
+			continue
+		}
+		if hasDefault {
+			if value, ok := defaultAttr.Value(); ok && value.IsNull() {
+				condition = fmt.Sprintf("var.%s == null || %s", name, condition)
+			}
+		}
+		issues = append(issues, variableIssue(block, "variable-validation", SeverityInfo,
+			fmt.Sprintf("Variable '%s' has no validation", name),
+			"Validate the values of variables with a known format or set of allowed values",
+			fmt.Sprintf("Add a validation block with condition = %s to variable '%s'", condition, name),
+			validationFix(block, condition, message)))
+	}
+
+	return issues
+}
+
+// variableIssue builds an issue of the variable quality rules
+func variableIssue(block *Block, ruleID string, severity ValidationSeverity, message, bestPractice, suggestion string, fix *Fix) ValidationIssue {
+	return ValidationIssue{
+		Message:      message,
+		RuleID:       ruleID,
+		Severity:     severity,
+		Category:     CategoryDocumentation,
+		File:         block.File,
+		Line:         block.Line,
+		BestPractice: bestPractice,
+		Suggestion:   suggestion,
+		Fix:          fix,
+		References:   []string{variablesPracticeID},
+	}
+}
+
+// suggestValidation returns the condition and error message of a validation
+// for variables holding CIDR blocks, ARNs or values compared with contains()
+func suggestValidation(name, typ string, usage *variableUsage) (string, string) {
+	label := humanize(name)
+	list := typ == "list(string)" || typ == "set(string)"
+	switch {
+	case usage != nil && len(usage.Enums) > 0 && typ == "string":
+		quoted := make([]string, len(usage.Enums))
+		for i, value := range usage.Enums {
+			quoted[i] = fmt.Sprintf("%q", value)
+		}
+		return fmt.Sprintf("contains([%s], var.%s)", strings.Join(quoted, ", "), name),
+			fmt.Sprintf("The %s must be one of: %s.", label, strings.Join(usage.Enums, ", "))
+	case strings.Contains(name, "cidr") && typ == "string":
+		return fmt.Sprintf("can(cidrhost(var.%s, 0))", name),
+			fmt.Sprintf("The %s must be a valid CIDR block.", label)
+	case strings.Contains(name, "cidr") && list:
+		return fmt.Sprintf("alltrue([for cidr in var.%s : can(cidrhost(cidr, 0))])", name),
+			fmt.Sprintf("All %s must be valid CIDR blocks.", label)
+	case strings.HasSuffix(name, "_arn") && typ == "string":
+		return fmt.Sprintf("can(regex(\"^arn:\", var.%s))", name),
+			fmt.Sprintf("The %s must be an ARN.", label)
+	case strings.HasSuffix(name, "_arns") && list:
+		return fmt.Sprintf("alltrue([for arn in var.%s : can(regex(\"^arn:\", arn))])", name),
+			fmt.Sprintf("All %s must be ARNs.", label)
+	}
+	return "", ""
+}
+
+// defaultType infers the type of a variable from its default value
+func defaultType(block *Block) string {
+	attr, ok := block.Attributes["default"]
+	if !ok {
+		return ""
+	}
+	value, ok := attr.Value()
+	if !ok || value.IsNull() {
+		return ""
+	}
+
+	switch t := value.Type(); {
+	case t == cty.String || t == cty.Number || t == cty.Bool:
+		return t.FriendlyName()
+	case t.IsTupleType() || t.IsListType():
+		if value.LengthInt() > 0 && allStrings(value) {
+			return "list(string)"
+		}
+	case t.IsObjectType() || t.IsMapType():
+		if value.LengthInt() > 0 && allStrings(value) {
+			return "map(string)"
+		}
+	}
+	return ""
+}
+
+// allStrings reports whether every element of a collection is a string
+func allStrings(value cty.Value) bool {
+	for it := value.ElementIterator(); it.Next(); {
+		_, element := it.Element()
+		if element.Type() != cty.String {
+			return false
+		}
+	}
+	return true
+}
+
+// typeFix returns a fix adding a type to a variable, after its description
+// if it has one
+func typeFix(block *Block, typ string) *Fix {
+	description := fmt.Sprintf("Add type = %s to variable '%s'", typ, block.Label(0))
+	if attr, ok := block.Attributes["description"]; ok {
+		indent := strings.Repeat(" ", block.Range.Start.Column-1)
+		return &Fix{
+			Description: description,
+			Edits:       []TextEdit{insertAt(block.File, attr.Range.End, "\n"+indent+"  type = "+typ)},
+		}
+	}
+	return addAttributeFix(block, "type", typ, description)
+}
+
+// validationFix returns a fix adding a validation block at the end of a
+// variable, or nil for variables written on a single line
+func validationFix(block *Block, condition, message string) *Fix {
+	if block.OpenBraceRange.End.Line == block.CloseBraceRange.Start.Line {
+		return nil
+	}
+
+	indent := strings.Repeat(" ", block.Range.Start.Column-1)
+	text := fmt.Sprintf("\n%[1]s  validation {\n%[1]s    condition     = %[2]s\n%[1]s    error_message = %[3]q\n%[1]s  }\n",
+		indent, condition, message)
+
+	// Insert at the start of the line holding the closing brace
+	end := block.CloseBraceRange.Start
+	end.Byte -= end.Column - 1
+	end.Column = 1
+	return &Fix{
+		Description: fmt.Sprintf("Add a validation block to variable '%s'", block.Label(0)),
+		Edits:       []TextEdit{insertAt(block.File, end, text)},
+	}
+}
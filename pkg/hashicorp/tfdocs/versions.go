@@ -0,0 +1,630 @@
+// pkg/hashicorp/tfdocs/versions.go
+package tfdocs
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Version is a semantic version such as 1.2.3 or 1.5.0-beta1
+type Version struct {
+	Segments   [3]int
+	Prerelease string
+}
+
+var versionPattern = regexp.MustCompile(`^v?(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+// ParseVersion parses a version. Missing minor and patch segments are zero.
+func ParseVersion(s string) (Version, int, error) {
+	match := versionPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if match == nil {
+		return Version{}, 0, fmt.Errorf("invalid version: %q", s)
+	}
+
+	var version Version
+	segments := 0
+	for i := 0; i < 3; i++ {
+		if match[i+1] == "" {
+			break
+		}
+		n, err := strconv.Atoi(match[i+1])
+		if err != nil {
+			return Version{}, 0, fmt.Errorf("invalid version: %q", s)
+		}
+		version.Segments[i] = n
+		segments++
+	}
+	version.Prerelease = match[4]
+
+	return version, segments, nil
+}
+
+// Compare returns -1, 0 or 1 if the version is lower than, equal to or
+// greater than another version. Prereleases sort before their release.
+func (v Version) Compare(other Version) int {
+	for i := 0; i < 3; i++ {
+		if v.Segments[i] != other.Segments[i] {
+			if v.Segments[i] < other.Segments[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case v.Prerelease == other.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case other.Prerelease == "":
+		return -1
+	case v.Prerelease < other.Prerelease:
+		return -1
+	}
+	return 1
+}
+
+// String returns the version as major.minor.patch
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Segments[0], v.Segments[1], v.Segments[2])
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	return s
+}
+
+// VersionConstraint is a single constraint such as ">= 4.0" or "~> 1.2.0"
+type VersionConstraint struct {
+	Operator string
+	Version  Version
+	// Segments is the number of version segments written in the constraint,
+	// which determines the upper bound of the ~> operator
+	Segments int
+}
+
+var constraintPattern = regexp.MustCompile(`^(=|!=|>=|<=|>|<|~>)?\s*(\S+)$`)
+
+// ParseVersionConstraints parses a Terraform version constraint string, a
+// comma-separated list of constraints that must all be satisfied
+func ParseVersionConstraints(s string) ([]VersionConstraint, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, fmt.Errorf("empty version constraint")
+	}
+
+	var constraints []VersionConstraint
+	for _, part := range strings.Split(s, ",") {
+		match := constraintPattern.FindStringSubmatch(strings.TrimSpace(part))
+		if match == nil {
+			return nil, fmt.Errorf("invalid version constraint: %q", part)
+		}
+		version, segments, err := ParseVersion(match[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid version constraint %q: %w", part, err)
+		}
+		operator := match[1]
+		if operator == "" {
+			operator = "="
+		}
+		constraints = append(constraints, VersionConstraint{Operator: operator, Version: version, Segments: segments})
+	}
+	return constraints, nil
+}
+
+// VersionBound is one end of a version range
+type VersionBound struct {
+	Version   Version
+	Inclusive bool
+}
+
+// VersionRange is the range of versions allowed by a set of constraints. A
+// nil bound is unbounded.
+type VersionRange struct {
+	Lower *VersionBound
+	Upper *VersionBound
+}
+
+// ConstraintRange returns the range of versions allowed by constraints.
+// Exclusions with != do not narrow the range.
+func ConstraintRange(constraints []VersionConstraint) VersionRange {
+	var r VersionRange
+	for _, c := range constraints {
+		switch c.Operator {
+		case "=":
+			r = r.Intersect(VersionRange{
+				Lower: &VersionBound{Version: c.Version, Inclusive: true},
+				Upper: &VersionBound{Version: c.Version, Inclusive: true},
+			})
+		case ">":
+			r = r.Intersect(VersionRange{Lower: &VersionBound{Version: c.Version}})
+		case ">=":
+			r = r.Intersect(VersionRange{Lower: &VersionBound{Version: c.Version, Inclusive: true}})
+		case "<":
+			r = r.Intersect(VersionRange{Upper: &VersionBound{Version: c.Version}})
+		case "<=":
+			r = r.Intersect(VersionRange{Upper: &VersionBound{Version: c.Version, Inclusive: true}})
+		case "~>":
+			// Only the rightmost written segment may increase
+			upper := Version{Segments: c.Version.Segments}
+			bump := c.Segments - 2
+			if bump < 0 {
+				bump = 0
+			}
+			upper.Segments[bump]++
+			for i := bump + 1; i < 3; i++ {
+				upper.Segments[i] = 0
+			}
+			r = r.Intersect(VersionRange{
+				Lower: &VersionBound{Version: c.Version, Inclusive: true},
+				Upper: &VersionBound{Version: upper},
+			})
+		}
+	}
+	return r
+}
+
+// Intersect returns the versions allowed by both ranges
+func (r VersionRange) Intersect(other VersionRange) VersionRange {
+	result := r
+	if other.Lower != nil {
+		if result.Lower == nil {
+			result.Lower = other.Lower
+		} else if cmp := other.Lower.Version.Compare(result.Lower.Version); cmp > 0 || cmp == 0 && !other.Lower.Inclusive {
+			result.Lower = other.Lower
+		}
+	}
+	if other.Upper != nil {
+		if result.Upper == nil {
+			result.Upper = other.Upper
+		} else if cmp := other.Upper.Version.Compare(result.Upper.Version); cmp < 0 || cmp == 0 && !other.Upper.Inclusive {
+			result.Upper = other.Upper
+		}
+	}
+	return result
+}
+
+// Empty reports whether no version satisfies the range
+func (r VersionRange) Empty() bool {
+	if r.Lower == nil || r.Upper == nil {
+		return false
+	}
+	cmp := r.Lower.Version.Compare(r.Upper.Version)
+	return cmp > 0 || cmp == 0 && !(r.Lower.Inclusive && r.Upper.Inclusive)
+}
+
+// Bounded reports whether the range has an upper bound
+func (r VersionRange) Bounded() bool {
+	return r.Upper != nil
+}
+
+// Exact reports whether the range allows exactly one version
+func (r VersionRange) Exact() bool {
+	return r.Lower != nil && r.Upper != nil && r.Lower.Inclusive && r.Upper.Inclusive &&
+		r.Lower.Version.Compare(r.Upper.Version) == 0
+}
+
+// ProviderRequirement is an entry of a required_providers block
+type ProviderRequirement struct {
+	Name    string
+	Source  string
+	Version string
+	File    string
+	Line    int
+}
+
+// Address returns the normalized source address of the provider, defaulting
+// to the hashicorp namespace like Terraform does
+func (p ProviderRequirement) Address() string {
+	source := strings.ToLower(p.Source)
+	if source == "" {
+		source = "hashicorp/" + strings.ToLower(p.Name)
+	}
+	return strings.TrimPrefix(source, "registry.terraform.io/")
+}
+
+// ProviderRequirements returns the required_providers entries of the
+// configuration, ordered by file and line
+func (c *TerraformConfiguration) ProviderRequirements() []ProviderRequirement {
+	var requirements []ProviderRequirement
+	for _, block := range c.Blocks("terraform") {
+		for _, providers := range block.NestedBlocks("required_providers") {
+			for _, attr := range sortedAttributes(providers.Attributes) {
+				requirement := ProviderRequirement{Name: attr.Name, File: attr.File, Line: attr.Line}
+				value, ok := attr.Value()
+				if !ok {
+					continue
+				}
+				if value.Type() == cty.String {
+					// Legacy shorthand: aws = "~> 3.0"
+					requirement.Version = value.AsString()
+				} else if value.Type().IsObjectType() {
+					requirement.Source = objectString(value, "source")
+					requirement.Version = objectString(value, "version")
+				}
+				requirements = append(requirements, requirement)
+			}
+		}
+	}
+	return requirements
+}
+
+// objectString returns a string attribute of an object value
+func objectString(value cty.Value, name string) string {
+	if !value.Type().HasAttribute(name) {
+		return ""
+	}
+	s, _ := ctyPrimitiveString(value.GetAttr(name))
+	return s
+}
+
+// ModuleSourceType is the kind of location a module is installed from
+type ModuleSourceType string
+
+const (
+	ModuleSourceLocal    ModuleSourceType = "local"
+	ModuleSourceRegistry ModuleSourceType = "registry"
+	ModuleSourceGit      ModuleSourceType = "git"
+	ModuleSourceHTTP     ModuleSourceType = "http"
+	ModuleSourceOther    ModuleSourceType = "other"
+)
+
+var (
+	registrySourcePattern = regexp.MustCompile(`^([0-9A-Za-z.-]+\.[a-z]+/)?[0-9A-Za-z_-]+/[0-9A-Za-z_-]+/[0-9a-z]+$`)
+	versionRefPattern     = regexp.MustCompile(`^v?\d+(\.\d+)*([-+][0-9A-Za-z.-]+)?$|^[0-9a-f]{7,40}$`)
+	versionPathPattern    = regexp.MustCompile(`[/_-]v?\d+\.\d+(\.\d+)?([/._-]|$)`)
+)
+
+// ClassifyModuleSource classifies a module source address
+func ClassifyModuleSource(source string) ModuleSourceType {
+	switch {
+	case isLocalSource(source):
+		return ModuleSourceLocal
+	case strings.HasPrefix(source, "git::") || strings.HasPrefix(source, "git@") ||
+		strings.HasPrefix(source, "github.com/") || strings.HasPrefix(source, "bitbucket.org/"):
+		return ModuleSourceGit
+	case strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://"):
+		if strings.HasSuffix(strings.SplitN(source, "?", 2)[0], ".git") {
+			return ModuleSourceGit
+		}
+		return ModuleSourceHTTP
+	case registrySourcePattern.MatchString(source):
+		return ModuleSourceRegistry
+	}
+	return ModuleSourceOther
+}
+
+// sourceRef returns the ref query parameter of a git source
+func sourceRef(source string) string {
+	parts := strings.SplitN(source, "?", 2)
+	if len(parts) < 2 {
+		return ""
+	}
+	query, err := url.ParseQuery(parts[1])
+	if err != nil {
+		return ""
+	}
+	return query.Get("ref")
+}
+
+// sourcePinned reports whether a git or http source refers to a fixed
+// revision: a git ref that is a version tag or commit SHA, or an archive URL
+// containing a version
+func sourcePinned(source string, sourceType ModuleSourceType) bool {
+	switch sourceType {
+	case ModuleSourceGit:
+		return versionRefPattern.MatchString(sourceRef(source))
+	case ModuleSourceHTTP:
+		if ref := sourceRef(source); ref != "" {
+			return versionRefPattern.MatchString(ref)
+		}
+		return versionPathPattern.MatchString(strings.SplitN(source, "?", 2)[0])
+	}
+	return true
+}
+
+// versionedConstraint is a parsed constraint together with where it is declared
+type versionedConstraint struct {
+	subject string
+	value   string
+	rng     VersionRange
+	file    string
+	line    int
+}
+
+// VersionValidator validates Terraform, provider and module version
+// constraints
+type VersionValidator struct{}
+
+// Name returns the name of the validator
+func (v *VersionValidator) Name() string {
+	return "VersionValidator"
+}
+
+// Validate validates the version constraints of a Terraform configuration
+func (v *VersionValidator) Validate(config *TerraformConfiguration) []ValidationIssue {
+	var issues []ValidationIssue
+
+	hasTerraformFiles := false
+	for name := range config.Files {
+		if strings.HasSuffix(name, ".tf") {
+			hasTerraformFiles = true
+			break
+		}
+	}
+	if !hasTerraformFiles {
+		return issues
+	}
+
+	// Check the Terraform version constraint
+	var requiredVersions []versionedConstraint
+	for _, block := range config.Blocks("terraform") {
+		attr, ok := block.Attributes["required_version"]
+		if !ok {
+			continue
+		}
+		value, known := attr.StringValue()
+		if !known {
+			continue
+		}
+		constraint, issue := parseConstraintIssue("Terraform", value, attr.File, attr.Line)
+		if issue != nil {
+			issues = append(issues, *issue)
+			continue
+		}
+		requiredVersions = append(requiredVersions, constraint)
+		if constraint.rng.Exact() {
+			issues = append(issues, exactVersionIssue(constraint))
+		}
+	}
+	if len(requiredVersions) == 0 {
+		issues = append(issues, ValidationIssue{
+			Message:      "Configuration does not declare a required_version",
+			RuleID:       "required-version",
+			Severity:     SeverityWarning,
+			Category:     CategoryMaintenance,
+			File:         requiredVersionFile(config),
+			Line:         1,
+			BestPractice: "Declare the Terraform versions a module supports with required_version",
+			Suggestion:   "Add required_version = \"~> 1.0\" to the terraform block in versions.tf",
+		})
+	}
+	issues = append(issues, conflictIssues(requiredVersions)...)
+
+	// Check the provider version constraints
+	byProvider := make(map[string][]versionedConstraint)
+	var providers []string
+	for _, requirement := range config.ProviderRequirements() {
+		subject := fmt.Sprintf("Provider '%s'", requirement.Name)
+		if requirement.Version == "" {
+			issues = append(issues, ValidationIssue{
+				Message:      fmt.Sprintf("%s has no version constraint", subject),
+				RuleID:       "version-unbounded",
+				Severity:     SeverityWarning,
+				Category:     CategoryMaintenance,
+				File:         requirement.File,
+				Line:         requirement.Line,
+				BestPractice: "Constrain provider versions with a lower and an upper bound",
+				Suggestion:   fmt.Sprintf("Add a version such as \"~> 5.0\" to provider '%s'", requirement.Name),
+			})
+			continue
+		}
+
+		constraint, issue := parseConstraintIssue(subject, requirement.Version, requirement.File, requirement.Line)
+		if issue != nil {
+			issues = append(issues, *issue)
+			continue
+		}
+		switch {
+		case constraint.rng.Exact():
+			issues = append(issues, exactVersionIssue(constraint))
+		case !constraint.rng.Bounded():
+			issues = append(issues, unboundedVersionIssue(constraint))
+		}
+
+		address := requirement.Address()
+		if _, ok := byProvider[address]; !ok {
+			providers = append(providers, address)
+		}
+		byProvider[address] = append(byProvider[address], constraint)
+	}
+	for _, provider := range providers {
+		issues = append(issues, conflictIssues(byProvider[provider])...)
+	}
+
+	// Check the module version constraints
+	for _, block := range config.Blocks("module") {
+		attr, ok := block.Attributes["version"]
+		if !ok {
+			continue
+		}
+		value, known := attr.StringValue()
+		if !known {
+			continue
+		}
+		constraint, issue := parseConstraintIssue(fmt.Sprintf("Module '%s'", block.Label(0)), value, attr.File, attr.Line)
+		if issue != nil {
+			issues = append(issues, *issue)
+			continue
+		}
+		if !constraint.rng.Bounded() {
+			issues = append(issues, unboundedVersionIssue(constraint))
+		}
+	}
+
+	return issues
+}
+
+// requiredVersionFile returns the file a missing required_version belongs in
+func requiredVersionFile(config *TerraformConfiguration) string {
+	for _, name := range []string{"versions.tf", "terraform.tf", "main.tf"} {
+		if _, ok := config.Files[name]; ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// parseConstraintIssue parses a constraint, returning an issue if it is invalid
+func parseConstraintIssue(subject, value, file string, line int) (versionedConstraint, *ValidationIssue) {
+	constraints, err := ParseVersionConstraints(value)
+	if err != nil {
+		return versionedConstraint{}, &ValidationIssue{
+			Message:      fmt.Sprintf("%s has an invalid version constraint '%s': %v", subject, value, err),
+			RuleID:       "version-constraint-syntax",
+			Severity:     SeverityError,
+			Category:     CategoryStructure,
+			File:         file,
+			Line:         line,
+			BestPractice: "Write version constraints in Terraform constraint syntax, e.g. \"~> 5.0\" or \">= 4.0, < 6.0\"",
+			Suggestion:   "Fix the version constraint",
+		}
+	}
+	return versionedConstraint{subject: subject, value: value, rng: ConstraintRange(constraints), file: file, line: line}, nil
+}
+
+func unboundedVersionIssue(constraint versionedConstraint) ValidationIssue {
+	lower := constraint.rng.Lower.versionOrZero()
+	return ValidationIssue{
+		Message:      fmt.Sprintf("%s version constraint '%s' has no upper bound", constraint.subject, constraint.value),
+		RuleID:       "version-unbounded",
+		Severity:     SeverityWarning,
+		Category:     CategoryMaintenance,
+		File:         constraint.file,
+		Line:         constraint.line,
+		BestPractice: "Constrain versions with an upper bound so major releases are adopted deliberately",
+		Suggestion:   fmt.Sprintf("Use a pessimistic constraint such as \"~> %d.%d\"", lower.Segments[0], lower.Segments[1]),
+	}
+}
+
+func exactVersionIssue(constraint versionedConstraint) ValidationIssue {
+	version := constraint.rng.Lower.Version
+	return ValidationIssue{
+		Message:      fmt.Sprintf("%s is pinned to exactly %s", constraint.subject, version),
+		RuleID:       "version-exact",
+		Severity:     SeverityInfo,
+		Category:     CategoryMaintenance,
+		File:         constraint.file,
+		Line:         constraint.line,
+		BestPractice: "Avoid exact version pins so patch releases can be adopted; the lock file records the selected version",
+		Suggestion:   fmt.Sprintf("Use \"~> %d.%d.%d\" to allow patch releases", version.Segments[0], version.Segments[1], version.Segments[2]),
+	}
+}
+
+// versionOrZero returns the version of a bound, or 0.0.0 for a missing bound
+func (b *VersionBound) versionOrZero() Version {
+	if b == nil {
+		return Version{}
+	}
+	return b.Version
+}
+
+// conflictIssues reports constraints on the same subject that no version
+// satisfies together. Each constraint is reported once, at the constraint
+// that makes the combined range empty.
+func conflictIssues(constraints []versionedConstraint) []ValidationIssue {
+	var issues []ValidationIssue
+	if len(constraints) < 2 {
+		return issues
+	}
+
+	combined := constraints[0].rng
+	for i := 1; i < len(constraints); i++ {
+		next := combined.Intersect(constraints[i].rng)
+		if !next.Empty() {
+			combined = next
+			continue
+		}
+
+		var others []string
+		for _, previous := range constraints[:i] {
+			others = append(others, fmt.Sprintf("'%s' in %s:%d", previous.value, previous.file, previous.line))
+		}
+		issues = append(issues, ValidationIssue{
+			Message:      fmt.Sprintf("%s version constraint '%s' conflicts with %s", constraints[i].subject, constraints[i].value, strings.Join(others, ", ")),
+			RuleID:       "version-conflicts",
+			Severity:     SeverityError,
+			Category:     CategoryStructure,
+			File:         constraints[i].file,
+			Line:         constraints[i].line,
+			BestPractice: "Version constraints on the same provider must allow at least one common version",
+			Suggestion:   "Align the version constraints so they overlap",
+		})
+	}
+	return issues
+}
+
+// versionConflictIssues reports provider and Terraform version constraints
+// of different modules in a tree that no version satisfies together
+func versionConflictIssues(tree *ModuleTree) []ValidationIssue {
+	byProvider := make(map[string][]versionedConstraint)
+	var subjects []string
+	add := func(key string, constraint versionedConstraint) {
+		if _, ok := byProvider[key]; !ok {
+			subjects = append(subjects, key)
+		}
+		byProvider[key] = append(byProvider[key], constraint)
+	}
+
+	for _, module := range tree.Modules {
+		// Constraints within a module are checked by the VersionValidator, so
+		// each module contributes its combined range
+		ranges := make(map[string]*versionedConstraint)
+		var keys []string
+		merge := func(key string, constraint versionedConstraint) {
+			if existing, ok := ranges[key]; ok {
+				existing.rng = existing.rng.Intersect(constraint.rng)
+				return
+			}
+			constraint.file = module.FilePath(constraint.file)
+			ranges[key] = &constraint
+			keys = append(keys, key)
+		}
+
+		for _, block := range module.Config.Blocks("terraform") {
+			if attr, ok := block.Attributes["required_version"]; ok {
+				if value, known := attr.StringValue(); known {
+					if constraint, issue := parseConstraintIssue("Terraform", value, attr.File, attr.Line); issue == nil {
+						merge("terraform", constraint)
+					}
+				}
+			}
+		}
+		for _, requirement := range module.Config.ProviderRequirements() {
+			if requirement.Version == "" {
+				continue
+			}
+			subject := fmt.Sprintf("Provider '%s'", requirement.Address())
+			if constraint, issue := parseConstraintIssue(subject, requirement.Version, requirement.File, requirement.Line); issue == nil {
+				merge(requirement.Address(), constraint)
+			}
+		}
+
+		for _, key := range keys {
+			if !ranges[key].rng.Empty() {
+				add(key, *ranges[key])
+			}
+		}
+	}
+
+	sort.Strings(subjects)
+	var issues []ValidationIssue
+	for _, subject := range subjects {
+		issues = append(issues, conflictIssues(byProvider[subject])...)
+	}
+	return issues
+}
+
+// DescribeRules returns the rules checked by the validator
+func (v *VersionValidator) DescribeRules() []RuleMetadata {
+	return []RuleMetadata{
+		{ID: "required-version", Name: "RequiredVersion", Description: "Declare the Terraform versions a module supports with required_version", Severity: SeverityWarning, Category: CategoryMaintenance},
+		{ID: "version-unbounded", Name: "VersionUnbounded", Description: "Constrain provider and module versions with an upper bound", Severity: SeverityWarning, Category: CategoryMaintenance},
+		{ID: "version-exact", Name: "VersionExact", Description: "Avoid exact Terraform and provider version pins", Severity: SeverityInfo, Category: CategoryMaintenance},
+		{ID: "version-conflicts", Name: "VersionConflicts", Description: "Version constraints on the same provider must allow at least one common version", Severity: SeverityError, Category: CategoryStructure},
+		{ID: "version-constraint-syntax", Name: "VersionConstraintSyntax", Description: "Write version constraints in Terraform constraint syntax", Severity: SeverityError, Category: CategoryStructure},
+	}
+}
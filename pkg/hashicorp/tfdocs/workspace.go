@@ -0,0 +1,160 @@
+// pkg/hashicorp/tfdocs/workspace.go
+package tfdocs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrWorkspaceDisabled is returned when a path is resolved without any
+// configured workspace roots
+var ErrWorkspaceDisabled = errors.New("workspace paths are disabled: no workspace roots are configured")
+
+// Workspace resolves paths requested by clients against a set of
+// allow-listed root directories. Resolved paths never leave a root, neither
+// through .. segments nor through symbolic links.
+type Workspace struct {
+	roots []string
+}
+
+// NewWorkspace creates a workspace from a list of root directories. Each root
+// must exist and is resolved to its real path.
+func NewWorkspace(roots []string) (*Workspace, error) {
+	workspace := &Workspace{}
+	for _, root := range roots {
+		if root == "" {
+			continue
+		}
+
+		abs, err := filepath.Abs(root)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve workspace root %s: %w", root, err)
+		}
+		real, err := filepath.EvalSymlinks(abs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve workspace root %s: %w", root, err)
+		}
+		info, err := os.Stat(real)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read workspace root %s: %w", root, err)
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("workspace root %s is not a directory", root)
+		}
+
+		workspace.roots = append(workspace.roots, real)
+	}
+	return workspace, nil
+}
+
+// Enabled reports whether any workspace roots are configured
+func (w *Workspace) Enabled() bool {
+	return w != nil && len(w.roots) > 0
+}
+
+// Roots returns the resolved workspace roots
+func (w *Workspace) Roots() []string {
+	if w == nil {
+		return nil
+	}
+	return w.roots
+}
+
+// Resolve returns the real path of a directory inside a workspace root.
+// Relative paths are resolved against the first root.
+func (w *Workspace) Resolve(path string) (string, error) {
+	real, info, err := w.resolve(path)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("path %s is not a directory", path)
+	}
+	return real, nil
+}
+
+// ResolveFile returns the real path of a regular file inside a workspace
+// root, such as a plan or state file
+func (w *Workspace) ResolveFile(path string) (string, error) {
+	real, info, err := w.resolve(path)
+	if err != nil {
+		return "", err
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("path %s is not a file", path)
+	}
+	return real, nil
+}
+
+// resolve returns the real path and file info of a path inside a workspace root
+func (w *Workspace) resolve(path string) (string, os.FileInfo, error) {
+	if !w.Enabled() {
+		return "", nil, ErrWorkspaceDisabled
+	}
+	if path == "" {
+		return "", nil, fmt.Errorf("path must not be empty")
+	}
+	if strings.ContainsRune(path, 0) {
+		return "", nil, fmt.Errorf("path contains invalid characters")
+	}
+
+	requested := path
+	if !filepath.IsAbs(requested) {
+		requested = filepath.Join(w.roots[0], requested)
+	}
+	// Resolve the links of the existing part of the path the roots were
+	// resolved with, e.g. /tmp to /private/tmp on macOS
+	requested = resolveExisting(filepath.Clean(requested))
+
+	// Check the path before and after resolving symbolic links, so neither
+	// .. segments nor links can point outside of the workspace
+	if w.rootOf(requested) == "" {
+		return "", nil, fmt.Errorf("path %s is outside of the workspace roots", path)
+	}
+	real, err := filepath.EvalSymlinks(requested)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to resolve path %s: %w", path, err)
+	}
+	if w.rootOf(real) == "" {
+		return "", nil, fmt.Errorf("path %s resolves outside of the workspace roots", path)
+	}
+
+	info, err := os.Stat(real)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read path %s: %w", path, err)
+	}
+
+	return real, info, nil
+}
+
+// resolveExisting resolves the symbolic links of the longest existing
+// prefix of a clean absolute path and appends the remaining elements
+func resolveExisting(path string) string {
+	rest := ""
+	for prefix := path; ; prefix = filepath.Dir(prefix) {
+		if real, err := filepath.EvalSymlinks(prefix); err == nil {
+			return filepath.Join(real, rest)
+		}
+		if filepath.Dir(prefix) == prefix {
+			return path
+		}
+		rest = filepath.Join(filepath.Base(prefix), rest)
+	}
+}
+
+// rootOf returns the root containing a clean absolute path, or an empty string
+func (w *Workspace) rootOf(path string) string {
+	for _, root := range w.roots {
+		prefix := root
+		if !strings.HasSuffix(prefix, string(filepath.Separator)) {
+			prefix += string(filepath.Separator)
+		}
+		if path == root || strings.HasPrefix(path, prefix) {
+			return root
+		}
+	}
+	return ""
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"strings"
 
 	"terraform-mcp-server/pkg/hashicorp/tfdocs"
@@ -368,18 +369,23 @@ func (t *GetPatternTemplateTool) Execute(ctx context.Context, args json.RawMessa
 // ValidateConfigurationTool is a tool for validating Terraform configurations
 type ValidateConfigurationTool struct {
 	validationEngine *tfdocs.ValidationEngine
+	workspace        *tfdocs.Workspace
 	logger           Logger
 }
 
 // ValidateConfigurationArgs are the arguments for the ValidateConfiguration tool
 type ValidateConfigurationArgs struct {
-	Files map[string]string `json:"files"`
+	Files        map[string]string `json:"files,omitempty"`
+	Path         string            `json:"path,omitempty"`
+	Recursive    *bool             `json:"recursive,omitempty"`
+	OutputFormat string            `json:"outputFormat,omitempty"`
 }
 
 // ValidateConfigurationResult is the result of the ValidateConfiguration tool
 type ValidateConfigurationResult struct {
 	Issues     []tfdocs.ValidationIssue `json:"issues"`
 	Summary    ValidationSummary        `json:"summary"`
+	Format     tfdocs.OutputFormat      `json:"format"`
 	Formatted  string                   `json:"formatted"`
 	Successful bool                     `json:"successful"`
 }
@@ -393,9 +399,10 @@ type ValidationSummary struct {
 }
 
 // NewValidateConfigurationTool creates a new ValidateConfiguration tool
-func NewValidateConfigurationTool(engine *tfdocs.ValidationEngine, logger Logger) *ValidateConfigurationTool {
+func NewValidateConfigurationTool(engine *tfdocs.ValidationEngine, workspace *tfdocs.Workspace, logger Logger) *ValidateConfigurationTool {
 	return &ValidateConfigurationTool{
 		validationEngine: engine,
+		workspace:        workspace,
 		logger:           logger,
 	}
 }
@@ -413,8 +420,23 @@ func (t *ValidateConfigurationTool) Describe() mcp.ToolDescription {
 		Parameters: map[string]mcp.ParameterDescription{
 			"files": {
 				Type:        "object",
-				Description: "Map of filenames to file contents to validate",
-				Required:    true,
+				Description: "Map of filenames to file contents to validate; either files or path is required",
+				Required:    false,
+			},
+			"path": {
+				Type:        "string",
+				Description: "Directory to validate instead of files, relative to the first workspace root or absolute within a workspace root",
+				Required:    false,
+			},
+			"recursive": {
+				Type:        "boolean",
+				Description: "Whether to validate modules in subdirectories of path (default: true)",
+				Required:    false,
+			},
+			"outputFormat": {
+				Type:        "string",
+				Description: "Format of the formatted result: 'text' (default), 'json', 'sarif', 'junit' or 'checkstyle'",
+				Required:    false,
 			},
 		},
 	}
@@ -427,22 +449,42 @@ func (t *ValidateConfigurationTool) Execute(ctx context.Context, args json.RawMe
 		return nil, fmt.Errorf("failed to unmarshal arguments: %w", err)
 	}
 
-	t.logger.Debug("Executing ValidateConfiguration", "fileCount", len(a.Files))
+	t.logger.Debug("Executing ValidateConfiguration", "fileCount", len(a.Files), "path", a.Path, "outputFormat", a.OutputFormat)
 
-	// Parse the configuration
-	config, err := tfdocs.ParseTerraformConfiguration(a.Files)
+	format, err := tfdocs.ParseOutputFormat(a.OutputFormat)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse configuration: %w", err)
+		return nil, err
 	}
 
-	// Validate the configuration
-	result, err := t.validationEngine.ValidateConfiguration(config)
-	if err != nil {
-		return nil, fmt.Errorf("failed to validate configuration: %w", err)
+	var config *tfdocs.TerraformConfiguration
+	var result *tfdocs.ValidationResult
+	switch {
+	case a.Path != "" && len(a.Files) > 0:
+		return nil, fmt.Errorf("files and path cannot be used together")
+	case a.Path != "":
+		config, result, err = t.validatePath(a.Path, a.Recursive == nil || *a.Recursive)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		// Parse the configuration
+		config, err = tfdocs.ParseTerraformConfiguration(a.Files)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse configuration: %w", err)
+		}
+
+		// Validate the configuration
+		result, err = t.validationEngine.ValidateConfiguration(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to validate configuration: %w", err)
+		}
 	}
 
 	// Format the validation result
-	formatted := tfdocs.FormatValidationResult(result)
+	formatted, err := tfdocs.FormatReport(format, result, t.validationEngine.Rules(), config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format validation result: %w", err)
+	}
 
 	// Prepare result
 	validationResult := ValidateConfigurationResult{
@@ -453,6 +495,7 @@ func (t *ValidateConfigurationTool) Execute(ctx context.Context, args json.RawMe
 			WarnCount:  result.WarnCount,
 			InfoCount:  result.InfoCount,
 		},
+		Format:     format,
 		Formatted:  formatted,
 		Successful: result.ErrorCount == 0,
 	}
@@ -460,6 +503,30 @@ func (t *ValidateConfigurationTool) Execute(ctx context.Context, args json.RawMe
 	return json.Marshal(validationResult)
 }
 
+// validatePath validates the modules of a directory inside the workspace
+func (t *ValidateConfigurationTool) validatePath(path string, recursive bool) (*tfdocs.TerraformConfiguration, *tfdocs.ValidationResult, error) {
+	dir, err := t.workspace.Resolve(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	modules, err := tfdocs.DiscoverModules(dir, tfdocs.WithRecursion(recursive))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load modules: %w", err)
+	}
+	if len(modules) == 0 {
+		return nil, nil, fmt.Errorf("no Terraform files found in %s", path)
+	}
+	t.logger.Debug("Discovered modules", "path", dir, "count", len(modules))
+
+	result, config, err := t.validationEngine.ValidateModules(modules)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to validate configuration: %w", err)
+	}
+
+	return config, result, nil
+}
+
 // SuggestImprovementsTool is a tool for suggesting improvements to Terraform configurations
 type SuggestImprovementsTool struct {
 	validationEngine *tfdocs.ValidationEngine
@@ -474,6 +541,8 @@ type SuggestImprovementsArgs struct {
 // SuggestImprovementsResult is the result of the SuggestImprovements tool
 type SuggestImprovementsResult struct {
 	Improvements   map[string]string `json:"improvements"`
+	Diffs          map[string]string `json:"diffs"`
+	Patch          string            `json:"patch"`
 	FormattedGuide string            `json:"formattedGuide"`
 }
 
@@ -527,14 +596,1062 @@ func (t *SuggestImprovementsTool) Execute(ctx context.Context, args json.RawMess
 	}
 
 	// Format the improvement suggestions
-	formattedGuide := tfdocs.FormatImprovementSuggestions(improvements)
+	formattedGuide := tfdocs.FormatImprovementSuggestions(a.Files, improvements)
 
 	// Prepare result
 	result := SuggestImprovementsResult{
 		Improvements:   improvements,
+		Diffs:          tfdocs.ImprovementDiffs(a.Files, improvements),
+		Patch:          tfdocs.CombinedPatch(a.Files, improvements),
 		FormattedGuide: formattedGuide,
 	}
 
 	return json.Marshal(result)
 }
-</content>
+
+// ApplyFixesTool is a tool for applying machine-applicable fixes to Terraform configurations
+type ApplyFixesTool struct {
+	validationEngine *tfdocs.ValidationEngine
+	logger           Logger
+}
+
+// ApplyFixesArgs are the arguments for the ApplyFixes tool
+type ApplyFixesArgs struct {
+	Files map[string]string `json:"files"`
+	Rules []string          `json:"rules,omitempty"`
+}
+
+// ApplyFixesResult is the result of the ApplyFixes tool
+type ApplyFixesResult struct {
+	Files   map[string]string   `json:"files"`
+	Changed []string            `json:"changed"`
+	Applied []tfdocs.AppliedFix `json:"applied"`
+	Skipped []tfdocs.AppliedFix `json:"skipped"`
+}
+
+// NewApplyFixesTool creates a new ApplyFixes tool
+func NewApplyFixesTool(engine *tfdocs.ValidationEngine, logger Logger) *ApplyFixesTool {
+	return &ApplyFixesTool{
+		validationEngine: engine,
+		logger:           logger,
+	}
+}
+
+// Name returns the name of the tool
+func (t *ApplyFixesTool) Name() string {
+	return "ApplyFixes"
+}
+
+// Describe returns a description of the tool
+func (t *ApplyFixesTool) Describe() mcp.ToolDescription {
+	return mcp.ToolDescription{
+		Name:        t.Name(),
+		Description: "Validates Terraform configurations and applies the machine-applicable fixes of the issues found, returning the patched files",
+		Parameters: map[string]mcp.ParameterDescription{
+			"files": {
+				Type:        "object",
+				Description: "Map of filenames to file contents to fix",
+				Required:    true,
+			},
+			"rules": {
+				Type:        "array",
+				Description: "Rule IDs to apply fixes for (e.g., 'variable-description', 'sensitive-variables'); the fixes of errors and warnings are applied if omitted, so info-level fixes need their rule ID",
+				Required:    false,
+			},
+		},
+	}
+}
+
+// Execute executes the tool with the given arguments
+func (t *ApplyFixesTool) Execute(ctx context.Context, args json.RawMessage) (json.RawMessage, error) {
+	var a ApplyFixesArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal arguments: %w", err)
+	}
+
+	t.logger.Debug("Executing ApplyFixes", "fileCount", len(a.Files), "rules", a.Rules)
+
+	// Parse the configuration
+	config, err := tfdocs.ParseTerraformConfiguration(a.Files)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse configuration: %w", err)
+	}
+
+	// Apply the fixes
+	fixes, err := t.validationEngine.ApplyFixes(config, a.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply fixes: %w", err)
+	}
+
+	// Prepare result
+	result := ApplyFixesResult{
+		Files:   fixes.Files,
+		Changed: fixes.Changed,
+		Applied: fixes.Applied,
+		Skipped: fixes.Skipped,
+	}
+
+	return json.Marshal(result)
+}
+
+// FormatConfigurationTool is a tool for formatting Terraform configurations
+type FormatConfigurationTool struct {
+	logger Logger
+}
+
+// FormatConfigurationArgs are the arguments for the FormatConfiguration tool
+type FormatConfigurationArgs struct {
+	Files map[string]string `json:"files"`
+}
+
+// FormatConfigurationResult is the result of the FormatConfiguration tool
+type FormatConfigurationResult struct {
+	Files   map[string]string `json:"files"`
+	Changed []string          `json:"changed"`
+	Diffs   map[string]string `json:"diffs"`
+	Patch   string            `json:"patch"`
+	Errors  map[string]string `json:"errors,omitempty"`
+}
+
+// NewFormatConfigurationTool creates a new FormatConfiguration tool
+func NewFormatConfigurationTool(logger Logger) *FormatConfigurationTool {
+	return &FormatConfigurationTool{
+		logger: logger,
+	}
+}
+
+// Name returns the name of the tool
+func (t *FormatConfigurationTool) Name() string {
+	return "FormatConfiguration"
+}
+
+// Describe returns a description of the tool
+func (t *FormatConfigurationTool) Describe() mcp.ToolDescription {
+	return mcp.ToolDescription{
+		Name:        t.Name(),
+		Description: "Formats Terraform configurations like terraform fmt, returning the formatted files and a diff of the changes",
+		Parameters: map[string]mcp.ParameterDescription{
+			"files": {
+				Type:        "object",
+				Description: "Map of filenames to file contents to format; .tf, .tfvars and .tftest.hcl files are formatted",
+				Required:    true,
+			},
+		},
+	}
+}
+
+// Execute executes the tool with the given arguments
+func (t *FormatConfigurationTool) Execute(ctx context.Context, args json.RawMessage) (json.RawMessage, error) {
+	var a FormatConfigurationArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal arguments: %w", err)
+	}
+
+	t.logger.Debug("Executing FormatConfiguration", "fileCount", len(a.Files))
+
+	// Parse the configuration
+	config, err := tfdocs.ParseTerraformConfiguration(a.Files)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse configuration: %w", err)
+	}
+
+	// Format the files
+	formatted := tfdocs.FormatConfiguration(config)
+
+	// Prepare result
+	result := FormatConfigurationResult{
+		Files:   formatted.Files,
+		Changed: formatted.Changed,
+		Diffs:   formatted.Diffs,
+		Patch:   formatted.Patch,
+		Errors:  formatted.Errors,
+	}
+
+	return json.Marshal(result)
+}
+
+// GenerateDocsTool is a tool for generating the README of Terraform modules
+type GenerateDocsTool struct {
+	logger Logger
+}
+
+// GenerateDocsArgs are the arguments for the GenerateDocs tool
+type GenerateDocsArgs struct {
+	Files map[string]string `json:"files"`
+	Title string            `json:"title,omitempty"`
+}
+
+// GenerateDocsResult is the result of the GenerateDocs tool
+type GenerateDocsResult struct {
+	Readme string             `json:"readme"`
+	Docs   *tfdocs.ModuleDocs `json:"docs"`
+	Diff   string             `json:"diff"`
+}
+
+// NewGenerateDocsTool creates a new GenerateDocs tool
+func NewGenerateDocsTool(logger Logger) *GenerateDocsTool {
+	return &GenerateDocsTool{
+		logger: logger,
+	}
+}
+
+// Name returns the name of the tool
+func (t *GenerateDocsTool) Name() string {
+	return "GenerateDocs"
+}
+
+// Describe returns a description of the tool
+func (t *GenerateDocsTool) Describe() mcp.ToolDescription {
+	return mcp.ToolDescription{
+		Name:        t.Name(),
+		Description: "Generates the Requirements, Providers, Modules, Resources, Inputs and Outputs tables of a module README, updating the section between the BEGIN_TF_DOCS and END_TF_DOCS markers of an existing README.md in place",
+		Parameters: map[string]mcp.ParameterDescription{
+			"files": {
+				Type:        "object",
+				Description: "Map of filenames to file contents of the module, optionally including its README.md",
+				Required:    true,
+			},
+			"title": {
+				Type:        "string",
+				Description: "Title of a newly generated README (default: 'Terraform Module')",
+				Required:    false,
+			},
+		},
+	}
+}
+
+// Execute executes the tool with the given arguments
+func (t *GenerateDocsTool) Execute(ctx context.Context, args json.RawMessage) (json.RawMessage, error) {
+	var a GenerateDocsArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal arguments: %w", err)
+	}
+
+	t.logger.Debug("Executing GenerateDocs", "fileCount", len(a.Files))
+
+	// Parse the configuration
+	config, err := tfdocs.ParseTerraformConfiguration(a.Files)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse configuration: %w", err)
+	}
+
+	// Update the existing README or generate a new one
+	docs := config.ModuleDocs()
+	existing, exists := a.Files["README.md"]
+	var readme string
+	if exists {
+		readme = tfdocs.UpdateReadme(existing, docs)
+	} else {
+		var options []tfdocs.ReadmeOption
+		if a.Title != "" {
+			options = append(options, tfdocs.WithReadmeTitle(a.Title))
+		}
+		readme = tfdocs.GenerateReadme(config, options...)
+	}
+
+	// Prepare result
+	result := GenerateDocsResult{
+		Readme: readme,
+		Docs:   docs,
+		Diff:   tfdocs.UnifiedDiff("README.md", existing, exists, readme),
+	}
+
+	return json.Marshal(result)
+}
+
+// ScaffoldModuleTool is a tool for generating new Terraform modules
+type ScaffoldModuleTool struct {
+	docIndexer       *tfdocs.Indexer
+	patternRepo      *tfdocs.PatternRepository
+	validationEngine *tfdocs.ValidationEngine
+	logger           Logger
+}
+
+// ScaffoldModuleArgs are the arguments for the ScaffoldModule tool
+type ScaffoldModuleArgs struct {
+	Name          string   `json:"name"`
+	Provider      string   `json:"provider"`
+	ResourceTypes []string `json:"resourceTypes"`
+	Examples      bool     `json:"examples,omitempty"`
+	Tests         bool     `json:"tests,omitempty"`
+	CIWorkflow    bool     `json:"ciWorkflow,omitempty"`
+	PreCommit     bool     `json:"preCommit,omitempty"`
+}
+
+// ScaffoldModuleResult is the result of the ScaffoldModule tool
+type ScaffoldModuleResult struct {
+	Files        map[string]string `json:"files"`
+	Patterns     []string          `json:"patterns"`
+	Resources    []string          `json:"resources"`
+	Placeholders []string          `json:"placeholders,omitempty"`
+	Validation   ValidationSummary `json:"validation"`
+}
+
+// NewScaffoldModuleTool creates a new ScaffoldModule tool
+func NewScaffoldModuleTool(indexer *tfdocs.Indexer, patternRepo *tfdocs.PatternRepository, engine *tfdocs.ValidationEngine, logger Logger) *ScaffoldModuleTool {
+	return &ScaffoldModuleTool{
+		docIndexer:       indexer,
+		patternRepo:      patternRepo,
+		validationEngine: engine,
+		logger:           logger,
+	}
+}
+
+// Name returns the name of the tool
+func (t *ScaffoldModuleTool) Name() string {
+	return "ScaffoldModule"
+}
+
+// Describe returns a description of the tool
+func (t *ScaffoldModuleTool) Describe() mcp.ToolDescription {
+	return mcp.ToolDescription{
+		Name:        t.Name(),
+		Description: "Generates a complete Terraform module following the documented module structure, taking resources from the code patterns and deriving variables, outputs, version constraints and the README from them",
+		Parameters: map[string]mcp.ParameterDescription{
+			"name": {
+				Type:        "string",
+				Description: "Name of the module (e.g., 'network')",
+				Required:    true,
+			},
+			"provider": {
+				Type:        "string",
+				Description: "The provider of the module (e.g., 'aws', 'azure', 'gcp')",
+				Required:    true,
+			},
+			"resourceTypes": {
+				Type:        "array",
+				Description: "Resource types the module manages (e.g., ['aws_vpc', 'aws_subnet'])",
+				Required:    true,
+			},
+			"examples": {
+				Type:        "boolean",
+				Description: "Whether to generate an example calling the module in examples/basic",
+				Required:    false,
+			},
+			"tests": {
+				Type:        "boolean",
+				Description: "Whether to generate a terraform test file in tests/",
+				Required:    false,
+			},
+			"ciWorkflow": {
+				Type:        "boolean",
+				Description: "Whether to generate a GitHub Actions workflow checking the module",
+				Required:    false,
+			},
+			"preCommit": {
+				Type:        "boolean",
+				Description: "Whether to generate a pre-commit configuration with the Terraform hooks",
+				Required:    false,
+			},
+		},
+	}
+}
+
+// Execute executes the tool with the given arguments
+func (t *ScaffoldModuleTool) Execute(ctx context.Context, args json.RawMessage) (json.RawMessage, error) {
+	var a ScaffoldModuleArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal arguments: %w", err)
+	}
+
+	t.logger.Debug("Executing ScaffoldModule", "name", a.Name, "provider", a.Provider, "resourceTypes", a.ResourceTypes)
+
+	// Use the module structure of the provider, falling back to the basic one
+	structures, err := t.docIndexer.GetModuleStructures("", a.Provider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get module structures: %w", err)
+	}
+	if len(structures) == 0 {
+		structures, err = t.docIndexer.GetModuleStructures("basic", "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get module structures: %w", err)
+		}
+	}
+	var structure *tfdocs.ModuleStructureDoc
+	if len(structures) > 0 {
+		structure = &structures[0]
+	}
+
+	patterns, err := t.patternRepo.FindPatterns(tfdocs.PatternFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find patterns: %w", err)
+	}
+
+	scaffold, err := tfdocs.ScaffoldModule(tfdocs.ModuleSpec{
+		Name:          a.Name,
+		Provider:      a.Provider,
+		ResourceTypes: a.ResourceTypes,
+		Examples:      a.Examples,
+		Tests:         a.Tests,
+		CIWorkflow:    a.CIWorkflow,
+		PreCommit:     a.PreCommit,
+	}, structure, patterns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scaffold module: %w", err)
+	}
+
+	// Validate the generated module
+	config, err := tfdocs.ParseTerraformConfiguration(scaffold.Files)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse configuration: %w", err)
+	}
+	validation, err := t.validationEngine.ValidateConfiguration(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate configuration: %w", err)
+	}
+
+	// Prepare result
+	result := ScaffoldModuleResult{
+		Files:        scaffold.Files,
+		Patterns:     scaffold.Patterns,
+		Resources:    scaffold.Resources,
+		Placeholders: scaffold.Placeholders,
+		Validation: ValidationSummary{
+			FileCount:  validation.FileCount,
+			ErrorCount: validation.ErrorCount,
+			WarnCount:  validation.WarnCount,
+			InfoCount:  validation.InfoCount,
+		},
+	}
+
+	return json.Marshal(result)
+}
+
+// GenerateTestsTool is a tool for generating terraform test files for modules
+type GenerateTestsTool struct {
+	logger Logger
+}
+
+// GenerateTestsArgs are the arguments for the GenerateTests tool
+type GenerateTestsArgs struct {
+	Files map[string]string `json:"files"`
+	Name  string            `json:"name,omitempty"`
+}
+
+// GenerateTestsResult is the result of the GenerateTests tool
+type GenerateTestsResult struct {
+	Files    map[string]string `json:"files"`
+	Runs     []string          `json:"runs"`
+	Untested []string          `json:"untested,omitempty"`
+}
+
+// NewGenerateTestsTool creates a new GenerateTests tool
+func NewGenerateTestsTool(logger Logger) *GenerateTestsTool {
+	return &GenerateTestsTool{
+		logger: logger,
+	}
+}
+
+// Name returns the name of the tool
+func (t *GenerateTestsTool) Name() string {
+	return "GenerateTests"
+}
+
+// Describe returns a description of the tool
+func (t *GenerateTestsTool) Describe() mcp.ToolDescription {
+	return mcp.ToolDescription{
+		Name:        t.Name(),
+		Description: "Generates terraform test (.tftest.hcl) files for the root module from its variables and outputs: a plan-mode run asserting the outputs are set, and runs with expect_failures checking that each variable validation rejects an invalid value, using mocked providers",
+		Parameters: map[string]mcp.ParameterDescription{
+			"files": {
+				Type:        "object",
+				Description: "Map of filenames to file contents of the module",
+				Required:    true,
+			},
+			"name": {
+				Type:        "string",
+				Description: "Name of the test file of the plan run, tests/<name>.tftest.hcl (default: 'main')",
+				Required:    false,
+			},
+		},
+	}
+}
+
+// Execute executes the tool with the given arguments
+func (t *GenerateTestsTool) Execute(ctx context.Context, args json.RawMessage) (json.RawMessage, error) {
+	var a GenerateTestsArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal arguments: %w", err)
+	}
+
+	t.logger.Debug("Executing GenerateTests", "fileCount", len(a.Files), "name", a.Name)
+
+	// Parse the configuration
+	config, err := tfdocs.ParseTerraformConfiguration(a.Files)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse configuration: %w", err)
+	}
+
+	name := a.Name
+	if name == "" {
+		name = "main"
+	}
+	tests := tfdocs.GenerateTests(config, name)
+
+	// Prepare result
+	result := GenerateTestsResult{
+		Files:    tests.Files,
+		Runs:     tests.Runs,
+		Untested: tests.Untested,
+	}
+
+	return json.Marshal(result)
+}
+
+// GenerateExamplesTool is a tool for generating example callers of modules
+type GenerateExamplesTool struct {
+	logger Logger
+}
+
+// GenerateExamplesArgs are the arguments for the GenerateExamples tool
+type GenerateExamplesArgs struct {
+	Files map[string]string `json:"files"`
+	Name  string            `json:"name,omitempty"`
+}
+
+// GenerateExamplesResult is the result of the GenerateExamples tool
+type GenerateExamplesResult struct {
+	Files map[string]string `json:"files"`
+}
+
+// NewGenerateExamplesTool creates a new GenerateExamples tool
+func NewGenerateExamplesTool(logger Logger) *GenerateExamplesTool {
+	return &GenerateExamplesTool{
+		logger: logger,
+	}
+}
+
+// Name returns the name of the tool
+func (t *GenerateExamplesTool) Name() string {
+	return "GenerateExamples"
+}
+
+// Describe returns a description of the tool
+func (t *GenerateExamplesTool) Describe() mcp.ToolDescription {
+	return mcp.ToolDescription{
+		Name:        t.Name(),
+		Description: "Generates examples/basic, calling the root module with its required inputs, and examples/complete, setting all of its inputs with realistic values inferred from their types and validations, each with a README",
+		Parameters: map[string]mcp.ParameterDescription{
+			"files": {
+				Type:        "object",
+				Description: "Map of filenames to file contents of the module",
+				Required:    true,
+			},
+			"name": {
+				Type:        "string",
+				Description: "Name of the module used in the examples (default: 'example')",
+				Required:    false,
+			},
+		},
+	}
+}
+
+// Execute executes the tool with the given arguments
+func (t *GenerateExamplesTool) Execute(ctx context.Context, args json.RawMessage) (json.RawMessage, error) {
+	var a GenerateExamplesArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal arguments: %w", err)
+	}
+
+	t.logger.Debug("Executing GenerateExamples", "fileCount", len(a.Files), "name", a.Name)
+
+	// Parse the configuration
+	config, err := tfdocs.ParseTerraformConfiguration(a.Files)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse configuration: %w", err)
+	}
+
+	name := a.Name
+	if name == "" {
+		name = "example"
+	}
+
+	// Prepare result
+	result := GenerateExamplesResult{
+		Files: tfdocs.GenerateExamples(config, name),
+	}
+
+	return json.Marshal(result)
+}
+
+// GetDependencyGraphTool is a tool for exporting the dependency graph of Terraform configurations
+type GetDependencyGraphTool struct {
+	logger Logger
+}
+
+// GetDependencyGraphArgs are the arguments for the GetDependencyGraph tool
+type GetDependencyGraphArgs struct {
+	Files  map[string]string `json:"files"`
+	Format string            `json:"format,omitempty"`
+}
+
+// GetDependencyGraphResult is the result of the GetDependencyGraph tool
+type GetDependencyGraphResult struct {
+	Format    tfdocs.GraphFormat      `json:"format"`
+	Graph     string                  `json:"graph"`
+	Nodes     []*tfdocs.Symbol        `json:"nodes"`
+	Edges     []tfdocs.DependencyEdge `json:"edges"`
+	Adjacency map[string][]string     `json:"adjacency"`
+	Cycles    [][]string              `json:"cycles"`
+}
+
+// NewGetDependencyGraphTool creates a new GetDependencyGraph tool
+func NewGetDependencyGraphTool(logger Logger) *GetDependencyGraphTool {
+	return &GetDependencyGraphTool{
+		logger: logger,
+	}
+}
+
+// Name returns the name of the tool
+func (t *GetDependencyGraphTool) Name() string {
+	return "GetDependencyGraph"
+}
+
+// Describe returns a description of the tool
+func (t *GetDependencyGraphTool) Describe() mcp.ToolDescription {
+	return mcp.ToolDescription{
+		Name:        t.Name(),
+		Description: "Returns the dependency graph between the variables, locals, resources, data sources, modules and outputs of Terraform configurations, including implicit references and depends_on",
+		Parameters: map[string]mcp.ParameterDescription{
+			"files": {
+				Type:        "object",
+				Description: "Map of filenames to file contents",
+				Required:    true,
+			},
+			"format": {
+				Type:        "string",
+				Description: "Format of the rendered graph: 'dot', 'mermaid' or 'json' (adjacency lists, the default)",
+				Required:    false,
+			},
+		},
+	}
+}
+
+// Execute executes the tool with the given arguments
+func (t *GetDependencyGraphTool) Execute(ctx context.Context, args json.RawMessage) (json.RawMessage, error) {
+	var a GetDependencyGraphArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal arguments: %w", err)
+	}
+
+	t.logger.Debug("Executing GetDependencyGraph", "fileCount", len(a.Files), "format", a.Format)
+
+	format, err := tfdocs.ParseGraphFormat(a.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the configuration
+	config, err := tfdocs.ParseTerraformConfiguration(a.Files)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse configuration: %w", err)
+	}
+
+	// Build and render the graph
+	graph := config.DependencyGraph()
+	rendered, err := graph.Render(format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render graph: %w", err)
+	}
+
+	// Prepare result
+	result := GetDependencyGraphResult{
+		Format:    format,
+		Graph:     rendered,
+		Nodes:     graph.Nodes,
+		Edges:     graph.Edges,
+		Adjacency: graph.Adjacency,
+		Cycles:    graph.Cycles(),
+	}
+
+	return json.Marshal(result)
+}
+
+// ValidatePlanTool is a tool for validating the JSON representation of Terraform plans
+type ValidatePlanTool struct {
+	validationEngine *tfdocs.ValidationEngine
+	workspace        *tfdocs.Workspace
+	logger           Logger
+}
+
+// ValidatePlanArgs are the arguments for the ValidatePlan tool
+type ValidatePlanArgs struct {
+	Plan         json.RawMessage `json:"plan,omitempty"`
+	Path         string          `json:"path,omitempty"`
+	OutputFormat string          `json:"outputFormat,omitempty"`
+}
+
+// ValidatePlanResult is the result of the ValidatePlan tool
+type ValidatePlanResult struct {
+	Issues     []tfdocs.ValidationIssue    `json:"issues"`
+	Summary    ValidationSummary           `json:"summary"`
+	Changes    map[tfdocs.ChangeAction]int `json:"changes"`
+	Format     tfdocs.OutputFormat         `json:"format"`
+	Formatted  string                      `json:"formatted"`
+	Successful bool                        `json:"successful"`
+}
+
+// NewValidatePlanTool creates a new ValidatePlan tool
+func NewValidatePlanTool(engine *tfdocs.ValidationEngine, workspace *tfdocs.Workspace, logger Logger) *ValidatePlanTool {
+	return &ValidatePlanTool{
+		validationEngine: engine,
+		workspace:        workspace,
+		logger:           logger,
+	}
+}
+
+// Name returns the name of the tool
+func (t *ValidatePlanTool) Name() string {
+	return "ValidatePlan"
+}
+
+// Describe returns a description of the tool
+func (t *ValidatePlanTool) Describe() mcp.ToolDescription {
+	return mcp.ToolDescription{
+		Name:        t.Name(),
+		Description: "Validates the output of terraform show -json for a plan file, checking evaluated values such as destroyed or replaced stateful resources, resolved security group rules and final tags",
+		Parameters: map[string]mcp.ParameterDescription{
+			"plan": {
+				Type:        "object",
+				Description: "The plan JSON, as an object or a string; either plan or path is required",
+				Required:    false,
+			},
+			"path": {
+				Type:        "string",
+				Description: "Plan JSON file to validate instead of plan, relative to the first workspace root or absolute within a workspace root",
+				Required:    false,
+			},
+			"outputFormat": {
+				Type:        "string",
+				Description: "Format of the formatted result: 'text' (default), 'json', 'sarif', 'junit' or 'checkstyle'",
+				Required:    false,
+			},
+		},
+	}
+}
+
+// Execute executes the tool with the given arguments
+func (t *ValidatePlanTool) Execute(ctx context.Context, args json.RawMessage) (json.RawMessage, error) {
+	var a ValidatePlanArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal arguments: %w", err)
+	}
+
+	t.logger.Debug("Executing ValidatePlan", "path", a.Path, "outputFormat", a.OutputFormat)
+
+	format, err := tfdocs.ParseOutputFormat(a.OutputFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load and parse the plan
+	data, err := loadJSONDocument(t.workspace, a.Plan, a.Path, "plan")
+	if err != nil {
+		return nil, err
+	}
+	plan, err := tfdocs.ParsePlan(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the plan
+	result, err := t.validationEngine.ValidatePlan(plan)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate plan: %w", err)
+	}
+	if a.Path != "" {
+		for i := range result.Issues {
+			result.Issues[i].File = a.Path
+		}
+	}
+
+	// Format the validation result
+	formatted, err := tfdocs.FormatReport(format, result, t.validationEngine.Rules(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format validation result: %w", err)
+	}
+
+	changes := make(map[tfdocs.ChangeAction]int)
+	for _, change := range plan.ResourceChanges {
+		changes[change.Action()]++
+	}
+
+	// Prepare result
+	validationResult := ValidatePlanResult{
+		Issues: result.Issues,
+		Summary: ValidationSummary{
+			ErrorCount: result.ErrorCount,
+			WarnCount:  result.WarnCount,
+			InfoCount:  result.InfoCount,
+		},
+		Changes:    changes,
+		Format:     format,
+		Formatted:  formatted,
+		Successful: result.ErrorCount == 0,
+	}
+
+	return json.Marshal(validationResult)
+}
+
+// SummarizePlanTool is a tool for summarizing the risk of applying a plan
+type SummarizePlanTool struct {
+	workspace *tfdocs.Workspace
+	logger    Logger
+}
+
+// SummarizePlanArgs are the arguments for the SummarizePlan tool
+type SummarizePlanArgs struct {
+	Plan json.RawMessage `json:"plan,omitempty"`
+	Path string          `json:"path,omitempty"`
+	Top  int             `json:"top,omitempty"`
+}
+
+// NewSummarizePlanTool creates a new SummarizePlan tool
+func NewSummarizePlanTool(workspace *tfdocs.Workspace, logger Logger) *SummarizePlanTool {
+	return &SummarizePlanTool{
+		workspace: workspace,
+		logger:    logger,
+	}
+}
+
+// Name returns the name of the tool
+func (t *SummarizePlanTool) Name() string {
+	return "SummarizePlan"
+}
+
+// Describe returns a description of the tool
+func (t *SummarizePlanTool) Describe() mcp.ToolDescription {
+	return mcp.ToolDescription{
+		Name:        t.Name(),
+		Description: "Summarizes how risky it is to apply a plan, scoring each create, update, replace and destroy by the category of the resource, and returns the risk score, the top risky changes and a Markdown summary for change review",
+		Parameters: map[string]mcp.ParameterDescription{
+			"plan": {
+				Type:        "object",
+				Description: "The output of terraform show -json for a plan file, as an object or a string; either plan or path is required",
+				Required:    false,
+			},
+			"path": {
+				Type:        "string",
+				Description: "Plan JSON file to summarize instead of plan, relative to the first workspace root or absolute within a workspace root",
+				Required:    false,
+			},
+			"top": {
+				Type:        "integer",
+				Description: "Number of risky changes to return (default: 5)",
+				Required:    false,
+			},
+		},
+	}
+}
+
+// Execute executes the tool with the given arguments
+func (t *SummarizePlanTool) Execute(ctx context.Context, args json.RawMessage) (json.RawMessage, error) {
+	var a SummarizePlanArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal arguments: %w", err)
+	}
+
+	t.logger.Debug("Executing SummarizePlan", "path", a.Path, "top", a.Top)
+
+	if a.Top < 0 {
+		return nil, fmt.Errorf("top must not be negative")
+	}
+	if a.Top == 0 {
+		a.Top = 5
+	}
+
+	// Load and parse the plan
+	data, err := loadJSONDocument(t.workspace, a.Plan, a.Path, "plan")
+	if err != nil {
+		return nil, err
+	}
+	plan, err := tfdocs.ParsePlan(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(tfdocs.SummarizePlan(plan, a.Top))
+}
+
+// AnalyzeStateTool is a tool for analyzing Terraform state files
+type AnalyzeStateTool struct {
+	validationEngine *tfdocs.ValidationEngine
+	workspace        *tfdocs.Workspace
+	logger           Logger
+}
+
+// AnalyzeStateArgs are the arguments for the AnalyzeState tool
+type AnalyzeStateArgs struct {
+	State        json.RawMessage   `json:"state,omitempty"`
+	Path         string            `json:"path,omitempty"`
+	Files        map[string]string `json:"files,omitempty"`
+	ConfigPath   string            `json:"configPath,omitempty"`
+	OutputFormat string            `json:"outputFormat,omitempty"`
+}
+
+// AnalyzeStateResult is the result of the AnalyzeState tool
+type AnalyzeStateResult struct {
+	Issues     []tfdocs.ValidationIssue `json:"issues"`
+	Summary    ValidationSummary        `json:"summary"`
+	State      tfdocs.StateSummary      `json:"state"`
+	Format     tfdocs.OutputFormat      `json:"format"`
+	Formatted  string                   `json:"formatted"`
+	Successful bool                     `json:"successful"`
+}
+
+// NewAnalyzeStateTool creates a new AnalyzeState tool
+func NewAnalyzeStateTool(engine *tfdocs.ValidationEngine, workspace *tfdocs.Workspace, logger Logger) *AnalyzeStateTool {
+	return &AnalyzeStateTool{
+		validationEngine: engine,
+		workspace:        workspace,
+		logger:           logger,
+	}
+}
+
+// Name returns the name of the tool
+func (t *AnalyzeStateTool) Name() string {
+	return "AnalyzeState"
+}
+
+// Describe returns a description of the tool
+func (t *AnalyzeStateTool) Describe() mcp.ToolDescription {
+	return mcp.ToolDescription{
+		Name:        t.Name(),
+		Description: "Analyzes a Terraform state file (version 4) offline, reporting missing required tags, secrets not marked sensitive, drift-prone resources and orphaned modules, and counting resources per provider",
+		Parameters: map[string]mcp.ParameterDescription{
+			"state": {
+				Type:        "object",
+				Description: "The state JSON, as an object or a string; either state or path is required",
+				Required:    false,
+			},
+			"path": {
+				Type:        "string",
+				Description: "State file to analyze instead of state, relative to the first workspace root or absolute within a workspace root",
+				Required:    false,
+			},
+			"files": {
+				Type:        "object",
+				Description: "Map of filenames to file contents of the configuration, used to find orphaned modules",
+				Required:    false,
+			},
+			"configPath": {
+				Type:        "string",
+				Description: "Configuration directory used to find orphaned modules instead of files",
+				Required:    false,
+			},
+			"outputFormat": {
+				Type:        "string",
+				Description: "Format of the formatted result: 'text' (default), 'json', 'sarif', 'junit' or 'checkstyle'",
+				Required:    false,
+			},
+		},
+	}
+}
+
+// Execute executes the tool with the given arguments
+func (t *AnalyzeStateTool) Execute(ctx context.Context, args json.RawMessage) (json.RawMessage, error) {
+	var a AnalyzeStateArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal arguments: %w", err)
+	}
+
+	t.logger.Debug("Executing AnalyzeState", "path", a.Path, "configPath", a.ConfigPath, "outputFormat", a.OutputFormat)
+
+	format, err := tfdocs.ParseOutputFormat(a.OutputFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load and parse the state
+	data, err := loadJSONDocument(t.workspace, a.State, a.Path, "state")
+	if err != nil {
+		return nil, err
+	}
+	state, err := tfdocs.ParseState(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the configuration, if any
+	var config *tfdocs.TerraformConfiguration
+	switch {
+	case a.ConfigPath != "" && len(a.Files) > 0:
+		return nil, fmt.Errorf("files and configPath cannot be used together")
+	case a.ConfigPath != "":
+		dir, err := t.workspace.Resolve(a.ConfigPath)
+		if err != nil {
+			return nil, err
+		}
+		modules, err := tfdocs.DiscoverModules(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load modules: %w", err)
+		}
+		config = tfdocs.CombineModules(modules)
+	case len(a.Files) > 0:
+		config, err = tfdocs.ParseTerraformConfiguration(a.Files)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse configuration: %w", err)
+		}
+	}
+
+	// Analyze the state
+	result, err := t.validationEngine.AnalyzeState(state, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze state: %w", err)
+	}
+	if a.Path != "" {
+		for i := range result.Issues {
+			result.Issues[i].File = a.Path
+		}
+	}
+
+	// Format the analysis result
+	formatted, err := tfdocs.FormatReport(format, result, t.validationEngine.Rules(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format analysis result: %w", err)
+	}
+
+	// Prepare result
+	analysisResult := AnalyzeStateResult{
+		Issues: result.Issues,
+		Summary: ValidationSummary{
+			FileCount:  result.FileCount,
+			ErrorCount: result.ErrorCount,
+			WarnCount:  result.WarnCount,
+			InfoCount:  result.InfoCount,
+		},
+		State:      state.Summary(),
+		Format:     format,
+		Formatted:  formatted,
+		Successful: result.ErrorCount == 0,
+	}
+
+	return json.Marshal(analysisResult)
+}
+
+// loadJSONDocument returns a JSON document passed inline, as an object or a
+// string holding JSON, or read from a file inside the workspace
+func loadJSONDocument(workspace *tfdocs.Workspace, inline json.RawMessage, path, what string) ([]byte, error) {
+	switch {
+	case path != "" && len(inline) > 0:
+		return nil, fmt.Errorf("%s and path cannot be used together", what)
+	case path != "":
+		file, err := workspace.ResolveFile(path)
+		if err != nil {
+			return nil, err
+		}
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", what, err)
+		}
+		return data, nil
+	case len(inline) == 0:
+		return nil, fmt.Errorf("either %s or path is required", what)
+	}
+
+	var text string
+	if err := json.Unmarshal(inline, &text); err == nil {
+		return []byte(text), nil
+	}
+	return inline, nil
+}
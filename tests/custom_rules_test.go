@@ -0,0 +1,122 @@
+// tests/custom_rules_test.go
+package tests
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"terraform-mcp-server/pkg/hashicorp/tfdocs"
+)
+
+const testCustomRules = `
+rules:
+  - id: s3-public-access-block
+    description: S3 buckets must have a public access block
+    severity: error
+    match:
+      block: resource
+      labels: ["aws_s3_bucket"]
+    require:
+      sibling:
+        block: resource
+        labels: ["aws_s3_bucket_public_access_block"]
+        references: true
+  - id: ebs-encrypted
+    description: EBS volumes must be encrypted
+    match:
+      block: resource
+      labels: ["aws_ebs_volume"]
+    require:
+      attributes:
+        - name: encrypted
+          equals: true
+`
+
+func TestCustomRules(t *testing.T) {
+	// Write the rules to a policy directory
+	policyDir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(policyDir, "rules.yaml"), []byte(testCustomRules), 0644); err != nil {
+		t.Fatalf("Failed to write rule file: %v", err)
+	}
+
+	rules, err := tfdocs.LoadCustomRules(policyDir)
+	if err != nil {
+		t.Fatalf("Failed to load custom rules: %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("Expected 2 rules, got %d", len(rules))
+	}
+
+	validator := tfdocs.NewCustomRuleValidator(rules)
+
+	// A configuration violating both rules
+	config := &tfdocs.TerraformConfiguration{
+		Files: map[string]string{
+			"main.tf": `
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+
+resource "aws_ebs_volume" "data" {
+  availability_zone = "us-west-2a"
+  size              = 40
+  encrypted         = false
+}
+`,
+		},
+	}
+
+	issues := validator.Validate(config)
+	found := map[string]bool{}
+	for _, issue := range issues {
+		found[issue.RuleID] = true
+	}
+	if !found["s3-public-access-block"] {
+		t.Errorf("Expected s3-public-access-block issue, got %v", issues)
+	}
+	if !found["ebs-encrypted"] {
+		t.Errorf("Expected ebs-encrypted issue, got %v", issues)
+	}
+
+	// A compliant configuration
+	config = &tfdocs.TerraformConfiguration{
+		Files: map[string]string{
+			"main.tf": `
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+
+resource "aws_s3_bucket_public_access_block" "logs" {
+  bucket = aws_s3_bucket.logs.id
+}
+
+resource "aws_ebs_volume" "data" {
+  availability_zone = "us-west-2a"
+  size              = 40
+  encrypted         = true
+}
+`,
+		},
+	}
+
+	if issues := validator.Validate(config); len(issues) != 0 {
+		t.Errorf("Expected no issues, got %v", issues)
+	}
+}
+
+func TestCustomRulesInvalid(t *testing.T) {
+	_, err := tfdocs.ParseCustomRules([]byte(`
+rules:
+  - id: broken
+    match:
+      block: resource
+    require:
+      attributes:
+        - name: name
+          matches: "("
+`))
+	if err == nil {
+		t.Errorf("Expected an error for an invalid pattern, got none")
+	}
+}
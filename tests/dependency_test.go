@@ -0,0 +1,142 @@
+// tests/dependency_test.go
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"terraform-mcp-server/pkg/hashicorp/tfdocs"
+)
+
+func dependencyFixture() *tfdocs.TerraformConfiguration {
+	return &tfdocs.TerraformConfiguration{
+		Files: map[string]string{
+			"main.tf": `variable "name" {
+  type = string
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = var.name
+}
+
+resource "aws_instance" "web" {
+  ami  = "ami-123"
+  tags = { Name = var.name }
+
+  depends_on = [aws_s3_bucket.logs]
+}
+
+module "app" {
+  source   = "./modules/app"
+  instance = aws_instance.web.id
+}
+
+output "app_url" {
+  value = module.app.url
+}
+`,
+		},
+	}
+}
+
+func TestDependencyGraph(t *testing.T) {
+	graph := dependencyFixture().DependencyGraph()
+
+	if got := graph.Adjacency["aws_instance.web"]; len(got) != 2 || got[0] != "aws_s3_bucket.logs" || got[1] != "var.name" {
+		t.Errorf("Expected aws_instance.web to depend on aws_s3_bucket.logs and var.name, got %v", got)
+	}
+	if got := graph.Adjacency["output.app_url"]; len(got) != 1 || got[0] != "module.app" {
+		t.Errorf("Expected output.app_url to depend on module.app, got %v", got)
+	}
+	if len(graph.Cycles()) != 0 {
+		t.Errorf("Expected no cycles, got %v", graph.Cycles())
+	}
+
+	dot, err := graph.Render(tfdocs.GraphFormatDOT)
+	if err != nil {
+		t.Fatalf("Failed to render DOT: %v", err)
+	}
+	if !strings.Contains(dot, `"aws_instance.web" -> "aws_s3_bucket.logs" [style=dashed, label="depends_on"];`) {
+		t.Errorf("Expected a dashed depends_on edge, got:\n%s", dot)
+	}
+
+	mermaid, err := graph.Render(tfdocs.GraphFormatMermaid)
+	if err != nil {
+		t.Fatalf("Failed to render Mermaid: %v", err)
+	}
+	if !strings.HasPrefix(mermaid, "flowchart LR\n") || !strings.Contains(mermaid, `[["module.app"]]`) {
+		t.Errorf("Expected a Mermaid flowchart with module.app, got:\n%s", mermaid)
+	}
+	if !strings.Contains(mermaid, " -. depends_on .-> ") {
+		t.Errorf("Expected a dotted depends_on edge, got:\n%s", mermaid)
+	}
+}
+
+func TestDependencyCycles(t *testing.T) {
+	config := &tfdocs.TerraformConfiguration{
+		Files: map[string]string{
+			"main.tf": `locals {
+  a = local.b
+  b = local.c
+  c = local.a
+}
+
+resource "aws_security_group" "a" {
+  name = aws_security_group.b.name
+}
+
+resource "aws_security_group" "b" {
+  name       = "b"
+  depends_on = [aws_security_group.a]
+}
+
+output "a" {
+  value = local.a
+}
+`,
+		},
+	}
+
+	cycles := config.DependencyGraph().Cycles()
+	if len(cycles) != 2 {
+		t.Fatalf("Expected 2 cycles, got %v", cycles)
+	}
+	if got := strings.Join(cycles[0], " -> "); got != "aws_security_group.a -> aws_security_group.b -> aws_security_group.a" {
+		t.Errorf("Unexpected cycle: %s", got)
+	}
+	if got := strings.Join(cycles[1], " -> "); got != "local.a -> local.b -> local.c -> local.a" {
+		t.Errorf("Unexpected cycle: %s", got)
+	}
+
+	var reported int
+	for _, issue := range (&tfdocs.ReferenceValidator{}).Validate(config) {
+		if issue.RuleID == "dependency-cycles" {
+			if issue.Severity != tfdocs.SeverityError {
+				t.Errorf("Expected cycles to be errors, got %s", issue.Severity)
+			}
+			reported++
+		}
+	}
+	if reported != 2 {
+		t.Errorf("Expected 2 dependency-cycles issues, got %d", reported)
+	}
+
+	// A module output feeding a resource that is passed back to the module
+	// is resolved per input and output, so it is not a cycle
+	config = &tfdocs.TerraformConfiguration{
+		Files: map[string]string{
+			"main.tf": `module "network" {
+  source            = "./modules/network"
+  security_group_id = aws_security_group.web.id
+}
+
+resource "aws_security_group" "web" {
+  vpc_id = module.network.vpc_id
+}
+`,
+		},
+	}
+	if cycles := config.DependencyGraph().Cycles(); len(cycles) != 0 {
+		t.Errorf("Expected no cycles through module calls, got %v", cycles)
+	}
+}
@@ -0,0 +1,87 @@
+// tests/diff_test.go
+package tests
+
+import (
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"terraform-mcp-server/pkg/hashicorp/tfdocs"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	original := "a\nb\nc\nd\ne\nf\ng\nh\n"
+	modified := "a\nb\nc\nd\nE\nf\ng\nh\n"
+
+	diff := tfdocs.UnifiedDiff("main.tf", original, true, modified)
+	expected := `diff --git a/main.tf b/main.tf
+--- a/main.tf
++++ b/main.tf
+@@ -2,7 +2,7 @@
+ b
+ c
+ d
+-e
++E
+ f
+ g
+ h
+`
+	if diff != expected {
+		t.Errorf("Unexpected diff:\n%s", diff)
+	}
+
+	if diff := tfdocs.UnifiedDiff("main.tf", original, true, original); diff != "" {
+		t.Errorf("Expected no diff for identical content, got:\n%s", diff)
+	}
+
+	diff = tfdocs.UnifiedDiff("versions.tf", "", false, "terraform {}")
+	if !strings.Contains(diff, "--- /dev/null\n+++ b/versions.tf\n@@ -0,0 +1,1 @@\n+terraform {}\n\\ No newline at end of file\n") {
+		t.Errorf("Unexpected new file diff:\n%s", diff)
+	}
+}
+
+func TestCombinedPatchApplies(t *testing.T) {
+	git, err := exec.LookPath("git")
+	if err != nil {
+		t.Skip("git is not installed")
+	}
+
+	original := map[string]string{
+		"main.tf":      "resource \"aws_s3_bucket\" \"logs\" {\n  bucket = var.name\n}\n",
+		"variables.tf": "variable \"name\" {\n  type = string\n}",
+	}
+	improvements := map[string]string{
+		"variables.tf": "variable \"name\" {\n  description = \"The bucket name\"\n  type        = string\n}\n",
+		"outputs.tf":   "output \"bucket\" {\n  value = aws_s3_bucket.logs.id\n}\n",
+	}
+
+	dir := t.TempDir()
+	for name, content := range original {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	patch := tfdocs.CombinedPatch(original, improvements)
+	if err := ioutil.WriteFile(filepath.Join(dir, "improvements.patch"), []byte(patch), 0644); err != nil {
+		t.Fatalf("Failed to write patch: %v", err)
+	}
+
+	cmd := exec.Command(git, "apply", "improvements.patch")
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git apply failed: %v\n%s\npatch:\n%s", err, output, patch)
+	}
+
+	for name, content := range improvements {
+		data, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		if string(data) != content {
+			t.Errorf("Unexpected content of %s after applying the patch:\n%s", name, data)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+// tests/examples_test.go
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"terraform-mcp-server/pkg/hashicorp/tfdocs"
+)
+
+func TestGenerateExamples(t *testing.T) {
+	files := tftestConfig()
+	files["versions.tf"] = `terraform {
+  required_version = ">= 1.6.0, < 2.0.0"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.40"
+    }
+  }
+}
+`
+	files["variables.tf"] += `
+variable "public_subnets" {
+  description = "CIDR blocks of the public subnets"
+  type        = list(string)
+  default     = []
+}
+
+variable "db_password" {
+  description = "Password of the database"
+  type        = string
+  sensitive   = true
+}
+`
+	config, err := tfdocs.ParseTerraformConfiguration(files)
+	if err != nil {
+		t.Fatalf("Failed to parse configuration: %v", err)
+	}
+
+	examples := tfdocs.GenerateExamples(config, "network")
+
+	for _, name := range []string{"main.tf", "versions.tf", "outputs.tf", "variables.tf", "README.md"} {
+		for _, dir := range []string{"examples/basic/", "examples/complete/"} {
+			if _, ok := examples[dir+name]; !ok {
+				t.Errorf("Expected %s%s to be generated", dir, name)
+			}
+		}
+	}
+
+	basic := examples["examples/basic/main.tf"]
+	for _, want := range []string{
+		"provider \"aws\" {\n  region = \"us-east-1\"\n}",
+		"module \"network\" {\n  source = \"../..\"\n",
+		`db_password        = var.db_password`,
+		`vpc_cidr           = "10.0.0.0/16"`,
+		`flow_log_retention = 90`,
+	} {
+		if !strings.Contains(basic, want) {
+			t.Errorf("Expected basic example to contain %q, got:\n%s", want, basic)
+		}
+	}
+	if strings.Contains(basic, "environment") || strings.Contains(basic, "public_subnets") {
+		t.Errorf("Expected the basic example to set only required inputs, got:\n%s", basic)
+	}
+
+	complete := examples["examples/complete/main.tf"]
+	for _, want := range []string{
+		`environment        = "dev"`,
+		`public_subnets     = ["10.0.1.0/24", "10.0.2.0/24"]`,
+		`tags               = { Environment = "test" }`,
+		`subnets            = { example = { cidr = "10.0.0.0/16", zone = "us-east-1a" } }`,
+	} {
+		if !strings.Contains(complete, want) {
+			t.Errorf("Expected complete example to contain %q, got:\n%s", want, complete)
+		}
+	}
+
+	if versions := examples["examples/complete/versions.tf"]; !strings.Contains(versions, `required_version = ">= 1.6.0, < 2.0.0"`) || !strings.Contains(versions, `version = "~> 5.40"`) {
+		t.Errorf("Expected the example to require the versions of the module, got:\n%s", versions)
+	}
+	if variables := examples["examples/basic/variables.tf"]; !strings.Contains(variables, "sensitive   = true") {
+		t.Errorf("Expected the sensitive input to be a sensitive variable of the example, got:\n%s", variables)
+	}
+	if outputs := examples["examples/basic/outputs.tf"]; !strings.Contains(outputs, "value       = module.network.vpc_id") {
+		t.Errorf("Expected the example to expose the outputs of the module, got:\n%s", outputs)
+	}
+
+	readme := examples["examples/complete/README.md"]
+	for _, want := range []string{"# Complete example", "Calls the `network` module with all of its inputs.", "export TF_VAR_db_password=", "| network | ../.. | n/a |"} {
+		if !strings.Contains(readme, want) {
+			t.Errorf("Expected complete README to contain %q, got:\n%s", want, readme)
+		}
+	}
+
+	// The examples validate without errors alongside the module
+	for name, content := range examples {
+		files[name] = content
+	}
+	config, err = tfdocs.ParseTerraformConfiguration(files)
+	if err != nil {
+		t.Fatalf("Failed to parse configuration: %v", err)
+	}
+	result, err := tfdocs.NewValidationEngine(nil, &mockLogger{}).ValidateConfiguration(config)
+	if err != nil {
+		t.Fatalf("Failed to validate configuration: %v", err)
+	}
+	for _, issue := range result.Issues {
+		if strings.HasPrefix(issue.File, "examples/") && issue.Severity == tfdocs.SeverityError {
+			t.Errorf("Unexpected error %s in %s: %s", issue.RuleID, issue.File, issue.Message)
+		}
+	}
+}
+
+func TestGenerateExamplesRootModule(t *testing.T) {
+	files := tftestConfig()
+	files["main.tf"] += `
+module "child" {
+  source = "./modules/child"
+
+  child_input = var.vpc_cidr
+}
+`
+	files["modules/child/main.tf"] = `variable "child_input" {
+  description = "Input of the child module"
+  type        = string
+}
+
+output "child_output" {
+  description = "Output of the child module"
+  value       = var.child_input
+}
+`
+	config, err := tfdocs.ParseTerraformConfiguration(files)
+	if err != nil {
+		t.Fatalf("Failed to parse configuration: %v", err)
+	}
+
+	examples := tfdocs.GenerateExamples(config, "network")
+	for name, content := range examples {
+		if strings.Contains(content, "child_input") || strings.Contains(content, "child_output") {
+			t.Errorf("Expected %s not to use the variables or outputs of the child module, got:\n%s", name, content)
+		}
+	}
+	if complete := examples["examples/complete/main.tf"]; !strings.Contains(complete, "vpc_cidr") {
+		t.Errorf("Expected the complete example to set the inputs of the root module, got:\n%s", complete)
+	}
+}
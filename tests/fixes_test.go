@@ -0,0 +1,265 @@
+// tests/fixes_test.go
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"terraform-mcp-server/pkg/hashicorp/tfdocs"
+)
+
+func TestApplyIssueFixes(t *testing.T) {
+	files := map[string]string{
+		"variables.tf": `variable "db-password" {
+  type = string
+}
+
+variable "subnet_ids" {
+  type = list(string)
+}
+`,
+		"terraform.tfvars": "db-password = \"example\"\n",
+		"tests/main.tftest.hcl": `variables {
+  db-password = "example"
+}
+
+run "plan" {
+  command = plan
+
+  variables {
+    db-password = "other"
+  }
+
+  assert {
+    condition     = var.db-password != ""
+    error_message = "The password must be set."
+  }
+}
+`,
+		"main.tf": `resource "aws_instance" "web" {
+  count     = length(var.subnet_ids)
+  subnet_id = var.subnet_ids[count.index]
+  password  = var.db-password
+
+  tags = {
+    Name = "web-${var.subnet_ids[count.index]}"
+  }
+}
+`,
+	}
+	config := &tfdocs.TerraformConfiguration{Files: files}
+
+	var issues []tfdocs.ValidationIssue
+	issues = append(issues, (&tfdocs.NamingValidator{}).Validate(config)...)
+	issues = append(issues, (&tfdocs.SecurityValidator{}).Validate(config)...)
+	issues = append(issues, (&tfdocs.DocumentationValidator{}).Validate(config)...)
+	issues = append(issues, (&tfdocs.ResourceValidator{}).Validate(config)...)
+
+	result := tfdocs.ApplyIssueFixes(files, issues)
+	if len(result.Applied) == 0 {
+		t.Fatalf("Expected fixes to be applied, skipped: %v", result.Skipped)
+	}
+
+	variables := result.Files["variables.tf"]
+	for _, expected := range []string{
+		`variable "db_password" {`,
+		`sensitive = true`,
+		`description = "The subnet ids"`,
+	} {
+		if !strings.Contains(variables, expected) {
+			t.Errorf("Expected variables.tf to contain %q, got:\n%s", expected, variables)
+		}
+	}
+
+	main := result.Files["main.tf"]
+	for _, expected := range []string{
+		`for_each = toset(var.subnet_ids)`,
+		`subnet_id = each.value`,
+		`password  = var.db_password`,
+		`Name = "web-${each.value}"`,
+	} {
+		if !strings.Contains(main, expected) {
+			t.Errorf("Expected main.tf to contain %q, got:\n%s", expected, main)
+		}
+	}
+
+	if tfvars := result.Files["terraform.tfvars"]; tfvars != "db_password = \"example\"\n" {
+		t.Errorf("Expected the tfvars assignment to be renamed, got:\n%s", tfvars)
+	}
+	if test := result.Files["tests/main.tftest.hcl"]; strings.Contains(test, "db-password") || strings.Count(test, "db_password") != 3 {
+		t.Errorf("Expected the test assignments and references to be renamed, got:\n%s", test)
+	}
+
+	// The fixed configuration must still parse
+	fixed := &tfdocs.TerraformConfiguration{Files: result.Files}
+	for _, file := range fixed.ParsedFiles() {
+		if file.Diagnostics.HasErrors() {
+			t.Errorf("Fixed file %s does not parse: %s", file.Name, file.Diagnostics.Error())
+		}
+	}
+}
+
+func TestRenameVariableFixSkipsCalledModules(t *testing.T) {
+	config := &tfdocs.TerraformConfiguration{Files: map[string]string{
+		"main.tf":            "module \"db\" {\n  source      = \"./modules/db\"\n  db-password = \"example\"\n}\n",
+		"modules/db/main.tf": "variable \"db-password\" {\n  description = \"The password\"\n  type        = string\n  sensitive   = true\n}\n",
+	}}
+
+	result, err := tfdocs.NewValidationEngine(nil, &mockLogger{}).ValidateConfiguration(config)
+	if err != nil {
+		t.Fatalf("Failed to validate configuration: %v", err)
+	}
+	found := false
+	for _, issue := range result.Issues {
+		if issue.RuleID != "variable-naming" {
+			continue
+		}
+		found = true
+		if issue.Fix != nil {
+			t.Errorf("Expected no fix renaming an input of a called module, got %q", issue.Fix.Description)
+		}
+	}
+	if !found {
+		t.Errorf("Expected the variable name to be reported")
+	}
+}
+
+func TestCountToForEachFix(t *testing.T) {
+	variables := `variable "subnet_ids" {
+  description = "The subnet ids"
+  type        = list(string)
+}
+
+variable "subnets" {
+  description = "The subnets"
+  type        = list(object({ id = string }))
+}
+`
+	tests := []struct {
+		name string
+		main string
+		fix  bool
+	}{
+		{
+			name: "element access only",
+			main: "resource \"aws_instance\" \"web\" {\n  count     = length(var.subnet_ids)\n  subnet_id = var.subnet_ids[count.index]\n}\n",
+			fix:  true,
+		},
+		{
+			name: "count.index used as a number",
+			main: "resource \"aws_instance\" \"web\" {\n  count     = length(var.subnet_ids)\n  subnet_id = var.subnet_ids[count.index]\n  tags      = { Name = \"web-${count.index + 1}\" }\n}\n",
+		},
+		{
+			name: "other collection indexed",
+			main: "resource \"aws_instance\" \"web\" {\n  count             = length(var.subnet_ids)\n  availability_zone = var.azs[count.index]\n}\n",
+		},
+		{
+			name: "element attribute",
+			main: "resource \"aws_instance\" \"web\" {\n  count     = length(var.subnets)\n  subnet_id = var.subnets[count.index].id\n}\n",
+		},
+		{
+			name: "instances referenced by index",
+			main: "resource \"aws_instance\" \"web\" {\n  count     = length(var.subnet_ids)\n  subnet_id = var.subnet_ids[count.index]\n}\n\noutput \"first\" {\n  value = aws_instance.web[0].id\n}\n",
+		},
+		{
+			name: "instances referenced by splat",
+			main: "resource \"aws_instance\" \"web\" {\n  count     = length(var.subnet_ids)\n  subnet_id = var.subnet_ids[count.index]\n}\n\noutput \"ids\" {\n  value = aws_instance.web[*].id\n}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &tfdocs.TerraformConfiguration{Files: map[string]string{"variables.tf": variables, "main.tf": tt.main}}
+			issues := (&tfdocs.ResourceValidator{}).Validate(config)
+			if len(issues) != 1 {
+				t.Fatalf("Expected 1 issue, got %d", len(issues))
+			}
+			if !strings.Contains(issues[0].Suggestion, "moved blocks") {
+				t.Errorf("Expected the suggestion to mention moved blocks, got %q", issues[0].Suggestion)
+			}
+			if (issues[0].Fix != nil) != tt.fix {
+				t.Errorf("Expected fix %v, got %+v", tt.fix, issues[0].Fix)
+			}
+		})
+	}
+}
+
+func TestApplyEditsSkipsOverlaps(t *testing.T) {
+	edit := tfdocs.TextEdit{
+		File:    "main.tf",
+		Start:   tfdocs.Position{Line: 1, Column: 1, Byte: 0},
+		End:     tfdocs.Position{Line: 1, Column: 4, Byte: 3},
+		NewText: "bar",
+	}
+	issues := []tfdocs.ValidationIssue{
+		{RuleID: "first", Fix: &tfdocs.Fix{Description: "first", Edits: []tfdocs.TextEdit{edit}}},
+		{RuleID: "second", Fix: &tfdocs.Fix{Description: "second", Edits: []tfdocs.TextEdit{edit}}},
+	}
+
+	result := tfdocs.ApplyIssueFixes(map[string]string{"main.tf": "foo = 1\n"}, issues)
+	if len(result.Applied) != 1 || len(result.Skipped) != 1 {
+		t.Fatalf("Expected 1 applied and 1 skipped fix, got %v and %v", result.Applied, result.Skipped)
+	}
+	if result.Files["main.tf"] != "bar = 1\n" {
+		t.Errorf("Unexpected result: %q", result.Files["main.tf"])
+	}
+}
+
+func TestApplyFixesDefaults(t *testing.T) {
+	config, err := tfdocs.ParseTerraformConfiguration(map[string]string{
+		"main.tf":      "resource \"aws_db_instance\" \"this\" {\n  password          = var.api_token\n  storage_encrypted = true\n}\n",
+		"variables.tf": "variable \"api_token\" {\n}\n",
+	})
+	if err != nil {
+		t.Fatalf("Failed to parse configuration: %v", err)
+	}
+
+	result, err := tfdocs.NewValidationEngine(nil, &mockLogger{}).ApplyFixes(config, nil)
+	if err != nil {
+		t.Fatalf("Failed to apply fixes: %v", err)
+	}
+
+	// Attributes several fixes add to one block are all inserted
+	variables := result.Files["variables.tf"]
+	for _, expected := range []string{"description = ", "sensitive = true", "type = string"} {
+		if !strings.Contains(variables, expected) {
+			t.Errorf("Expected variables.tf to contain %q, got:\n%s\nskipped: %v", expected, variables, result.Skipped)
+		}
+	}
+	fixed := &tfdocs.TerraformConfiguration{Files: result.Files}
+	for _, file := range fixed.ParsedFiles() {
+		if file.Diagnostics.HasErrors() {
+			t.Errorf("Fixed file %s does not parse: %s", file.Name, file.Diagnostics.Error())
+		}
+	}
+
+	// Info-level fixes such as generated tests need their rule ID
+	for _, fix := range result.Applied {
+		if fix.RuleID == "module-tests" || fix.RuleID == "suggested-outputs" {
+			t.Errorf("Expected info-level fix %s not to be applied by default", fix.RuleID)
+		}
+	}
+	if _, ok := result.Files["tests/main.tftest.hcl"]; ok {
+		t.Errorf("Expected no tests to be generated by default")
+	}
+}
+
+func TestApplyEditsInsertionOrder(t *testing.T) {
+	insert := func(text string) tfdocs.TextEdit {
+		return tfdocs.TextEdit{File: "main.tf", Start: tfdocs.Position{Line: 1, Column: 4, Byte: 3}, End: tfdocs.Position{Line: 1, Column: 4, Byte: 3}, NewText: text}
+	}
+	replace := tfdocs.TextEdit{File: "main.tf", Start: tfdocs.Position{Line: 1, Column: 4, Byte: 3}, End: tfdocs.Position{Line: 1, Column: 5, Byte: 4}, NewText: "!"}
+	issues := []tfdocs.ValidationIssue{
+		{RuleID: "first", Fix: &tfdocs.Fix{Description: "first", Edits: []tfdocs.TextEdit{insert("1")}}},
+		{RuleID: "second", Fix: &tfdocs.Fix{Description: "second", Edits: []tfdocs.TextEdit{insert("2")}}},
+		{RuleID: "third", Fix: &tfdocs.Fix{Description: "third", Edits: []tfdocs.TextEdit{replace}}},
+	}
+
+	result := tfdocs.ApplyIssueFixes(map[string]string{"main.tf": "abc {}\n"}, issues)
+	if len(result.Applied) != 2 || len(result.Skipped) != 1 || result.Skipped[0].RuleID != "third" {
+		t.Fatalf("Expected the insertions to apply and the replacement to be skipped, got %v and %v", result.Applied, result.Skipped)
+	}
+	if result.Files["main.tf"] != "abc12 {}\n" {
+		t.Errorf("Expected insertions in issue order, got %q", result.Files["main.tf"])
+	}
+}
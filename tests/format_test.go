@@ -0,0 +1,150 @@
+// tests/format_test.go
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"terraform-mcp-server/pkg/hashicorp/tfdocs"
+)
+
+const unformattedMain = `resource "aws_instance" "web" {
+ami = "ami-123"
+  instance_type   =   "t3.micro"
+}
+
+output "id" {
+  value = aws_instance.web.id
+}
+`
+
+const formattedMain = `resource "aws_instance" "web" {
+  ami           = "ami-123"
+  instance_type = "t3.micro"
+}
+
+output "id" {
+  value = aws_instance.web.id
+}
+`
+
+func TestFormatConfiguration(t *testing.T) {
+	config := &tfdocs.TerraformConfiguration{
+		Files: map[string]string{
+			"main.tf":              unformattedMain,
+			"outputs.tf":           formattedMain,
+			"prod.tfvars":          "region=\"eu-west-1\"\n",
+			"broken.tf":            "resource \"aws_instance\" {\n",
+			"README.md":            "# Example\n",
+			"tests/web.tftest.hcl": "run \"plan\" {\ncommand = plan\n}\n",
+		},
+	}
+
+	result := tfdocs.FormatConfiguration(config)
+
+	if strings.Join(result.Changed, ",") != "main.tf,prod.tfvars,tests/web.tftest.hcl" {
+		t.Errorf("Unexpected changed files: %v", result.Changed)
+	}
+	if result.Files["main.tf"] != formattedMain {
+		t.Errorf("Unexpected formatting:\n%s", result.Files["main.tf"])
+	}
+	if result.Files["prod.tfvars"] != "region = \"eu-west-1\"\n" {
+		t.Errorf("Unexpected formatting of variable file: %q", result.Files["prod.tfvars"])
+	}
+	if result.Files["README.md"] != "# Example\n" || result.Files["broken.tf"] != config.Files["broken.tf"] {
+		t.Errorf("Expected other files and files with syntax errors to be unchanged")
+	}
+	if _, ok := result.Errors["broken.tf"]; !ok || len(result.Errors) != 1 {
+		t.Errorf("Expected a syntax error for broken.tf, got %v", result.Errors)
+	}
+	if !strings.Contains(result.Diffs["main.tf"], "-ami = \"ami-123\"\n-  instance_type   =   \"t3.micro\"\n+  ami           = \"ami-123\"\n") {
+		t.Errorf("Unexpected diff:\n%s", result.Diffs["main.tf"])
+	}
+	if !strings.Contains(result.Patch, "diff --git a/prod.tfvars b/prod.tfvars") {
+		t.Errorf("Expected the patch to include prod.tfvars, got:\n%s", result.Patch)
+	}
+}
+
+func TestFormattingRule(t *testing.T) {
+	config := &tfdocs.TerraformConfiguration{
+		Files: map[string]string{
+			"main.tf":    unformattedMain,
+			"outputs.tf": formattedMain,
+		},
+	}
+
+	issues := (&tfdocs.FormatValidator{}).Validate(config)
+	if len(issues) != 1 {
+		t.Fatalf("Expected 1 formatting issue, got %d", len(issues))
+	}
+
+	issue := issues[0]
+	if issue.RuleID != "formatting" || issue.File != "main.tf" || issue.Line != 2 {
+		t.Errorf("Expected a formatting issue at main.tf:2, got %s at %s:%d", issue.RuleID, issue.File, issue.Line)
+	}
+	if !strings.Contains(issue.Diff, "+  instance_type = \"t3.micro\"") {
+		t.Errorf("Expected the issue to carry the diff, got:\n%s", issue.Diff)
+	}
+
+	// The fix only touches the changed lines
+	if len(issue.Fix.Edits) != 1 || issue.Fix.Edits[0].End.Line != 4 {
+		t.Errorf("Expected one edit of lines 2 to 3, got %+v", issue.Fix.Edits)
+	}
+	if fixed := tfdocs.ApplyEdits(unformattedMain, issue.Fix.Edits); fixed != formattedMain {
+		t.Errorf("Unexpected result of the fix:\n%s", fixed)
+	}
+}
+
+func TestSuggestImprovementsAreFormatted(t *testing.T) {
+	engine := tfdocs.NewValidationEngine(nil, &mockLogger{})
+
+	config := &tfdocs.TerraformConfiguration{
+		Files: map[string]string{
+			"main.tf": `variable "db_password" {
+  type = string
+}
+
+resource "aws_instance" "web" {
+ami = "ami-123"
+}
+`,
+		},
+	}
+
+	improvements, err := engine.SuggestImprovements(config)
+	if err != nil {
+		t.Fatalf("Failed to suggest improvements: %v", err)
+	}
+	for name, content := range improvements {
+		if !strings.HasSuffix(name, ".tf") {
+			continue
+		}
+		if formatted, err := tfdocs.FormatHCL(name, content); err != nil || formatted != content {
+			t.Errorf("Expected %s to be formatted, got:\n%s", name, content)
+		}
+	}
+	if !strings.Contains(improvements["main.tf"], "  ami = \"ami-123\"") {
+		t.Errorf("Expected main.tf to be formatted, got:\n%s", improvements["main.tf"])
+	}
+}
+
+func TestSuggestImprovementsWithoutMainTF(t *testing.T) {
+	engine := tfdocs.NewValidationEngine(nil, &mockLogger{})
+
+	config := &tfdocs.TerraformConfiguration{
+		Files: map[string]string{
+			"network.tf": "resource \"azurerm_virtual_network\" \"this\" {\n  name          = \"example\"\n  address_space = [\"10.0.0.0/16\"]\n}\n",
+		},
+	}
+
+	improvements, err := engine.SuggestImprovements(config)
+	if err != nil {
+		t.Fatalf("Failed to suggest improvements: %v", err)
+	}
+	if main, ok := improvements["main.tf"]; ok {
+		t.Errorf("Expected no main.tf to be proposed, got:\n%s", main)
+	}
+	if versions := improvements["versions.tf"]; !strings.Contains(versions, "azurerm") || strings.Contains(versions, "aws") {
+		t.Errorf("Expected versions.tf to require the azurerm provider only, got:\n%s", versions)
+	}
+}
@@ -0,0 +1,200 @@
+// tests/graph_test.go
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"terraform-mcp-server/pkg/hashicorp/tfdocs"
+)
+
+func referenceFixture() *tfdocs.TerraformConfiguration {
+	return &tfdocs.TerraformConfiguration{
+		Files: map[string]string{
+			"main.tf": `locals {
+  name   = "${var.prefix}-web"
+  unused = "never referenced"
+}
+
+resource "aws_security_group" "web" {
+  name = local.name
+
+  dynamic "ingress" {
+    for_each = var.ports
+    content {
+      from_port = ingress.value
+      to_port   = ingress.value
+    }
+  }
+
+  lifecycle {
+    ignore_changes = [tags]
+  }
+}
+
+resource "aws_instance" "web" {
+  ami                    = data.aws_ami.ubuntu.id
+  vpc_security_group_ids = [aws_security_group.web.id]
+  subnet_id              = var.subnet_id
+}
+`,
+			"variables.tf": `variable "prefix" {
+  type = string
+}
+
+variable "ports" {
+  type = list(number)
+}
+
+variable "legacy" {
+  type = string
+
+  validation {
+    condition     = length(var.legacy) > 0
+    error_message = "Must not be empty."
+  }
+}
+`,
+			"outputs.tf": `output "instance_id" {
+  value = aws_instance.web.id
+}
+
+output "bucket_arn" {
+  value = aws_s3_bucket.logs.arn
+}
+`,
+		},
+	}
+}
+
+func TestReferenceGraph(t *testing.T) {
+	graph := referenceFixture().ReferenceGraph()
+
+	for _, address := range []string{"var.prefix", "local.name", "aws_instance.web", "output.instance_id"} {
+		if _, ok := graph.Symbols[address]; !ok {
+			t.Errorf("Expected symbol %s", address)
+		}
+	}
+
+	refs := graph.ReferencesTo("aws_security_group.web")
+	if len(refs) != 1 || refs[0].From != "aws_instance.web" || refs[0].Line != 24 {
+		t.Errorf("Expected aws_instance.web to reference aws_security_group.web at line 24, got %v", refs)
+	}
+	for _, ref := range graph.References {
+		if ref.To == "ingress.value" || ref.To == "tags" {
+			t.Errorf("Expected dynamic iterators and ignore_changes to be skipped, got %v", ref)
+		}
+	}
+}
+
+func TestReferenceValidator(t *testing.T) {
+	issues := (&tfdocs.ReferenceValidator{}).Validate(referenceFixture())
+
+	byRule := make(map[string][]tfdocs.ValidationIssue)
+	for _, issue := range issues {
+		byRule[issue.RuleID] = append(byRule[issue.RuleID], issue)
+	}
+
+	if got := byRule["undefined-references"]; len(got) != 2 {
+		t.Errorf("Expected undeclared var.subnet_id and data.aws_ami.ubuntu, got %v", got)
+	}
+	if got := byRule["output-undefined-references"]; len(got) != 1 || got[0].File != "outputs.tf" || got[0].Line != 6 {
+		t.Errorf("Expected bucket_arn to reference a missing resource at outputs.tf:6, got %v", got)
+	}
+	// References from a variable's own validation block do not count as uses
+	if got := byRule["unused-variables"]; len(got) != 1 || got[0].Line != 9 {
+		t.Errorf("Expected legacy to be unused at variables.tf:9, got %v", got)
+	}
+	if got := byRule["unused-locals"]; len(got) != 1 || got[0].Line != 3 {
+		t.Errorf("Expected local unused to be reported at main.tf:3, got %v", got)
+	}
+}
+
+func TestReferenceGraphNonSymbolBlocks(t *testing.T) {
+	config := &tfdocs.TerraformConfiguration{
+		Files: map[string]string{
+			"main.tf": `provider "aws" {
+  region = var.region
+}
+
+provider "aws" {
+  alias  = "replica"
+  region = var.replica_region
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+
+check "health" {
+  data "http" "endpoint" {
+    url = var.health_url
+  }
+
+  assert {
+    condition     = data.http.endpoint.status_code == 200
+    error_message = "The endpoint is unhealthy."
+  }
+}
+
+moved {
+  from = aws_s3_bucket.old
+  to   = aws_s3_bucket.logs
+}
+
+import {
+  to = aws_s3_bucket.logs
+  id = var.bucket_id
+}
+`,
+			"variables.tf": `variable "region" {
+  type = string
+}
+
+variable "health_url" {
+  type = string
+}
+
+variable "bucket_id" {
+  type = string
+}
+`,
+		},
+	}
+
+	graph := config.ReferenceGraph()
+	if refs := graph.ReferencesTo("var.region"); len(refs) != 1 || refs[0].From != "provider.aws" {
+		t.Errorf("Expected provider.aws to reference var.region, got %v", refs)
+	}
+	if refs := graph.ReferencesTo("var.health_url"); len(refs) != 1 || refs[0].From != "check.health" {
+		t.Errorf("Expected check.health to reference var.health_url, got %v", refs)
+	}
+	if _, ok := graph.Symbols["provider.aws"]; ok {
+		t.Errorf("Expected provider blocks not to be symbols")
+	}
+
+	issues := (&tfdocs.ReferenceValidator{}).Validate(config)
+	var rules []string
+	for _, issue := range issues {
+		rules = append(rules, issue.RuleID+":"+issue.Message)
+	}
+	if len(issues) != 1 || issues[0].RuleID != "undefined-references" || !strings.Contains(issues[0].Message, "var.replica_region") {
+		t.Errorf("Expected only the undeclared var.replica_region of the provider, got %v", rules)
+	}
+}
+
+func TestUndeclaredProviderVariables(t *testing.T) {
+	config := &tfdocs.TerraformConfiguration{
+		Files: map[string]string{
+			"main.tf": "provider \"aws\" {\n  region = var.region\n}\n\nresource \"aws_s3_bucket\" \"logs\" {\n  bucket = \"logs\"\n}\n",
+		},
+	}
+
+	improvements, err := tfdocs.NewValidationEngine(nil, &mockLogger{}).SuggestImprovements(config)
+	if err != nil {
+		t.Fatalf("Failed to suggest improvements: %v", err)
+	}
+	if variables := improvements["variables.tf"]; !strings.Contains(variables, "variable \"region\" {") || !strings.Contains(variables, "type        = string") {
+		t.Errorf("Expected variables.tf to declare region, got:\n%s", variables)
+	}
+}
@@ -0,0 +1,195 @@
+// tests/interface_test.go
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"terraform-mcp-server/pkg/hashicorp/tfdocs"
+)
+
+func TestGenerateModuleInterface(t *testing.T) {
+	config := &tfdocs.TerraformConfiguration{
+		Files: map[string]string{
+			"variables.tf": `variable "prefix" {
+  description = "Prefix of resource names"
+  type        = string
+}`,
+			"main.tf": `resource "aws_vpc" "main" {
+  cidr_block           = var.vpc_cidr
+  enable_dns_hostnames = !var.private_dns
+}
+
+resource "aws_subnet" "private" {
+  count             = length(var.private_subnet_cidrs)
+  vpc_id            = aws_vpc.main.id
+  cidr_block        = var.private_subnet_cidrs[count.index]
+  availability_zone = var.azs[count.index]
+}
+
+resource "aws_s3_bucket" "this" {
+  bucket        = "${var.prefix}-logs"
+  force_destroy = var.force_destroy
+  tags          = merge(var.tags, { Name = "logs" })
+}
+
+resource "aws_db_instance" "main" {
+  identifier            = var.settings.name
+  allocated_storage     = var.storage_gb * 2
+  max_allocated_storage = var.settings.max_storage
+  password              = var.db_password
+}
+
+output "vpc_id" {
+  description = "The ID of the VPC"
+  value       = aws_vpc.main.id
+}
+`,
+		},
+	}
+
+	engine := tfdocs.NewValidationEngine(nil, &mockLogger{})
+	result, err := engine.ApplyFixes(config, []string{"undefined-references", "suggested-outputs"})
+	if err != nil {
+		t.Fatalf("Failed to apply fixes: %v", err)
+	}
+	if len(result.Applied) != 2 {
+		t.Fatalf("Expected one fix per rule to be applied, got %v", result.Applied)
+	}
+
+	// Undeclared variables are appended to variables.tf with inferred types
+	expectedVariables := `variable "prefix" {
+  description = "Prefix of resource names"
+  type        = string
+}
+
+variable "azs" {
+  description = "The azs"
+  type        = list(string)
+}
+
+variable "db_password" {
+  description = "The db password"
+  type        = string
+  sensitive   = true
+}
+
+variable "force_destroy" {
+  description = "The force destroy"
+  type        = bool
+}
+
+variable "private_dns" {
+  description = "The private dns"
+  type        = bool
+}
+
+variable "private_subnet_cidrs" {
+  description = "The private subnet cidrs"
+  type        = list(string)
+}
+
+variable "settings" {
+  description = "The settings"
+  type        = object({ max_storage = number, name = string })
+}
+
+variable "storage_gb" {
+  description = "The storage gb"
+  type        = number
+}
+
+variable "tags" {
+  description = "The tags"
+  type        = map(string)
+}
+
+variable "vpc_cidr" {
+  description = "The vpc cidr"
+  type        = string
+}
+`
+	if got := result.Files["variables.tf"]; got != expectedVariables {
+		t.Errorf("Unexpected variables.tf:\n%s", got)
+	}
+
+	// Key attributes that are not exposed yet go to a new outputs.tf
+	outputs := result.Files["outputs.tf"]
+	for _, want := range []string{
+		"# Output values from the module\n\n",
+		"output \"private_subnet_ids\" {\n  description = \"The ID of each instance of aws_subnet.private\"\n  value       = aws_subnet.private[*].id\n}\n",
+		"output \"s3_bucket_arn\" {\n  description = \"The ARN of aws_s3_bucket.this\"\n  value       = aws_s3_bucket.this.arn\n}\n",
+		"output \"db_instance_endpoint\" {",
+	} {
+		if !strings.Contains(outputs, want) {
+			t.Errorf("Expected outputs.tf to contain %q, got:\n%s", want, outputs)
+		}
+	}
+	if strings.Contains(outputs, "aws_vpc.main.id") {
+		t.Errorf("Expected the exposed VPC ID to be skipped, got:\n%s", outputs)
+	}
+
+	// The fixed configuration has no undeclared variables or missing outputs
+	fixed, err := engine.ValidateConfiguration(&tfdocs.TerraformConfiguration{Files: result.Files})
+	if err != nil {
+		t.Fatalf("Failed to validate configuration: %v", err)
+	}
+	for _, issue := range fixed.Issues {
+		if issue.RuleID == "undefined-references" || issue.RuleID == "suggested-outputs" {
+			t.Errorf("Unexpected issue after applying fixes: %s", issue.Message)
+		}
+	}
+
+	// SuggestImprovements generates both files when they are missing
+	delete(config.Files, "variables.tf")
+	improvements, err := engine.SuggestImprovements(config)
+	if err != nil {
+		t.Fatalf("Failed to suggest improvements: %v", err)
+	}
+	if !strings.Contains(improvements["variables.tf"], "variable \"prefix\" {\n  description = \"The prefix\"\n  type        = string\n}") {
+		t.Errorf("Expected variables.tf to declare prefix, got:\n%s", improvements["variables.tf"])
+	}
+	if _, ok := improvements["outputs.tf"]; !ok {
+		t.Errorf("Expected outputs.tf to be generated")
+	}
+}
+
+func TestInferredTypePrefersArguments(t *testing.T) {
+	config := &tfdocs.TerraformConfiguration{
+		Files: map[string]string{
+			"main.tf": `resource "aws_instance" "web" {
+  instance_type = var.size
+  ami           = var.image
+}
+
+resource "aws_ebs_volume" "data" {
+  size      = var.port
+  encrypted = true
+}
+
+output "timeout" {
+  description = "The timeout"
+  value       = var.timeout
+}
+`,
+		},
+	}
+
+	improvements, err := tfdocs.NewValidationEngine(nil, &mockLogger{}).SuggestImprovements(config)
+	if err != nil {
+		t.Fatalf("Failed to suggest improvements: %v", err)
+	}
+	variables := improvements["variables.tf"]
+	for _, want := range []string{
+		// The argument a variable is passed to wins over its name
+		"variable \"size\" {\n  description = \"The size\"\n  type        = string\n}",
+		"variable \"image\" {\n  description = \"The image\"\n  type        = string\n}",
+		"variable \"port\" {\n  description = \"The port\"\n  type        = number\n}",
+		// Without a typed usage the name decides
+		"variable \"timeout\" {\n  description = \"The timeout\"\n  type        = number\n}",
+	} {
+		if !strings.Contains(variables, want) {
+			t.Errorf("Expected variables.tf to contain %q, got:\n%s", want, variables)
+		}
+	}
+}
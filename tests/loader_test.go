@@ -0,0 +1,113 @@
+// tests/loader_test.go
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"terraform-mcp-server/pkg/hashicorp/tfdocs"
+)
+
+func writeTestFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+}
+
+func TestIgnoreRules(t *testing.T) {
+	rules := tfdocs.ParseIgnorePatterns([]string{
+		"# comment",
+		"*.tfstate",
+		"build/",
+		"/docs/*.md",
+		"**/fixtures/**",
+		"!docs/keep.md",
+	})
+
+	tests := []struct {
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{"terraform.tfstate", false, true},
+		{"envs/prod/terraform.tfstate", false, true},
+		{"build", true, true},
+		{"build", false, false},
+		{"docs/usage.md", false, true},
+		{"docs/keep.md", false, false},
+		{"modules/docs/usage.md", false, false},
+		{"tests/fixtures/main.tf", false, true},
+		{"main.tf", false, false},
+	}
+	for _, tt := range tests {
+		if got := rules.Match(tt.path, tt.isDir); got != tt.ignored {
+			t.Errorf("Match(%q, %v) = %v, expected %v", tt.path, tt.isDir, got, tt.ignored)
+		}
+	}
+}
+
+func TestDiscoverModules(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		"main.tf":                "variable \"name\" {}\n",
+		"README.md":              "# Root\n",
+		"modules/vpc/main.tf":    "variable \"cidr\" {}\n",
+		"modules/vpc/notes.txt":  "not loaded\n",
+		"examples/basic/main.tf": "module \"root\" {\n  source = \"../..\"\n}\n",
+		".terraform/mod/main.tf": "ignored by default\n",
+		"scratch/main.tf":        "ignored by .terraformignore\n",
+		"docs/README.md":         "not a module\n",
+		".terraformignore":       "scratch/\nexamples/\n",
+	})
+
+	modules, err := tfdocs.DiscoverModules(root)
+	if err != nil {
+		t.Fatalf("Failed to discover modules: %v", err)
+	}
+
+	var paths []string
+	for _, module := range modules {
+		paths = append(paths, module.Path)
+	}
+	if len(paths) != 2 || paths[0] != "." || paths[1] != "modules/vpc" {
+		t.Fatalf("Expected modules [. modules/vpc], got %v", paths)
+	}
+	if _, ok := modules[0].Config.Files["README.md"]; !ok {
+		t.Errorf("Expected README.md to be loaded with the root module")
+	}
+	if _, ok := modules[1].Config.Files["notes.txt"]; ok {
+		t.Errorf("Expected notes.txt not to be loaded")
+	}
+
+	// Issues are reported relative to the root
+	engine := tfdocs.NewValidationEngine(nil, &mockLogger{})
+	result, config, err := engine.ValidateModules(modules)
+	if err != nil {
+		t.Fatalf("Failed to validate modules: %v", err)
+	}
+	if _, ok := config.Files["modules/vpc/main.tf"]; !ok {
+		t.Errorf("Expected combined configuration to contain modules/vpc/main.tf")
+	}
+
+	found := false
+	for _, issue := range result.Issues {
+		if issue.RuleID == "variable-description" && issue.File == "modules/vpc/main.tf" && issue.Line == 1 {
+			found = true
+		}
+		if issue.File == "" {
+			t.Errorf("Expected every issue to have a file, got %+v", issue)
+		}
+	}
+	if !found {
+		t.Errorf("Expected a variable-description issue in modules/vpc/main.tf, got %v", result.Issues)
+	}
+}
@@ -0,0 +1,130 @@
+// tests/modules_test.go
+package tests
+
+import (
+	"testing"
+
+	"terraform-mcp-server/pkg/hashicorp/tfdocs"
+)
+
+func moduleTreeFixture() *tfdocs.TerraformConfiguration {
+	return &tfdocs.TerraformConfiguration{
+		Files: map[string]string{
+			"main.tf": `module "vpc" {
+  source = "./modules/vpc"
+  name   = "main"
+}
+
+module "registry" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "5.0.0"
+}
+
+output "vpc_id" {
+  description = "The VPC ID"
+  value       = module.vpc.id
+}
+
+output "subnets" {
+  description = "The subnet IDs"
+  value       = module.vpc.subnet_ids
+}
+`,
+			"modules/vpc/variables.tf": `variable "name" {
+  description = "The VPC name"
+  type        = string
+}
+
+variable "cidr" {
+  type = string
+}
+
+variable "tags" {
+  description = "Tags to apply"
+  type        = map(string)
+  default     = {}
+}
+`,
+			"modules/vpc/outputs.tf": `output "id" {
+  description = "The VPC ID"
+  value       = "vpc-123"
+}
+`,
+			"modules/legacy/main.tf": `variable "unused" {
+  description = "Never set"
+}
+`,
+		},
+	}
+}
+
+func TestModuleTree(t *testing.T) {
+	tree := moduleTreeFixture().ModuleTree()
+
+	var paths []string
+	for _, module := range tree.Modules {
+		paths = append(paths, module.Path)
+	}
+	if len(paths) != 3 || paths[0] != "." || paths[1] != "modules/legacy" || paths[2] != "modules/vpc" {
+		t.Fatalf("Expected modules [. modules/legacy modules/vpc], got %v", paths)
+	}
+
+	if len(tree.Root.Calls) != 1 {
+		t.Fatalf("Expected 1 local module call, got %d", len(tree.Root.Calls))
+	}
+	call := tree.Root.Calls[0]
+	if call.Name != "vpc" || call.Module == nil || call.Module.Path != "modules/vpc" {
+		t.Errorf("Expected vpc to resolve to modules/vpc, got %+v", call)
+	}
+	if _, ok := call.Module.Config.Files["variables.tf"]; !ok {
+		t.Errorf("Expected module files to be keyed by their base name")
+	}
+}
+
+func TestModuleTreeValidator(t *testing.T) {
+	issues := (&tfdocs.ModuleTreeValidator{}).Validate(moduleTreeFixture())
+
+	byRule := make(map[string][]tfdocs.ValidationIssue)
+	for _, issue := range issues {
+		byRule[issue.RuleID] = append(byRule[issue.RuleID], issue)
+	}
+
+	if got := byRule["module-required-inputs"]; len(got) != 1 || got[0].File != "main.tf" || got[0].Line != 1 {
+		t.Errorf("Expected one missing input for cidr at main.tf:1, got %v", got)
+	}
+	if got := byRule["module-undeclared-outputs"]; len(got) != 1 || got[0].Line != 18 {
+		t.Errorf("Expected one undeclared output at main.tf:18, got %v", got)
+	}
+	if got := byRule["unused-local-modules"]; len(got) != 1 || got[0].File != "modules/legacy/main.tf" {
+		t.Errorf("Expected modules/legacy to be unused, got %v", got)
+	}
+}
+
+func TestValidateModuleTree(t *testing.T) {
+	engine := tfdocs.NewValidationEngine(nil, &mockLogger{})
+	result, err := engine.ValidateConfiguration(moduleTreeFixture())
+	if err != nil {
+		t.Fatalf("Failed to validate configuration: %v", err)
+	}
+
+	var childIssue, crossIssue bool
+	for _, issue := range result.Issues {
+		// Child modules are validated on their own, with paths relative to the root
+		if issue.RuleID == "variable-description" && issue.File == "modules/vpc/variables.tf" && issue.Line == 6 {
+			childIssue = true
+		}
+		if issue.RuleID == "module-required-inputs" {
+			crossIssue = true
+		}
+		// The root module contains no variables of its own
+		if issue.RuleID == "variable-description" && issue.File == "main.tf" {
+			t.Errorf("Expected child variables not to be reported in the root module: %+v", issue)
+		}
+	}
+	if !childIssue {
+		t.Errorf("Expected the undocumented cidr variable to be reported in modules/vpc/variables.tf")
+	}
+	if !crossIssue {
+		t.Errorf("Expected cross-module issues to be reported")
+	}
+}
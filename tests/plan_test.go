@@ -0,0 +1,167 @@
+// tests/plan_test.go
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"terraform-mcp-server/pkg/hashicorp/tfdocs"
+)
+
+const testPlan = `{
+  "format_version": "1.2",
+  "terraform_version": "1.6.0",
+  "planned_values": {
+    "root_module": {
+      "resources": [
+        {
+          "address": "aws_security_group.web",
+          "mode": "managed",
+          "type": "aws_security_group",
+          "name": "web",
+          "values": {
+            "name": "web",
+            "ingress": [
+              {"cidr_blocks": ["10.0.0.0/8"], "ipv6_cidr_blocks": [], "from_port": 443, "to_port": 443, "protocol": "tcp"},
+              {"cidr_blocks": ["0.0.0.0/0"], "ipv6_cidr_blocks": [], "from_port": 22, "to_port": 22, "protocol": "tcp"}
+            ],
+            "tags_all": {"Environment": "prod", "Owner": "platform", "CostCenter": "cc-1234"}
+          }
+        },
+        {
+          "address": "aws_db_instance.main",
+          "mode": "managed",
+          "type": "aws_db_instance",
+          "name": "main",
+          "values": {
+            "engine": "postgres",
+            "publicly_accessible": true,
+            "storage_encrypted": true,
+            "tags_all": {"Environment": "qa", "Owner": "platform", "CostCenter": "cc-1234"}
+          }
+        },
+        {
+          "address": "data.aws_caller_identity.current",
+          "mode": "data",
+          "type": "aws_caller_identity",
+          "name": "current",
+          "values": {}
+        }
+      ],
+      "child_modules": [
+        {
+          "address": "module.app",
+          "resources": [
+            {
+              "address": "module.app.aws_instance.web[0]",
+              "mode": "managed",
+              "type": "aws_instance",
+              "name": "web",
+              "index": 0,
+              "values": {
+                "ami": "ami-123",
+                "tags_all": {"Environment": "prod"}
+              }
+            }
+          ]
+        }
+      ]
+    }
+  },
+  "resource_changes": [
+    {
+      "address": "aws_db_instance.main",
+      "mode": "managed",
+      "type": "aws_db_instance",
+      "name": "main",
+      "change": {"actions": ["delete", "create"], "replace_paths": [["engine"]]}
+    },
+    {
+      "address": "aws_s3_bucket.logs",
+      "mode": "managed",
+      "type": "aws_s3_bucket",
+      "name": "logs",
+      "change": {"actions": ["delete"]}
+    },
+    {
+      "address": "aws_security_group.web",
+      "mode": "managed",
+      "type": "aws_security_group",
+      "name": "web",
+      "change": {"actions": ["update"]}
+    },
+    {
+      "address": "module.app.aws_instance.web[0]",
+      "module_address": "module.app",
+      "mode": "managed",
+      "type": "aws_instance",
+      "name": "web",
+      "change": {"actions": ["create", "delete"]}
+    }
+  ]
+}`
+
+func TestValidatePlan(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{"tags.yaml": testTagPolicy})
+
+	engine := tfdocs.NewValidationEngine(nil, &mockLogger{}, tfdocs.WithPolicyPath(dir))
+	if err := engine.Initialize(context.Background()); err != nil {
+		t.Fatalf("Failed to initialize validation engine: %v", err)
+	}
+
+	plan, err := tfdocs.ParsePlan([]byte(testPlan))
+	if err != nil {
+		t.Fatalf("Failed to parse plan: %v", err)
+	}
+	if plan.ResourceChanges[0].Action() != tfdocs.ActionReplace || plan.ResourceChanges[3].Action() != tfdocs.ActionReplace {
+		t.Errorf("Expected delete/create and create/delete to be replacements")
+	}
+	if got := len(plan.Resources()); got != 3 {
+		t.Errorf("Expected 3 managed resources across modules, got %d", got)
+	}
+
+	result, err := engine.ValidatePlan(plan)
+	if err != nil {
+		t.Fatalf("Failed to validate plan: %v", err)
+	}
+
+	messages := make(map[string][]string)
+	for _, issue := range result.Issues {
+		messages[issue.RuleID] = append(messages[issue.RuleID], issue.Message)
+	}
+
+	// The replaced instance is not stateful
+	expected := map[string][]string{
+		"plan-stateful-destroy": {
+			"Plan replaces stateful resource 'aws_db_instance.main' because engine cannot be updated in place",
+			"Plan destroys stateful resource 'aws_s3_bucket.logs'",
+		},
+		"open-ssh-rdp":           {"'aws_security_group.web' allows SSH (port 22) from 0.0.0.0/0"},
+		"database-public-access": {"'aws_db_instance.main' is publicly accessible"},
+		"required-tags":          {"Resource 'module.app.aws_instance.web[0]' is missing required tags: Owner, CostCenter"},
+		"tag-values":             {"Tag 'Environment' of resource 'aws_db_instance.main' has value 'qa', which must be one of dev, staging, prod"},
+	}
+	for rule, want := range expected {
+		got := messages[rule]
+		if len(got) != len(want) {
+			t.Errorf("%s: expected %v, got %v", rule, want, got)
+			continue
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("%s: expected %q, got %q", rule, want[i], got[i])
+			}
+		}
+	}
+	if len(messages["open-ingress"]) != 0 {
+		t.Errorf("Expected the private HTTPS rule to pass, got %v", messages["open-ingress"])
+	}
+	if result.ErrorCount < 4 {
+		t.Errorf("Expected at least 4 errors, got %d", result.ErrorCount)
+	}
+
+	if _, err := tfdocs.ParsePlan([]byte(`{"version": 4, "resources": []}`)); err == nil {
+		t.Errorf("Expected a state file to be rejected as a plan")
+	}
+}
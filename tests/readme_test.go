@@ -0,0 +1,149 @@
+// tests/readme_test.go
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"terraform-mcp-server/pkg/hashicorp/tfdocs"
+)
+
+func readmeTestConfig() *tfdocs.TerraformConfiguration {
+	return &tfdocs.TerraformConfiguration{
+		Files: map[string]string{
+			"versions.tf": `terraform {
+  required_version = ">= 1.5.0"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+  }
+}
+`,
+			"variables.tf": `variable "bucket_name" {
+  description = "Name of the bucket"
+  type        = string
+
+  validation {
+    condition     = length(var.bucket_name) <= 63
+    error_message = "The bucket name must be at most 63 characters."
+  }
+}
+
+variable "tags" {
+  description = "Tags | labels of the bucket"
+  type        = map(string)
+  default = {
+    Team = "platform"
+  }
+}
+
+variable "api_key" {
+  type      = string
+  default   = "changeme"
+  sensitive = true
+}
+`,
+			"main.tf": `resource "aws_s3_bucket" "this" {
+  bucket = var.bucket_name
+}
+
+data "aws_caller_identity" "current" {}
+
+resource "random_id" "suffix" {
+  byte_length = 4
+}
+
+module "logs" {
+  source  = "terraform-aws-modules/s3-bucket/aws"
+  version = "4.1.0"
+}
+`,
+			"outputs.tf": `output "bucket_arn" {
+  description = "ARN of the bucket"
+  value       = aws_s3_bucket.this.arn
+}
+
+output "api_key" {
+  value     = var.api_key
+  sensitive = true
+}
+`,
+			"modules/nested/main.tf": `variable "ignored" {}
+`,
+		},
+	}
+}
+
+func TestModuleDocs(t *testing.T) {
+	docs := readmeTestConfig().ModuleDocs()
+
+	if len(docs.Inputs) != 3 || docs.Inputs[0].Name != "api_key" {
+		t.Fatalf("Expected the 3 root inputs sorted by name, got %+v", docs.Inputs)
+	}
+	if docs.Inputs[1].Validations[0] != "The bucket name must be at most 63 characters." || !docs.Inputs[1].Required {
+		t.Errorf("Expected bucket_name to be required with a validation, got %+v", docs.Inputs[1])
+	}
+
+	markdown := docs.Markdown()
+	for _, want := range []string{
+		"| terraform | `>= 1.5.0` |\n| aws | `~> 5.0` |",
+		"| aws | hashicorp/aws | `~> 5.0` |\n| random | hashicorp/random | n/a |",
+		"| logs | terraform-aws-modules/s3-bucket/aws | `4.1.0` |",
+		"| aws_s3_bucket.this | resource |\n| data.aws_caller_identity.current | data source |",
+		"| api_key |  | `string` | sensitive | no |",
+		"| bucket_name | Name of the bucket<br>_Validation:_ The bucket name must be at most 63 characters. | `string` | n/a | yes |",
+		"| tags | Tags \\| labels of the bucket | `map(string)` | `{ Team = \"platform\" }` | no |",
+		"| api_key |  | yes |\n| bucket_arn | ARN of the bucket | no |",
+	} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("Expected the docs to contain %q, got:\n%s", want, markdown)
+		}
+	}
+	if strings.Contains(markdown, "changeme") || strings.Contains(markdown, "ignored") {
+		t.Errorf("Expected sensitive defaults and nested modules to be left out, got:\n%s", markdown)
+	}
+}
+
+func TestUpdateReadme(t *testing.T) {
+	config := readmeTestConfig()
+	docs := config.ModuleDocs()
+
+	readme := "# Buckets\n\nHand-written intro.\n\n" + tfdocs.DocsBeginMarker + "\n## Inputs\n\nStale.\n" + tfdocs.DocsEndMarker + "\n\n## License\n\nMIT\n"
+	updated := tfdocs.UpdateReadme(readme, docs)
+	if !strings.HasPrefix(updated, "# Buckets\n\nHand-written intro.\n\n"+tfdocs.DocsBeginMarker+"\n## Requirements\n") {
+		t.Errorf("Expected the intro to be kept, got:\n%s", updated)
+	}
+	if !strings.HasSuffix(updated, "No outputs.\n"+tfdocs.DocsEndMarker+"\n\n## License\n\nMIT\n") && !strings.HasSuffix(updated, "| no |\n"+tfdocs.DocsEndMarker+"\n\n## License\n\nMIT\n") {
+		t.Errorf("Expected the trailing sections to be kept, got:\n%s", updated)
+	}
+	if strings.Contains(updated, "Stale.") {
+		t.Errorf("Expected the stale section to be replaced")
+	}
+	if again := tfdocs.UpdateReadme(updated, docs); again != updated {
+		t.Errorf("Expected updating an up-to-date README to be a no-op")
+	}
+
+	// READMEs without markers get the section appended
+	appended := tfdocs.UpdateReadme("# Buckets", docs)
+	if !strings.HasPrefix(appended, "# Buckets\n\n"+tfdocs.DocsBeginMarker+"\n") {
+		t.Errorf("Expected the section to be appended, got:\n%s", appended)
+	}
+
+	generated := tfdocs.GenerateReadme(config, tfdocs.WithReadmeTitle("S3 Bucket"), tfdocs.WithModuleSource("bucket", "../.."))
+	if !strings.HasPrefix(generated, "# S3 Bucket\n\n## Usage\n\n```hcl\nmodule \"bucket\" {\n  source = \"../..\"\n\n  bucket_name = \"...\"\n}\n```\n\n"+tfdocs.DocsBeginMarker) {
+		t.Errorf("Unexpected generated README:\n%s", generated)
+	}
+
+	// SuggestImprovements refreshes the generated section of an existing README
+	config.Files["README.md"] = readme
+	improvements, err := tfdocs.NewValidationEngine(nil, &mockLogger{}).SuggestImprovements(config)
+	if err != nil {
+		t.Fatalf("Failed to suggest improvements: %v", err)
+	}
+	if improvements["README.md"] != updated {
+		t.Errorf("Expected the README to be refreshed, got:\n%s", improvements["README.md"])
+	}
+}
@@ -0,0 +1,103 @@
+// tests/rego_test.go
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"terraform-mcp-server/pkg/hashicorp/tfdocs"
+)
+
+const testRegoPolicy = `
+package terraform.ebs
+
+deny[msg] {
+	r := input.resources[_]
+	r.type == "aws_ebs_volume"
+	r.attributes.encrypted != true
+	msg := sprintf("%s must be encrypted", [r.address])
+}
+
+warn[{"msg": "EBS volume is larger than 100 GiB", "id": "ebs-size", "file": r.file, "line": r.line}] {
+	r := input.resources[_]
+	r.type == "aws_ebs_volume"
+	r.attributes.size > 100
+}
+`
+
+const testRegoConfig = `
+resource "aws_ebs_volume" "data" {
+  availability_zone = var.zone
+  size              = 200
+  encrypted         = false
+}
+`
+
+func TestConfigurationProjection(t *testing.T) {
+	config := &tfdocs.TerraformConfiguration{
+		Files: map[string]string{"main.tf": testRegoConfig},
+	}
+
+	projection := config.Projection()
+	if len(projection.Resources) != 1 {
+		t.Fatalf("Expected 1 resource, got %d", len(projection.Resources))
+	}
+
+	resource := projection.Resources[0]
+	if resource.Address != "aws_ebs_volume.data" {
+		t.Errorf("Expected address aws_ebs_volume.data, got %s", resource.Address)
+	}
+	if resource.Attributes["encrypted"] != false {
+		t.Errorf("Expected encrypted to be false, got %v", resource.Attributes["encrypted"])
+	}
+	if resource.Attributes["availability_zone"] != "var.zone" {
+		t.Errorf("Expected availability_zone to be its source text, got %v", resource.Attributes["availability_zone"])
+	}
+	if refs := resource.References["availability_zone"]; len(refs) != 1 || refs[0] != "var.zone" {
+		t.Errorf("Expected availability_zone to reference var.zone, got %v", refs)
+	}
+}
+
+func TestRegoValidator(t *testing.T) {
+	validator, err := tfdocs.NewRegoValidator(context.Background(), map[string]string{
+		"ebs.rego": testRegoPolicy,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create Rego validator: %v", err)
+	}
+
+	config := &tfdocs.TerraformConfiguration{
+		Files: map[string]string{"main.tf": testRegoConfig},
+	}
+
+	var deny, warn *tfdocs.ValidationIssue
+	issues := validator.Validate(config)
+	for i := range issues {
+		switch issues[i].RuleID {
+		case "terraform.ebs.deny":
+			deny = &issues[i]
+		case "ebs-size":
+			warn = &issues[i]
+		}
+	}
+
+	if deny == nil {
+		t.Fatalf("Expected a deny issue, got %v", issues)
+	}
+	if deny.Severity != tfdocs.SeverityError {
+		t.Errorf("Expected deny to be an error, got %s", deny.Severity)
+	}
+	if deny.Message != "aws_ebs_volume.data must be encrypted" {
+		t.Errorf("Unexpected deny message: %s", deny.Message)
+	}
+
+	if warn == nil {
+		t.Fatalf("Expected a warn issue, got %v", issues)
+	}
+	if warn.Severity != tfdocs.SeverityWarning {
+		t.Errorf("Expected warn to be a warning, got %s", warn.Severity)
+	}
+	if warn.File != "main.tf" || warn.Line != 2 {
+		t.Errorf("Expected warn location main.tf:2, got %s:%d", warn.File, warn.Line)
+	}
+}
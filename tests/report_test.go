@@ -0,0 +1,225 @@
+// tests/report_test.go
+package tests
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"terraform-mcp-server/pkg/hashicorp/tfdocs"
+)
+
+func reportFixture() (*tfdocs.TerraformConfiguration, *tfdocs.ValidationResult) {
+	config := &tfdocs.TerraformConfiguration{
+		Files: map[string]string{
+			"variables.tf": "variable \"db_password\" {\n  type = string\n}\n",
+		},
+	}
+	result := &tfdocs.ValidationResult{
+		FileCount: 1,
+		WarnCount: 1,
+		InfoCount: 1,
+		Issues: []tfdocs.ValidationIssue{
+			{
+				Message:  "Sensitive variable 'db_password' should be marked with sensitive = true",
+				RuleID:   "sensitive-variables",
+				Severity: tfdocs.SeverityWarning,
+				Category: tfdocs.CategorySecurity,
+				File:     "variables.tf",
+				Line:     1,
+			},
+			{
+				Message:  "Missing README.md file",
+				RuleID:   "readme",
+				Severity: tfdocs.SeverityInfo,
+				Category: tfdocs.CategoryDocumentation,
+			},
+		},
+	}
+	return config, result
+}
+
+func TestSARIFReport(t *testing.T) {
+	config, result := reportFixture()
+	rules := (&tfdocs.SecurityValidator{}).DescribeRules()
+
+	output, err := tfdocs.FormatReport(tfdocs.OutputFormatSARIF, result, rules, config)
+	if err != nil {
+		t.Fatalf("Failed to format SARIF report: %v", err)
+	}
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID              string            `json:"ruleId"`
+				RuleIndex           int               `json:"ruleIndex"`
+				Level               string            `json:"level"`
+				PartialFingerprints map[string]string `json:"partialFingerprints"`
+				Locations           []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal([]byte(output), &log); err != nil {
+		t.Fatalf("Failed to parse SARIF report: %v", err)
+	}
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("Unexpected SARIF log: %s", output)
+	}
+	run := log.Runs[0]
+	if len(run.Results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(run.Results))
+	}
+
+	first := run.Results[0]
+	if run.Tool.Driver.Rules[first.RuleIndex].ID != "sensitive-variables" {
+		t.Errorf("Expected rule index to point at sensitive-variables, got %d", first.RuleIndex)
+	}
+	if first.Level != "warning" {
+		t.Errorf("Expected level warning, got %s", first.Level)
+	}
+	location := first.Locations[0].PhysicalLocation
+	if location.ArtifactLocation.URI != "variables.tf" || location.Region.StartLine != 1 {
+		t.Errorf("Unexpected location: %+v", location)
+	}
+
+	// Rules that only appear in issues are added to the driver
+	second := run.Results[1]
+	if run.Tool.Driver.Rules[second.RuleIndex].ID != "readme" || second.Level != "note" {
+		t.Errorf("Unexpected result for readme: %+v", second)
+	}
+
+	// Fingerprints do not depend on the line number
+	moved := result.Issues[0]
+	config.Files["variables.tf"] = "# Inputs\n" + config.Files["variables.tf"]
+	moved.Line = 2
+	if tfdocs.IssueFingerprint(moved, config) != first.PartialFingerprints["issueHash/v1"] {
+		t.Errorf("Expected fingerprint to be stable when the issue moves")
+	}
+
+	// Fingerprints do not depend on the message, but on the symbol
+	reworded := moved
+	reworded.Message = "Sensitive variable 'db_password' is used 3 times without sensitive = true"
+	if tfdocs.IssueFingerprint(reworded, config) != first.PartialFingerprints["issueHash/v1"] {
+		t.Errorf("Expected fingerprint to be stable when the message changes")
+	}
+	config.Files["variables.tf"] += "\nvariable \"api_token\" {\n  type = string\n}\n"
+	other := moved
+	other.Line = 6
+	if tfdocs.IssueFingerprint(other, config) == tfdocs.IssueFingerprint(moved, config) {
+		t.Errorf("Expected issues of different variables to have different fingerprints")
+	}
+}
+
+func TestIssueFingerprints(t *testing.T) {
+	config, err := tfdocs.ParseTerraformConfiguration(map[string]string{
+		"main.tf": `resource "aws_security_group" "admin" {
+  name = "admin"
+
+  ingress {
+    from_port   = 22
+    to_port     = 22
+    protocol    = "tcp"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  ingress {
+    from_port   = 3389
+    to_port     = 3389
+    protocol    = "tcp"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+}
+`,
+		"variables.tf": "variable \"Instance-Name\" {\n  description = \"Name of the instance\"\n  type        = string\n}\n",
+	})
+	if err != nil {
+		t.Fatalf("Failed to parse configuration: %v", err)
+	}
+	result, err := tfdocs.NewValidationEngine(nil, &mockLogger{}).ValidateConfiguration(config)
+	if err != nil {
+		t.Fatalf("Failed to validate configuration: %v", err)
+	}
+
+	// Findings of one rule in one block or on one variable differ
+	issues := []tfdocs.ValidationIssue{}
+	for _, issue := range result.Issues {
+		if issue.RuleID == "open-ssh-rdp" || issue.RuleID == "variable-naming" {
+			issues = append(issues, issue)
+		}
+	}
+	// Issues without a file differ by their message
+	issues = append(issues,
+		tfdocs.ValidationIssue{RuleID: "module-structure", Message: "Missing examples directory"},
+		tfdocs.ValidationIssue{RuleID: "module-structure", Message: "Missing README.md file"},
+	)
+	if len(issues) != 6 {
+		t.Fatalf("Expected 2 open-ssh-rdp, 2 variable-naming and 2 structure issues, got %v", issues)
+	}
+
+	fingerprints := tfdocs.IssueFingerprints(issues, config)
+	seen := make(map[string]string)
+	for i, fingerprint := range fingerprints {
+		if other, ok := seen[fingerprint]; ok {
+			t.Errorf("Expected %q and %q to have different fingerprints", other, issues[i].Message)
+		}
+		seen[fingerprint] = issues[i].Message
+	}
+
+	// Counts in messages of issues outside of a symbol do not matter
+	counted := tfdocs.ValidationIssue{RuleID: "module-size", Message: "Module declares 12 resources"}
+	recounted := tfdocs.ValidationIssue{RuleID: "module-size", Message: "Module declares 13 resources"}
+	if tfdocs.IssueFingerprint(counted, config) != tfdocs.IssueFingerprint(recounted, config) {
+		t.Errorf("Expected fingerprints to ignore counts in messages")
+	}
+}
+
+func TestJUnitAndCheckstyleReports(t *testing.T) {
+	config, result := reportFixture()
+
+	output, err := tfdocs.FormatReport(tfdocs.OutputFormatJUnit, result, nil, config)
+	if err != nil {
+		t.Fatalf("Failed to format JUnit report: %v", err)
+	}
+	var suites struct {
+		Tests    int `xml:"tests,attr"`
+		Failures int `xml:"failures,attr"`
+	}
+	if err := xml.Unmarshal([]byte(output), &suites); err != nil {
+		t.Fatalf("Failed to parse JUnit report: %v", err)
+	}
+	if suites.Tests != 2 || suites.Failures != 2 {
+		t.Errorf("Expected 2 failed tests, got %d tests and %d failures", suites.Tests, suites.Failures)
+	}
+
+	output, err = tfdocs.FormatReport(tfdocs.OutputFormatCheckstyle, result, nil, config)
+	if err != nil {
+		t.Fatalf("Failed to format Checkstyle report: %v", err)
+	}
+	if !strings.Contains(output, `<file name="variables.tf">`) ||
+		!strings.Contains(output, `source="terraform-mcp-server.sensitive-variables"`) {
+		t.Errorf("Unexpected Checkstyle report:\n%s", output)
+	}
+
+	if _, err := tfdocs.ParseOutputFormat("yaml"); err == nil {
+		t.Errorf("Expected an error for an unsupported format")
+	}
+}
@@ -0,0 +1,98 @@
+// tests/risk_test.go
+package tests
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"terraform-mcp-server/pkg/hashicorp/tfdocs"
+)
+
+func TestResourceCategory(t *testing.T) {
+	expected := map[string]tfdocs.PatternCategory{
+		"aws_db_instance":                "database",
+		"google_sql_database_instance":   "database",
+		"aws_s3_bucket":                  "storage",
+		"azurerm_managed_disk":           "storage",
+		"aws_security_group":             "security",
+		"azurerm_network_security_group": "security",
+		"aws_iam_role":                   "security",
+		"aws_vpc":                        "networking",
+		"aws_lb_listener":                "networking",
+		"aws_instance":                   "compute",
+		"aws_lambda_function":            "compute",
+		"aws_cloudwatch_metric_alarm":    "monitoring",
+		"aws_sqs_queue":                  "application",
+	}
+	for resourceType, want := range expected {
+		if got := tfdocs.ResourceCategory(resourceType); got != want {
+			t.Errorf("%s: expected %s, got %s", resourceType, want, got)
+		}
+	}
+}
+
+func TestSummarizePlan(t *testing.T) {
+	plan, err := tfdocs.ParsePlan([]byte(testPlan))
+	if err != nil {
+		t.Fatalf("Failed to parse plan: %v", err)
+	}
+
+	summary := tfdocs.SummarizePlan(plan, 2)
+	if summary.Score != 71 || summary.Level != tfdocs.RiskCritical {
+		t.Errorf("Expected a critical risk of 71, got %s (%d)", summary.Level, summary.Score)
+	}
+	if summary.Actions[tfdocs.ActionReplace] != 2 || summary.Actions[tfdocs.ActionDelete] != 1 || summary.Actions[tfdocs.ActionUpdate] != 1 {
+		t.Errorf("Unexpected action counts: %v", summary.Actions)
+	}
+
+	// Destroying the bucket outweighs replacing the database
+	if len(summary.TopChanges) != 2 {
+		t.Fatalf("Expected 2 top changes, got %d", len(summary.TopChanges))
+	}
+	first, second := summary.TopChanges[0], summary.TopChanges[1]
+	if first.Address != "aws_s3_bucket.logs" || first.Score != 64 || !first.Stateful {
+		t.Errorf("Expected the bucket destroy to score 64, got %+v", first)
+	}
+	if second.Address != "aws_db_instance.main" || second.Score != 60 || second.Category != "database" {
+		t.Errorf("Expected the database replacement to score 60, got %+v", second)
+	}
+	if summary.Categories["networking"] != 0 || summary.Categories["security"] != 6 || summary.Categories["compute"] != 12 {
+		t.Errorf("Unexpected scores per category: %v", summary.Categories)
+	}
+
+	for _, want := range []string{
+		"## Plan risk: critical (71/100)",
+		"1 to update, 2 to replace, 1 to destroy.",
+		"| 60 | replace | `aws_db_instance.main` | database | stateful, data may be lost; forced by `engine` |",
+	} {
+		if !strings.Contains(summary.Markdown, want) {
+			t.Errorf("Expected the Markdown summary to contain %q, got:\n%s", want, summary.Markdown)
+		}
+	}
+
+	small, err := tfdocs.ParsePlan([]byte(`{"format_version": "1.2", "resource_changes": [
+  {"address": "aws_instance.web", "mode": "managed", "type": "aws_instance", "change": {"actions": ["create"]}},
+  {"address": "data.aws_ami.ubuntu", "mode": "data", "type": "aws_ami", "change": {"actions": ["read"]}},
+  {"address": "aws_sqs_queue.jobs", "mode": "managed", "type": "aws_sqs_queue", "change": {"actions": ["no-op"]}}
+]}`))
+	if err != nil {
+		t.Fatalf("Failed to parse plan: %v", err)
+	}
+	if summary := tfdocs.SummarizePlan(small, 5); summary.Score != 2 || summary.Level != tfdocs.RiskLow || len(summary.TopChanges) != 1 {
+		t.Errorf("Expected a single low risk create, got %+v", summary)
+	}
+
+	// Many low risk changes do not add up to a critical plan
+	var creates []string
+	for i := 0; i < 26; i++ {
+		creates = append(creates, fmt.Sprintf(`{"address": "aws_instance.web[%d]", "mode": "managed", "type": "aws_instance", "change": {"actions": ["create"]}}`, i))
+	}
+	large, err := tfdocs.ParsePlan([]byte(`{"format_version": "1.2", "resource_changes": [` + strings.Join(creates, ",") + `]}`))
+	if err != nil {
+		t.Fatalf("Failed to parse plan: %v", err)
+	}
+	if summary := tfdocs.SummarizePlan(large, 5); summary.Score != 7 || summary.Level != tfdocs.RiskLow {
+		t.Errorf("Expected 26 instance creates to be a low risk of 7, got %s (%d)", summary.Level, summary.Score)
+	}
+}
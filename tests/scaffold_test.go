@@ -0,0 +1,203 @@
+// tests/scaffold_test.go
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"terraform-mcp-server/pkg/hashicorp/tfdocs"
+)
+
+func TestScaffoldModule(t *testing.T) {
+	logger := &mockLogger{}
+
+	indexer := tfdocs.NewIndexer(t.TempDir(), logger)
+	if err := indexer.Initialize(context.Background()); err != nil {
+		t.Fatalf("Failed to initialize indexer: %v", err)
+	}
+	repo := tfdocs.NewPatternRepository(t.TempDir(), logger)
+	if err := repo.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize pattern repository: %v", err)
+	}
+	patterns, err := repo.FindPatterns(tfdocs.PatternFilter{})
+	if err != nil {
+		t.Fatalf("Failed to find patterns: %v", err)
+	}
+	engine := tfdocs.NewValidationEngine(nil, logger)
+
+	tests := []struct {
+		name         string
+		spec         tfdocs.ModuleSpec
+		resources    []string
+		placeholders []string
+	}{
+		{
+			name: "aws network",
+			spec: tfdocs.ModuleSpec{
+				Name:          "network",
+				Provider:      "aws",
+				ResourceTypes: []string{"aws_vpc", "aws_nat_gateway"},
+			},
+			resources: []string{"aws_vpc.main", "aws_nat_gateway.main", "aws_eip.nat", "aws_subnet.public", "aws_internet_gateway.main"},
+		},
+		{
+			name: "aws web server with placeholder",
+			spec: tfdocs.ModuleSpec{
+				Name:          "web-server",
+				Provider:      "aws",
+				ResourceTypes: []string{"aws_instance", "aws_s3_bucket"},
+			},
+			resources:    []string{"aws_instance.web", "aws_security_group.web", "aws_s3_bucket.this"},
+			placeholders: []string{"aws_s3_bucket"},
+		},
+		{
+			name: "azure network",
+			spec: tfdocs.ModuleSpec{
+				Name:          "vnet",
+				Provider:      "azure",
+				ResourceTypes: []string{"azurerm_virtual_network", "azurerm_subnet"},
+			},
+		},
+		{
+			name: "gcp network",
+			spec: tfdocs.ModuleSpec{
+				Name:          "vpc",
+				Provider:      "gcp",
+				ResourceTypes: []string{"google_compute_network", "google_compute_router_nat"},
+			},
+			resources: []string{"google_compute_network.vpc", "google_compute_router.router"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := tt.spec
+			spec.Examples, spec.Tests, spec.CIWorkflow, spec.PreCommit = true, true, true, true
+
+			structures, err := indexer.GetModuleStructures("", spec.Provider)
+			if err != nil {
+				t.Fatalf("Failed to get module structures: %v", err)
+			}
+			var structure *tfdocs.ModuleStructureDoc
+			if len(structures) > 0 {
+				structure = &structures[0]
+			}
+
+			result, err := tfdocs.ScaffoldModule(spec, structure, patterns)
+			if err != nil {
+				t.Fatalf("Failed to scaffold module: %v", err)
+			}
+
+			for _, name := range []string{"main.tf", "variables.tf", "outputs.tf", "versions.tf", "README.md",
+				"examples/basic/main.tf", "examples/basic/README.md", "examples/complete/main.tf", "examples/complete/README.md", ".github/workflows/terraform.yml", ".pre-commit-config.yaml"} {
+				if _, ok := result.Files[name]; !ok {
+					t.Errorf("Expected file %s to be generated", name)
+				}
+			}
+			testFile := "tests/" + strings.ReplaceAll(spec.Name, "-", "_") + ".tftest.hcl"
+			if _, ok := result.Files[testFile]; !ok {
+				t.Errorf("Expected file %s to be generated", testFile)
+			}
+			if versions := result.Files["versions.tf"]; !strings.Contains(versions, `required_version = ">= 1.11.0, < 2.0.0"`) {
+				t.Errorf("Expected generated tests to require Terraform 1.11, got:\n%s", versions)
+			}
+			for _, address := range tt.resources {
+				if !containsAddress(result.Resources, address) {
+					t.Errorf("Expected resource %s, got %v", address, result.Resources)
+				}
+			}
+			if strings.Join(result.Placeholders, ",") != strings.Join(tt.placeholders, ",") {
+				t.Errorf("Expected placeholders %v, got %v", tt.placeholders, result.Placeholders)
+			}
+
+			// The generated module must pass validation without errors
+			config, err := tfdocs.ParseTerraformConfiguration(result.Files)
+			if err != nil {
+				t.Fatalf("Failed to parse configuration: %v", err)
+			}
+			validation, err := engine.ValidateConfiguration(config)
+			if err != nil {
+				t.Fatalf("Failed to validate configuration: %v", err)
+			}
+			for _, issue := range validation.Issues {
+				if issue.Severity == tfdocs.SeverityError {
+					t.Errorf("Unexpected error %s in %s:%d: %s", issue.RuleID, issue.File, issue.Line, issue.Message)
+				}
+			}
+			if validation.ErrorCount != 0 {
+				for name, content := range result.Files {
+					t.Logf("%s:\n%s", name, content)
+				}
+			}
+		})
+	}
+
+	if _, err := tfdocs.ScaffoldModule(tfdocs.ModuleSpec{Name: "Invalid Name", ResourceTypes: []string{"aws_vpc"}}, nil, patterns); err == nil {
+		t.Errorf("Expected an error for an invalid module name")
+	}
+	if _, err := tfdocs.ScaffoldModule(tfdocs.ModuleSpec{Name: "empty"}, nil, patterns); err == nil {
+		t.Errorf("Expected an error without resource types")
+	}
+}
+
+func containsAddress(addresses []string, address string) bool {
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
+
+func TestScaffoldSecurityRuleTypes(t *testing.T) {
+	logger := &mockLogger{}
+	repo := tfdocs.NewPatternRepository(t.TempDir(), logger)
+	if err := repo.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize pattern repository: %v", err)
+	}
+	patterns, err := repo.FindPatterns(tfdocs.PatternFilter{})
+	if err != nil {
+		t.Fatalf("Failed to find patterns: %v", err)
+	}
+	engine := tfdocs.NewValidationEngine(nil, logger)
+
+	// Every resource type the security rule pack checks
+	resourceTypes := []string{
+		"aws_s3_bucket", "aws_s3_bucket_acl", "aws_s3_bucket_public_access_block", "aws_s3_bucket_versioning", "aws_s3_bucket_logging",
+		"aws_ebs_volume", "aws_ebs_encryption_by_default", "aws_instance",
+		"aws_db_instance", "aws_rds_cluster", "aws_rds_cluster_instance",
+		"aws_iam_policy", "aws_iam_role_policy", "aws_iam_user_policy", "aws_iam_group_policy", "aws_iam_role",
+		"aws_security_group", "aws_security_group_rule", "aws_vpc_security_group_ingress_rule", "aws_cloudtrail",
+		"azurerm_storage_account", "azurerm_storage_container", "azurerm_network_security_group", "azurerm_network_security_rule", "azurerm_postgresql_firewall_rule",
+		"google_sql_database_instance", "google_compute_firewall",
+	}
+	providers := map[string]string{"aws": "aws", "azurerm": "azure", "google": "gcp"}
+
+	for _, resourceType := range resourceTypes {
+		t.Run(resourceType, func(t *testing.T) {
+			spec := tfdocs.ModuleSpec{
+				Name:          "secure",
+				Provider:      providers[resourceType[:strings.Index(resourceType, "_")]],
+				ResourceTypes: []string{resourceType},
+			}
+			result, err := tfdocs.ScaffoldModule(spec, nil, patterns)
+			if err != nil {
+				t.Fatalf("Failed to scaffold module: %v", err)
+			}
+			config, err := tfdocs.ParseTerraformConfiguration(result.Files)
+			if err != nil {
+				t.Fatalf("Failed to parse configuration: %v", err)
+			}
+			validation, err := engine.ValidateConfiguration(config)
+			if err != nil {
+				t.Fatalf("Failed to validate configuration: %v", err)
+			}
+			for _, issue := range validation.Issues {
+				if issue.Severity == tfdocs.SeverityError {
+					t.Errorf("Unexpected error %s in %s:%d: %s", issue.RuleID, issue.File, issue.Line, issue.Message)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+// tests/schemas_test.go
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"terraform-mcp-server/pkg/hashicorp/tfdocs"
+)
+
+const testProviderSchemas = `{
+  "format_version": "1.0",
+  "provider_schemas": {
+    "registry.terraform.io/hashicorp/aws": {
+      "resource_schemas": {
+        "aws_vpc": {
+          "version": 1,
+          "block": {
+            "attributes": {
+              "id": {"type": "string", "computed": true},
+              "cidr_block": {"type": "string", "required": true},
+              "enable_classiclink": {"type": "bool", "optional": true, "deprecated": true},
+              "tags": {"type": ["map", "string"], "optional": true}
+            }
+          }
+        },
+        "aws_s3_bucket_policy": {
+          "version": 0,
+          "block": {
+            "attributes": {
+              "bucket": {"type": "string", "required": true},
+              "policy": {"type": "string", "required": true}
+            }
+          }
+        },
+        "aws_security_group": {
+          "version": 1,
+          "block": {
+            "attributes": {
+              "name": {"type": "string", "optional": true},
+              "tags": {"type": ["map", "string"], "optional": true}
+            },
+            "block_types": {
+              "ingress": {
+                "nesting_mode": "set",
+                "block": {
+                  "attributes": {
+                    "from_port": {"type": "number", "required": true},
+                    "to_port": {"type": "number", "required": true}
+                  }
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+func TestSchemaValidation(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{"aws.json": testProviderSchemas})
+
+	engine := tfdocs.NewValidationEngine(nil, &mockLogger{}, tfdocs.WithSchemaPath(dir))
+	if err := engine.Initialize(context.Background()); err != nil {
+		t.Fatalf("Failed to initialize validation engine: %v", err)
+	}
+
+	config := &tfdocs.TerraformConfiguration{
+		Files: map[string]string{
+			"main.tf": `resource "aws_vpc" "main" {
+  cidr_blok          = "10.0.0.0/16"
+  enable_classiclink = false
+  id                 = "vpc-123"
+}
+
+resource "aws_s3_bucket_policy" "logs" {
+  bucket = "logs"
+  policy = "{}"
+}
+
+resource "aws_security_group" "web" {
+  name = "web"
+  tags = {}
+
+  dynamic "ingress" {
+    for_each = [80, 443]
+    content {
+      from_port = ingress.value
+      port      = ingress.value
+    }
+  }
+}
+`,
+		},
+	}
+
+	result, err := engine.ValidateConfiguration(config)
+	if err != nil {
+		t.Fatalf("Failed to validate configuration: %v", err)
+	}
+
+	lines := make(map[string][]int)
+	for _, issue := range result.Issues {
+		lines[issue.RuleID] = append(lines[issue.RuleID], issue.Line)
+		if issue.RuleID == "unknown-attributes" && issue.Line == 2 && issue.Suggestion != "Did you mean 'cidr_block'?" {
+			t.Errorf("Expected cidr_block to be suggested, got %q", issue.Suggestion)
+		}
+	}
+
+	// cidr_blok, the read-only id and port inside the dynamic block
+	if got := lines["unknown-attributes"]; len(got) != 3 || got[0] != 2 || got[1] != 4 || got[2] != 20 {
+		t.Errorf("Expected unknown attributes at lines 2, 4 and 20, got %v", got)
+	}
+	// cidr_block on aws_vpc and to_port inside the dynamic block
+	if got := lines["missing-required-attributes"]; len(got) != 2 || got[0] != 1 || got[1] != 18 {
+		t.Errorf("Expected missing required arguments at lines 1 and 18, got %v", got)
+	}
+	if got := lines["deprecated-attributes"]; len(got) != 1 || got[0] != 3 {
+		t.Errorf("Expected enable_classiclink to be deprecated at line 3, got %v", got)
+	}
+	// The schema of aws_s3_bucket_policy has no tags attribute
+	if got := lines["resource-tags"]; len(got) != 1 || got[0] != 1 {
+		t.Errorf("Expected only aws_vpc to be missing tags, got %v", got)
+	}
+}
+
+func TestSchemaValidationWithoutSchemas(t *testing.T) {
+	config := &tfdocs.TerraformConfiguration{
+		Files: map[string]string{
+			"main.tf": "resource \"aws_vpc\" \"main\" {\n  cidr_blok = \"10.0.0.0/16\"\n}\n",
+		},
+	}
+
+	engine := tfdocs.NewValidationEngine(nil, &mockLogger{}, tfdocs.WithSchemaPath(t.TempDir()))
+	if err := engine.Initialize(context.Background()); err != nil {
+		t.Fatalf("Failed to initialize validation engine: %v", err)
+	}
+	result, err := engine.ValidateConfiguration(config)
+	if err != nil {
+		t.Fatalf("Failed to validate configuration: %v", err)
+	}
+	for _, issue := range result.Issues {
+		if issue.RuleID == "unknown-attributes" || issue.RuleID == "missing-required-attributes" {
+			t.Errorf("Expected no schema issues without schemas, got %+v", issue)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+// tests/security_rules_test.go
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"terraform-mcp-server/pkg/hashicorp/tfdocs"
+)
+
+func TestSecurityRulePack(t *testing.T) {
+	config := &tfdocs.TerraformConfiguration{
+		Files: map[string]string{
+			"storage.tf": `resource "aws_s3_bucket" "data" {
+  bucket = "data"
+  acl    = "public-read"
+}
+
+resource "aws_s3_bucket_public_access_block" "data" {
+  bucket              = aws_s3_bucket.data.id
+  block_public_acls   = true
+  block_public_policy = false
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+
+resource "aws_s3_bucket_versioning" "logs" {
+  bucket = aws_s3_bucket.logs.id
+  versioning_configuration {
+    status = "Enabled"
+  }
+}
+
+resource "aws_s3_bucket_logging" "data" {
+  bucket        = aws_s3_bucket.data.id
+  target_bucket = aws_s3_bucket.logs.id
+}
+
+resource "azurerm_storage_account" "main" {
+  name                            = "main"
+  https_traffic_only_enabled      = false
+  min_tls_version                 = "TLS1_2"
+  allow_nested_items_to_be_public = true
+}
+`,
+			"compute.tf": `resource "aws_ebs_volume" "data" {
+  size = 100
+}
+
+resource "aws_instance" "web" {
+  ami = "ami-123"
+
+  root_block_device {
+    encrypted = true
+  }
+}
+
+resource "aws_db_instance" "main" {
+  engine              = "postgres"
+  storage_encrypted   = true
+  publicly_accessible = true
+}
+
+resource "aws_security_group" "web" {
+  name = "web"
+
+  ingress {
+    from_port   = 443
+    to_port     = 443
+    protocol    = "tcp"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  ingress {
+    from_port        = 0
+    to_port          = 0
+    protocol         = "-1"
+    ipv6_cidr_blocks = ["::/0"]
+  }
+}
+
+resource "google_compute_firewall" "rdp" {
+  name          = "rdp"
+  source_ranges = ["0.0.0.0/0"]
+
+  allow {
+    protocol = "tcp"
+    ports    = ["3000-4000"]
+  }
+}
+
+resource "azurerm_network_security_rule" "ssh" {
+  direction              = "Inbound"
+  access                 = "Allow"
+  source_address_prefix  = var.admin_cidr
+  destination_port_range = "22"
+}
+`,
+			"iam.tf": `resource "aws_iam_policy" "admin" {
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [{
+      Effect   = "Allow"
+      Action   = "*"
+      Resource = "*"
+    }]
+  })
+}
+
+resource "aws_iam_role_policy" "s3" {
+  role   = "app"
+  policy = <<EOF
+{"Statement": {"Effect": "Allow", "Action": ["s3:*"], "Resource": "*"}}
+EOF
+}
+
+data "aws_iam_policy_document" "read" {
+  statement {
+    actions   = ["s3:GetObject"]
+    resources = ["*"]
+  }
+
+  statement {
+    effect    = "Deny"
+    actions   = ["*"]
+    resources = ["*"]
+  }
+}
+`,
+		},
+	}
+
+	issues := (&tfdocs.SecurityValidator{}).Validate(config)
+
+	locations := make(map[string][]string)
+	for _, issue := range issues {
+		if issue.Line == 0 {
+			continue
+		}
+		locations[issue.RuleID] = append(locations[issue.RuleID], fmt.Sprintf("%s:%d", issue.File, issue.Line))
+		if len(issue.References) == 0 {
+			t.Errorf("Expected %s to carry references", issue.RuleID)
+		}
+	}
+
+	expected := map[string][]string{
+		// The public ACL and the incomplete public access block
+		"s3-public-access": {"storage.tf:3", "storage.tf:9"},
+		"s3-versioning":    {"storage.tf:1"},
+		// The logs bucket is the access log target of the data bucket
+		"s3-access-logging":               nil,
+		"storage-account-secure-transfer": {"storage.tf:30"},
+		"storage-account-public-access":   {"storage.tf:32"},
+		"ebs-encryption":                  {"compute.tf:1"},
+		"rds-encryption":                  nil,
+		"database-public-access":          {"compute.tf:16"},
+		// All ports over IPv6 and RDP on GCP; the Azure source is unknown
+		"open-ssh-rdp":           {"compute.tf:29", "compute.tf:41"},
+		"open-ingress":           {"compute.tf:22"},
+		"iam-wildcard-actions":   {"iam.tf:2"},
+		"iam-wildcard-resources": {"iam.tf:14"},
+	}
+	for rule, want := range expected {
+		got := locations[rule]
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("%s: expected %v, got %v", rule, want, got)
+		}
+	}
+}
+
+func TestSecurityRuleReferencesInSARIF(t *testing.T) {
+	rules := (&tfdocs.SecurityValidator{}).DescribeRules()
+
+	var ebs *tfdocs.RuleMetadata
+	for i := range rules {
+		if rules[i].ID == "ebs-encryption" {
+			ebs = &rules[i]
+		}
+	}
+	if ebs == nil || ebs.HelpURI != "https://cwe.mitre.org/data/definitions/311.html" {
+		t.Fatalf("Expected ebs-encryption to link CWE-311, got %+v", ebs)
+	}
+
+	output, err := tfdocs.FormatReport(tfdocs.OutputFormatSARIF, &tfdocs.ValidationResult{}, []tfdocs.RuleMetadata{*ebs}, &tfdocs.TerraformConfiguration{})
+	if err != nil {
+		t.Fatalf("Failed to format SARIF report: %v", err)
+	}
+
+	var log struct {
+		Runs []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						Properties struct {
+							Tags       []string `json:"tags"`
+							References []string `json:"references"`
+						} `json:"properties"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal([]byte(output), &log); err != nil {
+		t.Fatalf("Failed to parse SARIF report: %v", err)
+	}
+	properties := log.Runs[0].Tool.Driver.Rules[0].Properties
+	if !strings.Contains(strings.Join(properties.Tags, ","), "external/cwe/cwe-311") {
+		t.Errorf("Expected a CWE tag, got %v", properties.Tags)
+	}
+	if len(properties.References) != 2 || properties.References[1] != "CIS AWS Foundations 2.2.1" {
+		t.Errorf("Expected the benchmark reference, got %v", properties.References)
+	}
+}
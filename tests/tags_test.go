@@ -0,0 +1,148 @@
+// tests/tags_test.go
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"terraform-mcp-server/pkg/hashicorp/tfdocs"
+)
+
+const testTagPolicy = `
+tag_policy:
+  required: [Environment, Owner, CostCenter]
+  key_case: pascal
+  tags:
+    Environment:
+      values: [dev, staging, prod]
+    CostCenter:
+      pattern: '^cc-[0-9]{4}$'
+`
+
+func TestTagPolicy(t *testing.T) {
+	policy, err := tfdocs.ParseTagPolicy([]byte(testTagPolicy))
+	if err != nil {
+		t.Fatalf("Failed to parse tag policy: %v", err)
+	}
+
+	config := &tfdocs.TerraformConfiguration{
+		Files: map[string]string{
+			"providers.tf": `provider "aws" {
+  default_tags {
+    tags = {
+      Owner = "platform"
+    }
+  }
+}
+
+provider "aws" {
+  alias = "west"
+}
+
+provider "google" {
+  default_labels = {
+    owner = "platform"
+  }
+}
+`,
+			"main.tf": `locals {
+  common_tags = {
+    Environment = "prod"
+    CostCenter  = "cc-1234"
+  }
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+  tags = merge(local.common_tags, {
+    Name    = "logs"
+    project = "web"
+  })
+}
+
+resource "aws_s3_bucket" "replica" {
+  provider = aws.west
+  bucket   = "replica"
+  tags     = local.common_tags
+}
+
+resource "aws_instance" "web" {
+  ami  = "ami-123"
+  tags = merge(var.tags, { Environment = "qa" })
+}
+
+resource "google_storage_bucket" "assets" {
+  name = "assets"
+  labels = {
+    environment = "prod"
+    CostCenter  = "cc-1234"
+  }
+}
+`,
+		},
+	}
+
+	issues := tfdocs.NewTagValidator(policy).Validate(config)
+
+	byRule := make(map[string][]tfdocs.ValidationIssue)
+	for _, issue := range issues {
+		byRule[issue.RuleID] = append(byRule[issue.RuleID], issue)
+	}
+
+	// The replica uses a provider without default tags, so Owner is missing.
+	// The tags of aws_instance.web depend on a variable and cannot be checked.
+	if got := byRule["required-tags"]; len(got) != 1 || got[0].Message != "Resource 'aws_s3_bucket.replica' is missing required tags: Owner" {
+		t.Errorf("Expected only the replica to miss Owner, got %v", got)
+	}
+	if got := byRule["tag-values"]; len(got) != 1 || got[0].File != "main.tf" || got[0].Line != 24 {
+		t.Errorf("Expected Environment = qa to be rejected at main.tf:24, got %v", got)
+	}
+	// project is not PascalCase; GCP labels are not checked for casing
+	if got := byRule["tag-key-case"]; len(got) != 1 || got[0].Line != 12 {
+		t.Errorf("Expected project to violate the key case at main.tf:12, got %v", got)
+	}
+	if got := byRule["gcp-label-format"]; len(got) != 1 || got[0].Line != 31 {
+		t.Errorf("Expected the CostCenter label to be rejected at main.tf:31, got %v", got)
+	}
+	if got := byRule["resource-tags"]; len(got) != 0 {
+		t.Errorf("Expected no resource-tags issues with a tagging policy, got %v", got)
+	}
+}
+
+func TestTagPolicyFromPolicyDirectory(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{"tags.yaml": testTagPolicy})
+
+	engine := tfdocs.NewValidationEngine(nil, &mockLogger{}, tfdocs.WithPolicyPath(dir))
+	if err := engine.Initialize(context.Background()); err != nil {
+		t.Fatalf("Failed to initialize validation engine: %v", err)
+	}
+
+	config := &tfdocs.TerraformConfiguration{
+		Files: map[string]string{
+			"main.tf": `resource "azurerm_resource_group" "main" {
+  name     = "main"
+  location = "westeurope"
+}
+`,
+		},
+	}
+	result, err := engine.ValidateConfiguration(config)
+	if err != nil {
+		t.Fatalf("Failed to validate configuration: %v", err)
+	}
+
+	found := false
+	for _, issue := range result.Issues {
+		if issue.RuleID == "required-tags" && issue.Line == 1 {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected the untagged resource group to be reported")
+	}
+
+	if _, err := tfdocs.ParseTagPolicy([]byte("tag_policy:\n  key_case: shouting\n")); err == nil {
+		t.Errorf("Expected an unknown key_case to be rejected")
+	}
+}
@@ -0,0 +1,317 @@
+// tests/tftest_test.go
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"terraform-mcp-server/pkg/hashicorp/tfdocs"
+)
+
+func tftestConfig() map[string]string {
+	return map[string]string{
+		"variables.tf": `variable "name" {
+  description = "Name of the network"
+  type        = string
+
+  validation {
+    condition     = length(var.name) <= 32
+    error_message = "The name must be at most 32 characters."
+  }
+
+  validation {
+    condition     = can(regex("^[a-z][a-z0-9-]*$", var.name))
+    error_message = "The name must start with a letter."
+  }
+}
+
+variable "vpc_cidr" {
+  description = "CIDR block of the VPC"
+  type        = string
+
+  validation {
+    condition     = can(cidrhost(var.vpc_cidr, 0))
+    error_message = "The VPC CIDR must be a valid CIDR block."
+  }
+}
+
+variable "environment" {
+  description = "Environment of the network"
+  type        = string
+  default     = "dev"
+
+  validation {
+    condition     = contains(["dev", "prod"], var.environment)
+    error_message = "The environment must be dev or prod."
+  }
+}
+
+variable "flow_log_retention" {
+  description = "Days to keep flow logs"
+  type        = number
+
+  validation {
+    condition     = var.flow_log_retention >= 90
+    error_message = "Flow logs must be kept for at least 90 days."
+  }
+}
+
+variable "subnets" {
+  description = "Subnets of the network"
+  type = map(object({
+    cidr = string
+    zone = string
+  }))
+}
+
+variable "tags" {
+  description = "Tags of the network"
+  type        = map(string)
+  default     = {}
+
+  validation {
+    condition     = alltrue([for key in keys(var.tags) : key != ""])
+    error_message = "Tag keys must not be empty."
+  }
+}
+`,
+		"main.tf": `resource "aws_vpc" "this" {
+  cidr_block = var.vpc_cidr
+  tags       = merge(var.tags, { Name = var.name })
+}
+
+resource "aws_flow_log" "this" {
+  count  = var.environment == "prod" ? 1 : 0
+  vpc_id = aws_vpc.this.id
+}
+`,
+		"outputs.tf": `output "vpc_id" {
+  description = "The ID of the VPC"
+  value       = aws_vpc.this.id
+}
+
+output "flow_log_id" {
+  description = "The ID of the flow log"
+  value       = var.environment == "prod" ? aws_flow_log.this[0].id : null
+}
+`,
+	}
+}
+
+func TestGenerateTests(t *testing.T) {
+	config, err := tfdocs.ParseTerraformConfiguration(tftestConfig())
+	if err != nil {
+		t.Fatalf("Failed to parse configuration: %v", err)
+	}
+
+	tests := tfdocs.GenerateTests(config, "network")
+
+	plan, ok := tests.Files["tests/network.tftest.hcl"]
+	if !ok {
+		t.Fatalf("Expected tests/network.tftest.hcl, got %v", tests.Files)
+	}
+	for _, want := range []string{
+		"mock_provider \"aws\" {\n  override_during = plan\n}",
+		`name               = "example"`,
+		`vpc_cidr           = "10.0.0.0/16"`,
+		`flow_log_retention = 90`,
+		`subnets            = { example = { cidr = "10.0.0.0/16", zone = "us-east-1a" } }`,
+		"run \"plan\" {\n  command = plan\n",
+		"condition     = output.vpc_id != null",
+	} {
+		if !strings.Contains(plan, want) {
+			t.Errorf("Expected plan test to contain %q, got:\n%s", want, plan)
+		}
+	}
+	if strings.Contains(plan, "output.flow_log_id") {
+		t.Errorf("Expected no assertion on an output that may be null, got:\n%s", plan)
+	}
+	if strings.Contains(plan, "environment =") {
+		t.Errorf("Expected variables with defaults not to be set, got:\n%s", plan)
+	}
+
+	validation, ok := tests.Files["tests/validation.tftest.hcl"]
+	if !ok {
+		t.Fatalf("Expected tests/validation.tftest.hcl, got %v", tests.Files)
+	}
+	for _, want := range []string{
+		"run \"invalid_name\" {\n  command = plan\n\n  variables {\n    name = \"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa\"\n  }\n\n  expect_failures = [\n    var.name,\n  ]\n}",
+		`name = ""`,
+		`vpc_cidr = "not-a-cidr"`,
+		`environment = "invalid"`,
+		`flow_log_retention = 89`,
+	} {
+		if !strings.Contains(validation, want) {
+			t.Errorf("Expected validation test to contain %q, got:\n%s", want, validation)
+		}
+	}
+
+	wantRuns := "plan,invalid_name,invalid_name_2,invalid_vpc_cidr,invalid_environment,invalid_flow_log_retention"
+	if got := strings.Join(tests.Runs, ","); got != wantRuns {
+		t.Errorf("Expected runs %s, got %s", wantRuns, got)
+	}
+	if strings.Join(tests.Untested, ",") != "tags" {
+		t.Errorf("Expected the tags validation to be untested, got %v", tests.Untested)
+	}
+
+	// The generated files are formatted
+	for name, content := range tests.Files {
+		if formatted, err := tfdocs.FormatHCL(name, content); err != nil || formatted != content {
+			t.Errorf("Expected %s to be formatted, got:\n%s", name, content)
+		}
+	}
+}
+
+func TestModuleTestsRule(t *testing.T) {
+	engine := tfdocs.NewValidationEngine(nil, &mockLogger{})
+
+	// Modules without tests get a fix generating them
+	config, err := tfdocs.ParseTerraformConfiguration(tftestConfig())
+	if err != nil {
+		t.Fatalf("Failed to parse configuration: %v", err)
+	}
+	result, err := engine.ApplyFixes(config, []string{"module-tests"})
+	if err != nil {
+		t.Fatalf("Failed to apply fixes: %v", err)
+	}
+	for _, name := range []string{"tests/main.tftest.hcl", "tests/validation.tftest.hcl"} {
+		if _, ok := result.Files[name]; !ok {
+			t.Errorf("Expected the fix to create %s, got %v", name, result.Changed)
+		}
+	}
+
+	// Test files in the tests directory of a module tree belong to the root,
+	// and examples only calling modules need no tests
+	files := tftestConfig()
+	files["tests/network.tftest.hcl"] = "run \"plan\" {\n  command = plan\n}\n"
+	files["modules/subnet/main.tf"] = "variable \"cidr\" {\n  description = \"CIDR block\"\n  type        = string\n}\n\nresource \"aws_subnet\" \"this\" {\n  cidr_block = var.cidr\n}\n"
+	files["examples/basic/main.tf"] = "module \"network\" {\n  source = \"../..\"\n}\n\noutput \"vpc_id\" {\n  description = \"The ID of the VPC\"\n  value       = module.network.vpc_id\n}\n"
+	config, err = tfdocs.ParseTerraformConfiguration(files)
+	if err != nil {
+		t.Fatalf("Failed to parse configuration: %v", err)
+	}
+	validation, err := engine.ValidateConfiguration(config)
+	if err != nil {
+		t.Fatalf("Failed to validate configuration: %v", err)
+	}
+	var flagged []string
+	for _, issue := range validation.Issues {
+		if issue.RuleID == "module-tests" {
+			flagged = append(flagged, issue.File)
+		}
+	}
+	if strings.Join(flagged, ",") != "modules/subnet/main.tf" {
+		t.Errorf("Expected only modules/subnet to lack tests, got %v", flagged)
+	}
+}
+
+func TestModuleTestsRuleVersion(t *testing.T) {
+	engine := tfdocs.NewValidationEngine(nil, &mockLogger{})
+	providers := "\n  required_providers {\n    aws = {\n      source  = \"hashicorp/aws\"\n      version = \"~> 5.0\"\n    }\n  }\n"
+
+	tests := []struct {
+		name     string
+		versions string
+		// want is the required_version after the fix, empty if versions.tf is unchanged
+		want     string
+		override bool
+	}{
+		{"lower bound below 1.11", "terraform {\n  required_version = \">= 1.5.0, < 2.0.0\"\n" + providers + "}\n", `required_version = ">= 1.11.0, < 2.0.0"`, true},
+		{"pessimistic constraint", "terraform {\n  required_version = \"~> 1.6\"\n}\n", `required_version = ">= 1.11.0, < 2.0.0"`, true},
+		{"already 1.11", "terraform {\n  required_version = \">= 1.12.0\"\n}\n", "", true},
+		{"upper bound below 1.11", "terraform {\n  required_version = \"~> 1.9.0\"\n}\n", "", false},
+		{"no required_version", "terraform {" + providers + "}\n", "terraform {\n  required_version = \">= 1.11.0\"\n", true},
+		{"no terraform block", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := tftestConfig()
+			if tt.versions != "" {
+				files["versions.tf"] = tt.versions
+			}
+			config, err := tfdocs.ParseTerraformConfiguration(files)
+			if err != nil {
+				t.Fatalf("Failed to parse configuration: %v", err)
+			}
+			result, err := engine.ApplyFixes(config, []string{"module-tests"})
+			if err != nil {
+				t.Fatalf("Failed to apply fixes: %v", err)
+			}
+
+			if versions := result.Files["versions.tf"]; tt.want == "" && versions != tt.versions {
+				t.Errorf("Expected versions.tf to be unchanged, got:\n%s", versions)
+			} else if !strings.Contains(versions, tt.want) {
+				t.Errorf("Expected versions.tf to contain %q, got:\n%s", tt.want, versions)
+			}
+
+			plan := result.Files["tests/main.tftest.hcl"]
+			if got := strings.Contains(plan, "override_during = plan"); got != tt.override {
+				t.Errorf("Expected override_during %v, got:\n%s", tt.override, plan)
+			}
+			if !tt.override && !strings.Contains(plan, "run \"apply\" {\n  command = apply\n") {
+				t.Errorf("Expected the outputs to be checked by applying the mocks, got:\n%s", plan)
+			}
+		})
+	}
+}
+
+func TestGenerateTestsRootModule(t *testing.T) {
+	files := tftestConfig()
+	files["main.tf"] += "\nmodule \"child\" {\n  source = \"./modules/child\"\n\n  child_input = var.vpc_cidr\n}\n"
+	files["modules/child/main.tf"] = `variable "child_input" {
+  description = "Input of the child module"
+  type        = string
+
+  validation {
+    condition     = length(var.child_input) > 0
+    error_message = "The input must not be empty."
+  }
+}
+
+resource "google_storage_bucket" "this" {
+  name = var.child_input
+}
+
+output "child_output" {
+  description = "Output of the child module"
+  value       = google_storage_bucket.this.id
+}
+`
+	config, err := tfdocs.ParseTerraformConfiguration(files)
+	if err != nil {
+		t.Fatalf("Failed to parse configuration: %v", err)
+	}
+
+	tests := tfdocs.GenerateTests(config, "network")
+	for name, content := range tests.Files {
+		if strings.Contains(content, "child_input") || strings.Contains(content, "child_output") {
+			t.Errorf("Expected %s not to use the variables or outputs of the child module, got:\n%s", name, content)
+		}
+	}
+	// Providers of child modules are mocked too
+	if plan := tests.Files["tests/network.tftest.hcl"]; !strings.Contains(plan, "mock_provider \"google\"") {
+		t.Errorf("Expected the provider of the child module to be mocked, got:\n%s", plan)
+	}
+}
+
+func TestDiscoverModulesTests(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"main.tf":                  "variable \"name\" {\n  type = string\n}\n",
+		"tests/main.tftest.hcl":    "run \"plan\" {\n  command = plan\n}\n",
+		"modules/vpc/main.tf":      "variable \"cidr\" {\n  type = string\n}\n",
+		"modules/vpc/tests/vpc.md": "# Notes\n",
+	})
+
+	modules, err := tfdocs.DiscoverModules(dir)
+	if err != nil {
+		t.Fatalf("Failed to discover modules: %v", err)
+	}
+	if len(modules) != 2 {
+		t.Fatalf("Expected 2 modules, got %d", len(modules))
+	}
+	if _, ok := modules[0].Config.Files["tests/main.tftest.hcl"]; !ok {
+		t.Errorf("Expected tests/main.tftest.hcl to be loaded with the root module, got %v", modules[0].Config.Files)
+	}
+}
@@ -0,0 +1,145 @@
+// tests/variables_test.go
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"terraform-mcp-server/pkg/hashicorp/tfdocs"
+)
+
+func TestVariableQuality(t *testing.T) {
+	files := map[string]string{
+		"variables.tf": `variable "environment" {
+  description = "Deployment environment"
+  type        = string
+}
+
+variable "instance_count" {
+  description = "Number of instances"
+  default     = 2
+}
+
+variable "buckets" {
+  description = "Buckets to create"
+  type        = map(any)
+}
+
+variable "settings" {
+  description = "Free-form settings"
+  type        = any
+}
+
+variable "vpc_cidr" {
+  description = "CIDR block of the VPC"
+  type        = string
+  default     = "10.0.0.0/16"
+  nullable    = false
+}
+
+variable "kms_key_arn" {
+  description = "ARN of the KMS key"
+  type        = string
+  default     = null
+}
+
+variable "allowed_cidrs" {
+  description = "CIDR blocks allowed to connect"
+  type        = list(string)
+  default     = []
+  nullable    = false
+
+  validation {
+    condition     = length(var.allowed_cidrs) < 10
+    error_message = "At most 10 CIDR blocks are allowed."
+  }
+}
+`,
+		"main.tf": `locals {
+  is_production = contains(["dev", "staging", "prod"], var.environment) && var.environment == "prod"
+}
+
+resource "aws_instance" "web" {
+  count = var.instance_count
+}
+
+resource "aws_s3_bucket" "this" {
+  for_each      = var.buckets
+  bucket        = each.value.name
+  force_destroy = each.value.force_destroy
+}
+
+resource "aws_vpc" "main" {
+  cidr_block = var.vpc_cidr
+}
+
+resource "aws_kms_alias" "this" {
+  target_key_id = var.kms_key_arn
+}
+
+resource "aws_security_group_rule" "ingress" {
+  cidr_blocks = var.allowed_cidrs
+}
+`,
+	}
+	config := &tfdocs.TerraformConfiguration{Files: files}
+
+	issues := (&tfdocs.DocumentationValidator{}).Validate(config)
+	messages := make(map[string][]string)
+	for _, issue := range issues {
+		if strings.HasPrefix(issue.RuleID, "variable-") {
+			messages[issue.RuleID] = append(messages[issue.RuleID], issue.Message)
+			if len(issue.References) != 1 || issue.References[0] != "variables-documentation" {
+				t.Errorf("Expected %s to refer to the variables best practice, got %v", issue.RuleID, issue.References)
+			}
+		}
+	}
+
+	expected := map[string][]string{
+		"variable-type": {"Variable 'instance_count' has no type"},
+		"variable-any-type": {
+			"Variable 'buckets' has type map(any), but its usage implies map(object({ force_destroy = bool, name = string }))",
+			"Variable 'settings' has type any, which accepts values of any type",
+		},
+		// Variables with a null default or an explicit nullable are not reported
+		"variable-nullable": {"Variable 'instance_count' has a default but accepts null, which replaces the default when passed"},
+		// Variables with a validation block are not reported
+		"variable-validation": {
+			"Variable 'environment' has no validation",
+			"Variable 'vpc_cidr' has no validation",
+			"Variable 'kms_key_arn' has no validation",
+		},
+	}
+	for rule, want := range expected {
+		if got := messages[rule]; strings.Join(got, "\n") != strings.Join(want, "\n") {
+			t.Errorf("%s: expected\n%s\ngot\n%s", rule, strings.Join(want, "\n"), strings.Join(got, "\n"))
+		}
+	}
+
+	var fixable []tfdocs.ValidationIssue
+	for _, issue := range issues {
+		if strings.HasPrefix(issue.RuleID, "variable-") && issue.Fix != nil {
+			fixable = append(fixable, issue)
+		}
+	}
+	result := tfdocs.ApplyIssueFixes(files, fixable)
+	if len(result.Skipped) != 0 {
+		t.Errorf("Expected all fixes to apply, skipped: %v", result.Skipped)
+	}
+
+	variables := result.Files["variables.tf"]
+	for _, want := range []string{
+		"  description = \"Deployment environment\"\n  type        = string\n\n  validation {\n    condition     = contains([\"dev\", \"staging\", \"prod\"], var.environment)\n    error_message = \"The environment must be one of: dev, staging, prod.\"\n  }\n}",
+		"  description = \"Number of instances\"\n  type = number\n  default     = 2\n  nullable = false\n}",
+		"  type        = map(object({ force_destroy = bool, name = string }))\n",
+		"    condition     = can(cidrhost(var.vpc_cidr, 0))\n    error_message = \"The vpc cidr must be a valid CIDR block.\"\n",
+		"    condition     = var.kms_key_arn == null || can(regex(\"^arn:\", var.kms_key_arn))\n",
+	} {
+		if !strings.Contains(variables, want) {
+			t.Errorf("Expected variables.tf to contain %q, got:\n%s", want, variables)
+		}
+	}
+	if _, err := tfdocs.FormatHCL("variables.tf", variables); err != nil {
+		t.Errorf("Expected the fixed variables.tf to parse: %v", err)
+	}
+}
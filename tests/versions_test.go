@@ -0,0 +1,176 @@
+// tests/versions_test.go
+package tests
+
+import (
+	"testing"
+
+	"terraform-mcp-server/pkg/hashicorp/tfdocs"
+)
+
+func TestVersionConstraintRanges(t *testing.T) {
+	tests := []struct {
+		constraint string
+		bounded    bool
+		exact      bool
+		lower      string
+		upper      string
+	}{
+		{">= 4.0", false, false, "4.0.0", ""},
+		{"~> 4.0", true, false, "4.0.0", "5.0.0"},
+		{"~> 4.2.1", true, false, "4.2.1", "4.3.0"},
+		{">= 1.3, < 2.0", true, false, "1.3.0", "2.0.0"},
+		{"= 1.5.7", true, true, "1.5.7", "1.5.7"},
+		{"1.5.7", true, true, "1.5.7", "1.5.7"},
+	}
+
+	for _, test := range tests {
+		constraints, err := tfdocs.ParseVersionConstraints(test.constraint)
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %v", test.constraint, err)
+		}
+		r := tfdocs.ConstraintRange(constraints)
+		if r.Bounded() != test.bounded || r.Exact() != test.exact {
+			t.Errorf("%q: expected bounded=%v exact=%v", test.constraint, test.bounded, test.exact)
+		}
+		if r.Lower == nil || r.Lower.Version.String() != test.lower {
+			t.Errorf("%q: expected lower bound %s, got %+v", test.constraint, test.lower, r.Lower)
+		}
+		if test.upper != "" && (r.Upper == nil || r.Upper.Version.String() != test.upper) {
+			t.Errorf("%q: expected upper bound %s, got %+v", test.constraint, test.upper, r.Upper)
+		}
+	}
+
+	for _, invalid := range []string{"", "~>", ">= four", "=> 1.0"} {
+		if _, err := tfdocs.ParseVersionConstraints(invalid); err == nil {
+			t.Errorf("Expected %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestVersionValidator(t *testing.T) {
+	config := &tfdocs.TerraformConfiguration{
+		Files: map[string]string{
+			"versions.tf": `terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = ">= 4.0"
+    }
+    random = {
+      source  = "hashicorp/random"
+      version = "= 3.5.1"
+    }
+    null = {
+      source = "hashicorp/null"
+    }
+  }
+}
+`,
+			"providers.tf": `terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "< 4.0"
+    }
+  }
+}
+`,
+			"main.tf": `module "vpc" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = ">= 5.0"
+}
+
+module "network" {
+  source = "git::https://example.com/network.git"
+}
+
+module "dns" {
+  source = "github.com/example/terraform-dns?ref=main"
+}
+
+module "pinned" {
+  source = "git::https://example.com/pinned.git?ref=v1.2.0"
+}
+
+module "short_sha" {
+  source = "git::https://example.com/short.git?ref=a1b2c3d"
+}
+
+module "archive" {
+  source = "https://example.com/modules/storage.zip"
+}
+`,
+		},
+	}
+
+	issues := (&tfdocs.VersionValidator{}).Validate(config)
+	issues = append(issues, (&tfdocs.ModuleValidator{}).Validate(config)...)
+
+	byRule := make(map[string][]tfdocs.ValidationIssue)
+	for _, issue := range issues {
+		byRule[issue.RuleID] = append(byRule[issue.RuleID], issue)
+	}
+
+	if got := byRule["required-version"]; len(got) != 1 || got[0].File != "versions.tf" {
+		t.Errorf("Expected a missing required_version in versions.tf, got %v", got)
+	}
+	// aws >= 4.0, null without a version and module vpc >= 5.0
+	if got := byRule["version-unbounded"]; len(got) != 3 {
+		t.Errorf("Expected 3 unbounded constraints, got %v", got)
+	}
+	if got := byRule["version-exact"]; len(got) != 1 || got[0].Line != 7 {
+		t.Errorf("Expected the exact random pin at versions.tf:7, got %v", got)
+	}
+	if got := byRule["version-conflicts"]; len(got) != 1 || got[0].File != "versions.tf" || got[0].Line != 3 {
+		t.Errorf("Expected aws constraints to conflict at versions.tf:3, got %v", got)
+	}
+	if got := byRule["module-source-unpinned"]; len(got) != 3 {
+		t.Errorf("Expected network, dns and archive sources to be unpinned, got %v", got)
+	}
+	if got := byRule["module-version"]; len(got) != 0 {
+		t.Errorf("Expected versioned registry modules to pass, got %v", got)
+	}
+}
+
+func TestVersionConflictsAcrossModules(t *testing.T) {
+	config := &tfdocs.TerraformConfiguration{
+		Files: map[string]string{
+			"main.tf": `terraform {
+  required_version = "~> 1.5"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+  }
+}
+
+module "vpc" {
+  source = "./modules/vpc"
+}
+`,
+			"modules/vpc/versions.tf": `terraform {
+  required_version = ">= 1.3"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = ">= 4.0, < 5.0"
+    }
+  }
+}
+`,
+		},
+	}
+
+	var conflicts []tfdocs.ValidationIssue
+	for _, issue := range (&tfdocs.ModuleTreeValidator{}).Validate(config) {
+		if issue.RuleID == "version-conflicts" {
+			conflicts = append(conflicts, issue)
+		}
+	}
+	if len(conflicts) != 1 || conflicts[0].File != "modules/vpc/versions.tf" || conflicts[0].Line != 5 {
+		t.Errorf("Expected the aws constraint of modules/vpc to conflict, got %v", conflicts)
+	}
+}
@@ -0,0 +1,125 @@
+// tests/workspace_test.go
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"terraform-mcp-server/pkg/hashicorp/tfdocs"
+)
+
+func TestWorkspaceResolve(t *testing.T) {
+	base := t.TempDir()
+	root := filepath.Join(base, "root")
+	writeTestFiles(t, base, map[string]string{
+		"root/app/main.tf":   "variable \"name\" {}\n",
+		"outside/secrets.tf": "variable \"token\" {}\n",
+	})
+	if err := os.Symlink(filepath.Join(base, "outside"), filepath.Join(root, "escape")); err != nil {
+		t.Skipf("Symbolic links are not supported: %v", err)
+	}
+
+	if _, err := (&tfdocs.Workspace{}).Resolve("app"); err != tfdocs.ErrWorkspaceDisabled {
+		t.Errorf("Expected ErrWorkspaceDisabled, got %v", err)
+	}
+
+	workspace, err := tfdocs.NewWorkspace([]string{root})
+	if err != nil {
+		t.Fatalf("Failed to create workspace: %v", err)
+	}
+
+	dir, err := workspace.Resolve("app")
+	if err != nil {
+		t.Fatalf("Failed to resolve app: %v", err)
+	}
+	if filepath.Base(dir) != "app" {
+		t.Errorf("Expected app directory, got %s", dir)
+	}
+
+	for _, path := range []string{
+		"../outside",
+		"app/../../outside",
+		filepath.Join(base, "outside"),
+		"escape",
+		"app/main.tf",
+	} {
+		if _, err := workspace.Resolve(path); err == nil {
+			t.Errorf("Expected %s to be rejected", path)
+		}
+	}
+
+	if file, err := workspace.ResolveFile("app/main.tf"); err != nil || filepath.Base(file) != "main.tf" {
+		t.Errorf("Expected app/main.tf to resolve to a file, got %s, %v", file, err)
+	}
+	for _, path := range []string{"app", "escape/secrets.tf"} {
+		if _, err := workspace.ResolveFile(path); err == nil {
+			t.Errorf("Expected file %s to be rejected", path)
+		}
+	}
+
+	// Absolute paths through a link to a root resolve like the root did,
+	// e.g. /tmp and /private/tmp on macOS
+	alias := filepath.Join(base, "alias")
+	if err := os.Symlink(root, alias); err != nil {
+		t.Fatalf("Failed to create link: %v", err)
+	}
+	if dir, err := workspace.Resolve(filepath.Join(alias, "app")); err != nil || filepath.Base(dir) != "app" {
+		t.Errorf("Expected the app directory through the link, got %s, %v", dir, err)
+	}
+	if _, err := workspace.Resolve(filepath.Join(alias, "missing")); err == nil || strings.Contains(err.Error(), "outside") {
+		t.Errorf("Expected a missing path through the link to fail to resolve, got %v", err)
+	}
+	if _, err := workspace.Resolve(filepath.Join(alias, "escape")); err == nil {
+		t.Errorf("Expected a link leaving the root to be rejected through the alias")
+	}
+}
+
+func TestDiscoverModulesGitignore(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		"main.tf":                   "variable \"name\" {}\n",
+		".gitignore":                "generated/\n",
+		"generated/main.tf":         "variable \"generated\" {}\n",
+		"modules/db/main.tf":        "variable \"size\" {}\n",
+		"modules/db/.gitignore":     "override.tf\n",
+		"modules/db/override.tf":    "variable \"local\" {}\n",
+		"modules/cache/main.tf":     "variable \"nodes\" {}\n",
+		"modules/cache/.gitignore":  "*\n!*.tf\n",
+		"modules/cache/variable.md": "ignored\n",
+	})
+	if err := os.Symlink(filepath.Join(root, "main.tf"), filepath.Join(root, "modules", "db", "linked.tf")); err != nil {
+		t.Skipf("Symbolic links are not supported: %v", err)
+	}
+
+	modules, err := tfdocs.DiscoverModules(root)
+	if err != nil {
+		t.Fatalf("Failed to discover modules: %v", err)
+	}
+
+	files := make(map[string]bool)
+	for _, module := range modules {
+		for name := range module.Config.Files {
+			files[filepath.ToSlash(filepath.Join(module.Path, name))] = true
+		}
+	}
+	for _, expected := range []string{"main.tf", "modules/db/main.tf", "modules/cache/main.tf"} {
+		if !files[expected] {
+			t.Errorf("Expected %s to be loaded, got %v", expected, files)
+		}
+	}
+	for _, unexpected := range []string{"generated/main.tf", "modules/db/override.tf", "modules/db/linked.tf"} {
+		if files[unexpected] {
+			t.Errorf("Expected %s to be skipped", unexpected)
+		}
+	}
+
+	modules, err = tfdocs.DiscoverModules(root, tfdocs.WithRecursion(false))
+	if err != nil {
+		t.Fatalf("Failed to discover modules: %v", err)
+	}
+	if len(modules) != 1 || modules[0].Path != "." {
+		t.Errorf("Expected only the root module without recursion, got %d modules", len(modules))
+	}
+}